@@ -0,0 +1,27 @@
+// Runs the in-memory fake of the Neon API, e.g. to try the provider's examples without the Neon account:
+//
+//	go run ./cmd/fakeapi -addr localhost:8080
+//	NEON_API_BASE_URL=http://localhost:8080/api/v2 NEON_API_KEY=fake terraform apply
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+)
+
+func main() {
+	var (
+		addr string
+		s    = fakeapi.New()
+	)
+	flag.StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	flag.DurationVar(&s.OperationDuration, "operation-duration", fakeapi.DefaultOperationDuration,
+		"duration of the simulated operations")
+	flag.Parse()
+
+	log.Printf("fake Neon API listens on http://%s/api/v2\n", addr)
+	log.Fatalln(http.ListenAndServe(addr, s))
+}
@@ -0,0 +1,181 @@
+// Rewrites the Terraform state to switch the Neon resources from another Neon provider to this provider
+// without re-creation. The state is read from stdin and written to stdout, e.g.
+//
+//	terraform state pull > backup.tfstate
+//	go run ./cmd/migratestate -from registry.terraform.io/acme/neon < backup.tfstate > migrated.tfstate
+//	terraform state push migrated.tfstate
+//
+// The resources' provider address is replaced, and the instances are verified against the provider's schema:
+// the attributes unknown to the provider are dropped and reported, the migration fails if the instance's schema
+// version is newer than the provider's because the provider cannot upgrade such state.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kislerdm/terraform-provider-neon/internal/provider"
+)
+
+func main() {
+	from := flag.String("from", "", "address of the provider to migrate from, e.g. registry.terraform.io/acme/neon")
+	to := flag.String("to", "registry.terraform.io/"+provider.Name, "address of the provider to migrate to")
+	flag.Parse()
+
+	if *from == "" {
+		log.Fatalln("the flag -from is required")
+	}
+
+	warnings, err := migrate(os.Stdin, os.Stdout, provider.New("migratestate"), *from, *to)
+	for _, w := range warnings {
+		log.Println("warning:", w)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// migrate rewrites the state of the version 4, i.e. the format of terraform >= v0.12.
+func migrate(r io.Reader, w io.Writer, p *schema.Provider, from, to string) ([]string, error) {
+	dec := json.NewDecoder(r)
+	// the numbers are kept as is, e.g. to avoid the exponent notation of the large integers
+	dec.UseNumber()
+
+	var state map[string]interface{}
+	if err := dec.Decode(&state); err != nil {
+		return nil, fmt.Errorf("cannot decode the state: %w", err)
+	}
+	if v, _ := state["version"].(json.Number); v.String() != "4" {
+		return nil, errors.New("the state version " + v.String() + " is not supported, the version 4 is expected")
+	}
+
+	fromAddr, toAddr := providerConfigAddr(from), providerConfigAddr(to)
+
+	var (
+		warnings []string
+		cnt      int
+	)
+	resources, _ := state["resources"].([]interface{})
+	for _, el := range resources {
+		res, ok := el.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		addr, _ := res["provider"].(string)
+		if !strings.HasPrefix(addr, fromAddr) {
+			continue
+		}
+
+		typeName, _ := res["type"].(string)
+		name, _ := res["name"].(string)
+		mode, _ := res["mode"].(string)
+		resourceAddr := typeName + "." + name
+		if mode == "data" {
+			resourceAddr = "data." + resourceAddr
+		}
+
+		s := p.ResourcesMap[typeName]
+		if mode == "data" {
+			s = p.DataSourcesMap[typeName]
+		}
+		if s == nil {
+			return warnings, errors.New(resourceAddr + ": the type " + typeName + " is not supported by the provider")
+		}
+
+		instances, _ := res["instances"].([]interface{})
+		for _, inst := range instances {
+			v, ok := inst.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			w, err := migrateInstance(v, s)
+			if err != nil {
+				return warnings, fmt.Errorf("%s: %w", resourceAddr, err)
+			}
+			for _, el := range w {
+				warnings = append(warnings, resourceAddr+": "+el)
+			}
+		}
+
+		// the provider's alias, if any, is preserved
+		res["provider"] = toAddr + strings.TrimPrefix(addr, fromAddr)
+		cnt++
+	}
+
+	if cnt == 0 {
+		return warnings, errors.New("no resources of the provider " + from + " found in the state")
+	}
+
+	if serial, ok := state["serial"].(json.Number); ok {
+		if v, err := serial.Int64(); err == nil {
+			state["serial"] = v + 1
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return warnings, enc.Encode(state)
+}
+
+func providerConfigAddr(addr string) string {
+	return `provider["` + addr + `"]`
+}
+
+// migrateInstance drops the attributes unknown to the resource's schema, and returns the dropped attributes.
+func migrateInstance(v map[string]interface{}, s *schema.Resource) ([]string, error) {
+	if n, ok := v["schema_version"].(json.Number); ok {
+		if version, err := n.Int64(); err == nil && version > int64(s.SchemaVersion) {
+			return nil, fmt.Errorf("the schema version %d is newer than the provider's schema version %d",
+				version, s.SchemaVersion)
+		}
+	}
+
+	attrs, _ := v["attributes"].(map[string]interface{})
+	var dropped []string
+	for k := range attrs {
+		if _, ok := s.Schema[k]; !ok && k != "id" {
+			dropped = append(dropped, k)
+			delete(attrs, k)
+		}
+	}
+	sort.Strings(dropped)
+
+	// the paths of the sensitive values start with the attribute's name, e.g. [{"type":"get_attr","value":"password"}]
+	if paths, ok := v["sensitive_attributes"].([]interface{}); ok && len(dropped) > 0 {
+		kept := make([]interface{}, 0, len(paths))
+		for _, path := range paths {
+			if !isPathOf(path, dropped) {
+				kept = append(kept, path)
+			}
+		}
+		v["sensitive_attributes"] = kept
+	}
+
+	var o []string
+	for _, k := range dropped {
+		o = append(o, "the attribute "+k+" is not supported by the provider, it's dropped")
+	}
+	return o, nil
+}
+
+func isPathOf(path interface{}, attrs []string) bool {
+	steps, ok := path.([]interface{})
+	if !ok || len(steps) == 0 {
+		return false
+	}
+	step, ok := steps[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	name, _ := step["value"].(string)
+	i := sort.SearchStrings(attrs, name)
+	return i < len(attrs) && attrs[i] == name
+}
@@ -0,0 +1,109 @@
+//go:build !acceptance
+// +build !acceptance
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kislerdm/terraform-provider-neon/internal/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+const stateFixture = `{
+  "version": 4,
+  "serial": 7,
+  "lineage": "foo",
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "neon_role",
+      "name": "this",
+      "provider": "provider[\"registry.terraform.io/acme/neon\"].dev",
+      "instances": [
+        {
+          "schema_version": 7,
+          "attributes": {
+            "id": "shiny-cell-31746257/br-foo/bar",
+            "project_id": "shiny-cell-31746257",
+            "branch_id": "br-foo",
+            "name": "bar",
+            "password": "secret",
+            "login": "bar"
+          },
+          "sensitive_attributes": [
+            [{"type": "get_attr", "value": "password"}],
+            [{"type": "get_attr", "value": "login"}]
+          ]
+        }
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "this",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": []
+    }
+  ]
+}`
+
+func Test_migrate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	t.Run("shall rewrite the provider's address and drop the unknown attributes", func(t *testing.T) {
+		// GIVEN
+		var out bytes.Buffer
+
+		// WHEN
+		warnings, err := migrate(strings.NewReader(stateFixture), &out, provider.New("test"),
+			"registry.terraform.io/acme/neon", "registry.terraform.io/kislerdm/neon")
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, []string{
+			"neon_role.this: the attribute login is not supported by the provider, it's dropped",
+		}, warnings)
+
+		var got struct {
+			Serial    int `json:"serial"`
+			Resources []struct {
+				Provider  string `json:"provider"`
+				Instances []struct {
+					Attributes          map[string]interface{} `json:"attributes"`
+					SensitiveAttributes []interface{}          `json:"sensitive_attributes"`
+				} `json:"instances"`
+			} `json:"resources"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 8, got.Serial)
+		assert.Equal(t, `provider["registry.terraform.io/kislerdm/neon"].dev`, got.Resources[0].Provider)
+		assert.Equal(t, `provider["registry.terraform.io/hashicorp/aws"]`, got.Resources[1].Provider)
+		assert.NotContains(t, got.Resources[0].Instances[0].Attributes, "login")
+		assert.Equal(t, "secret", got.Resources[0].Instances[0].Attributes["password"])
+		assert.Len(t, got.Resources[0].Instances[0].SensitiveAttributes, 1)
+	})
+
+	t.Run("shall fail if the schema version is newer than the provider's", func(t *testing.T) {
+		state := strings.Replace(stateFixture, `"schema_version": 7`, `"schema_version": 99`, 1)
+		_, err := migrate(strings.NewReader(state), &bytes.Buffer{}, provider.New("test"),
+			"registry.terraform.io/acme/neon", "registry.terraform.io/kislerdm/neon")
+		assert.ErrorContains(t, err, "neon_role.this: the schema version 99 is newer")
+	})
+
+	t.Run("shall fail if no resources of the provider found", func(t *testing.T) {
+		_, err := migrate(strings.NewReader(stateFixture), &bytes.Buffer{}, provider.New("test"),
+			"registry.terraform.io/foo/neon", "registry.terraform.io/kislerdm/neon")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,3 @@
+output "restore_point_is_head" {
+  value = provider::neon::lsn_compare(var.restore_lsn, var.head_lsn) == 0
+}
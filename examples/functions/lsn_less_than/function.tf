@@ -0,0 +1,6 @@
+check "restore_point" {
+  assert {
+    condition     = provider::neon::lsn_less_than(var.restore_lsn, var.head_lsn)
+    error_message = "The restore point must precede the branch's head."
+  }
+}
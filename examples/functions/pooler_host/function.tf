@@ -0,0 +1,3 @@
+output "pooler_host" {
+  value = provider::neon::pooler_host(neon_endpoint.example.host)
+}
@@ -0,0 +1,3 @@
+output "connection_uri" {
+  value = nonsensitive(provider::neon::redact_uri(neon_project.example.connection_uri))
+}
@@ -0,0 +1,12 @@
+resource "time_rotating" "this" {
+  rotation_days = 30
+}
+
+resource "neon_api_key" "this" {
+  name = "ci"
+
+  # the key is rotated every 30 days
+  rotation_triggered_by = {
+    rotation = time_rotating.this.id
+  }
+}
@@ -25,3 +25,17 @@ resource "neon_branch" "child" {
   parent_id  = neon_branch.parent.id
   name       = "bar"
 }
+
+### keep the branch in Neon when it's removed from the configuration
+resource "neon_branch" "analytics" {
+  project_id  = neon_project.example.id
+  name        = "analytics"
+  skip_delete = true
+}
+
+### create a branch off of a parent branch defined by its name
+resource "neon_branch" "child_of_main" {
+  project_id  = neon_project.example.id
+  parent_name = "main"
+  name        = "qux"
+}
@@ -0,0 +1,9 @@
+resource "neon_project" "example" {
+  name = "foo"
+}
+
+# the release branches are protected, including the branches created outside terraform by the next apply
+resource "neon_branch_protection_rule" "release" {
+  project_id = neon_project.example.id
+  pattern    = "release/*"
+}
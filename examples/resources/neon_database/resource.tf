@@ -19,3 +19,11 @@ resource "neon_database" "example" {
   name       = "qux"
   owner_name = neon_role.example.name
 }
+
+### create a database on the branch defined by its name
+resource "neon_database" "default_branch" {
+  project_id  = neon_project.example.id
+  branch_name = "main"
+  name        = "quux"
+  owner_name  = "neondb_owner"
+}
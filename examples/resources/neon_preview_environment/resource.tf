@@ -0,0 +1,16 @@
+resource "neon_project" "example" {
+  name = "foo"
+}
+
+# the preview database of the pull request #42
+resource "neon_preview_environment" "pr_42" {
+  project_id    = neon_project.example.id
+  name          = "pr-42"
+  role_name     = "app"
+  database_name = "app"
+}
+
+output "database_url" {
+  value     = neon_preview_environment.pr_42.connection_uri
+  sensitive = true
+}
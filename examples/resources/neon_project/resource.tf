@@ -67,3 +67,51 @@ resource "neon_project" "example_in_org" {
   name   = "myproject"
   org_id = "org-restless-silence-28866559"
 }
+
+### Manage the default branch, database and role created together with the project
+# Note that the import IDs are known once the project is created, i.e. the import blocks are added afterwards
+resource "neon_project" "example" {
+  name = "foo"
+}
+
+import {
+  to = neon_role.owner
+  id = neon_project.example.default_role_id
+}
+
+resource "neon_role" "owner" {
+  project_id = neon_project.example.id
+  branch_id  = neon_project.example.default_branch_id
+  name       = neon_project.example.database_user
+}
+
+import {
+  to = neon_database.default
+  id = neon_project.example.default_database_id
+}
+
+resource "neon_database" "default" {
+  project_id = neon_project.example.id
+  branch_id  = neon_project.example.default_branch_id
+  name       = neon_project.example.database_name
+  owner_name = neon_role.owner.name
+}
+
+### Create project in the region which is not listed by the API, e.g. the early-access region
+resource "neon_project" "example_in_private_region" {
+  name                   = "myproject"
+  region_id              = "aws-private-region-1"
+  skip_region_validation = true
+}
+
+### Let the autoscaling limits be tuned in the Neon console after the project is created
+resource "neon_project" "example_with_external_autoscaling" {
+  name = "myproject"
+
+  default_endpoint_settings {
+    autoscaling_limit_min_cu = 0.25
+    autoscaling_limit_max_cu = 1
+  }
+
+  ignore_external_changes = ["autoscaling"]
+}
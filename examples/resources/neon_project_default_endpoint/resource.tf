@@ -0,0 +1,12 @@
+resource "neon_project" "example" {
+  name = "foo"
+}
+
+# the endpoint created together with the project is adopted without the import
+resource "neon_project_default_endpoint" "example" {
+  project_id               = neon_project.example.id
+  autoscaling_limit_min_cu = 0.25
+  autoscaling_limit_max_cu = 2
+  suspend_timeout_seconds  = 300
+  pooler_enabled           = true
+}
@@ -0,0 +1,13 @@
+resource "neon_project" "example" {
+  name = "foo"
+
+  lifecycle {
+    ignore_changes = [org_id]
+  }
+}
+
+# transfer the project from the personal account to the organization org-foo-12345678
+resource "neon_project_transfer" "example" {
+  project_id = neon_project.example.id
+  org_id     = "org-foo-12345678"
+}
@@ -12,3 +12,10 @@ resource "neon_role" "example" {
   branch_id  = neon_branch.example.id
   name       = "qux"
 }
+
+### create a role on the branch defined by its name
+resource "neon_role" "default_branch" {
+  project_id  = neon_project.example.id
+  branch_name = "main"
+  name        = "quux"
+}
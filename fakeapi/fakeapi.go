@@ -0,0 +1,1116 @@
+// Package fakeapi defines the in-memory fake of the Neon API.
+// It serves the projects, branches, endpoints, databases and roles endpoints used by the provider,
+// and simulates the asynchronous operations: the project is locked while its operations are running.
+//
+// The fake can be used to test the Go code which calls the Neon API without the Neon account, e.g.
+//
+//	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &http.Client{Transport: fakeapi.New()}})
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// DefaultOperationDuration defines how long the operations run by default.
+const DefaultOperationDuration = 100 * time.Millisecond
+
+// Server the fake Neon API.
+type Server struct {
+	// OperationDuration defines how long the operations run.
+	// The mutating requests are rejected with the status code 423 while the project has running operations.
+	OperationDuration time.Duration
+
+	// Now returns the current time, it's overridden in tests.
+	Now func() time.Time
+
+	// StateFile defines the path to the file the state is persisted to, e.g. to share the fake resources
+	// between the processes which serve the API one after another. The state is kept in memory if not set.
+	StateFile string
+
+	mu       sync.Mutex
+	seq      int
+	projects []*project
+}
+
+type project struct {
+	neon.Project
+	branches   []*branch
+	endpoints  []*neon.Endpoint
+	operations []neon.Operation
+}
+
+type branch struct {
+	neon.Branch
+	annotations neon.AnnotationValueData
+	databases   []*neon.Database
+	roles       []*neon.Role
+	passwords   map[string]string
+}
+
+// New initializes the fake API.
+func New() *Server {
+	return &Server{
+		OperationDuration: DefaultOperationDuration,
+		Now:               time.Now,
+	}
+}
+
+type errorResp struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiError struct {
+	code int
+	msg  string
+}
+
+func (e apiError) Error() string {
+	return e.msg
+}
+
+func errNotFound(kind, id string) error {
+	return apiError{code: http.StatusNotFound, msg: fmt.Sprintf("%s %s not found", kind, id)}
+}
+
+func errBadRequest(format string, a ...interface{}) error {
+	return apiError{code: http.StatusBadRequest, msg: fmt.Sprintf(format, a...)}
+}
+
+var errLocked = apiError{code: http.StatusLocked, msg: "project already has running operations, scheduling of new ones is prohibited"}
+
+// ServeHTTP serves the API request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, err := s.serve(r)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if e, ok := err.(apiError); ok {
+			code = e.code
+		}
+		writeJSON(w, code, errorResp{Code: http.StatusText(code), Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// RoundTrip serves the API request in-process, i.e. the server can be used as the transport of the HTTP client.
+func (s *Server) RoundTrip(r *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	o := w.Result()
+	o.Request = r
+	return o, nil
+}
+
+// serve routes the request given the state persisted to StateFile, and persists the mutated state.
+func (s *Server) serve(r *http.Request) (interface{}, error) {
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("cannot load the state: %w", err)
+	}
+
+	v, err := s.route(r)
+	if err != nil || r.Method == http.MethodGet {
+		return v, err
+	}
+
+	if err := s.save(); err != nil {
+		return nil, fmt.Errorf("cannot save the state: %w", err)
+	}
+	return v, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errBadRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) route(r *http.Request) (interface{}, error) {
+	path := strings.Trim(r.URL.Path, "/")
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "api/v2"), "/")
+	seg := strings.Split(path, "/")
+
+	if seg[0] != "projects" {
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	if len(seg) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listProjects(), nil
+		case http.MethodPost:
+			return s.createProject(r)
+		}
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	p, err := s.project(seg[1])
+	if err != nil {
+		return nil, err
+	}
+
+	locked := r.Method != http.MethodGet && s.isLocked(p)
+
+	switch {
+	case len(seg) == 2:
+		switch r.Method {
+		case http.MethodGet:
+			return neon.ProjectResponse{Project: p.Project}, nil
+		case http.MethodPatch:
+			if locked {
+				return nil, errLocked
+			}
+			return s.updateProject(p, r)
+		case http.MethodDelete:
+			return s.deleteProject(p), nil
+		}
+
+	case len(seg) == 3 && seg[2] == "operations" && r.Method == http.MethodGet:
+		return s.listOperations(p), nil
+
+	case len(seg) == 4 && seg[2] == "operations" && r.Method == http.MethodGet:
+		return s.getOperation(p, seg[3])
+
+	case len(seg) == 3 && seg[2] == "connection_uri" && r.Method == http.MethodGet:
+		return s.connectionURI(p, r)
+
+	case seg[2] == "branches":
+		return s.routeBranches(p, seg[3:], r, locked)
+
+	case seg[2] == "endpoints":
+		return s.routeEndpoints(p, seg[3:], r, locked)
+	}
+
+	return nil, errNotFound("route", r.URL.Path)
+}
+
+func (s *Server) routeBranches(p *project, seg []string, r *http.Request, locked bool) (interface{}, error) {
+	if locked {
+		return nil, errLocked
+	}
+
+	if len(seg) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listBranches(p), nil
+		case http.MethodPost:
+			return s.createBranch(p, r)
+		}
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	b, err := p.branch(seg[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(seg) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			var v neon.GetProjectBranchRespObj
+			v.Branch = b.Branch
+			v.Annotation = neon.AnnotationData{
+				Object: neon.AnnotationObjectData{ID: b.ID, Type: "console/branch"},
+				Value:  b.annotations,
+			}
+			return v, nil
+		case http.MethodPatch:
+			return s.updateBranch(p, b, r)
+		case http.MethodDelete:
+			return s.deleteBranch(p, b)
+		}
+
+	case seg[1] == "endpoints" && len(seg) == 2 && r.Method == http.MethodGet:
+		return neon.EndpointsResponse{Endpoints: p.branchEndpoints(b.ID)}, nil
+
+	case seg[1] == "databases":
+		return s.routeDatabases(p, b, seg[2:], r)
+
+	case seg[1] == "roles":
+		return s.routeRoles(p, b, seg[2:], r)
+	}
+
+	return nil, errNotFound("route", r.URL.Path)
+}
+
+func (s *Server) routeEndpoints(p *project, seg []string, r *http.Request, locked bool) (interface{}, error) {
+	if locked {
+		return nil, errLocked
+	}
+
+	if len(seg) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return neon.EndpointsResponse{Endpoints: p.branchEndpoints("")}, nil
+		case http.MethodPost:
+			return s.createEndpoint(p, r)
+		}
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	e, err := p.endpoint(seg[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(seg) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			return neon.EndpointResponse{Endpoint: *e}, nil
+		case http.MethodPatch:
+			return s.updateEndpoint(p, e, r)
+		case http.MethodDelete:
+			return s.deleteEndpoint(p, e), nil
+		}
+
+	case len(seg) == 2 && seg[1] == "start" && r.Method == http.MethodPost:
+		e.CurrentState = neon.EndpointStateActive
+		return neon.EndpointOperations{
+			EndpointResponse:   neon.EndpointResponse{Endpoint: *e},
+			OperationsResponse: s.newOperations(p, neon.OperationActionStartCompute, &e.BranchID, &e.ID),
+		}, nil
+
+	case len(seg) == 2 && seg[1] == "restart" && r.Method == http.MethodPost:
+		e.CurrentState = neon.EndpointStateActive
+		return neon.EndpointOperations{
+			EndpointResponse:   neon.EndpointResponse{Endpoint: *e},
+			OperationsResponse: s.newOperations(p, neon.OperationActionSuspendCompute, &e.BranchID, &e.ID),
+		}, nil
+	}
+
+	return nil, errNotFound("route", r.URL.Path)
+}
+
+func (s *Server) routeDatabases(p *project, b *branch, seg []string, r *http.Request) (interface{}, error) {
+	if len(seg) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return neon.DatabasesResponse{Databases: b.listDatabases()}, nil
+		case http.MethodPost:
+			var req neon.DatabaseCreateRequest
+			if err := readJSON(r, &req); err != nil {
+				return nil, err
+			}
+			db, err := s.addDatabase(b, req.Database.Name, req.Database.OwnerName)
+			if err != nil {
+				return nil, err
+			}
+			return neon.DatabaseOperations{
+				DatabaseResponse:   neon.DatabaseResponse{Database: *db},
+				OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+			}, nil
+		}
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	db, i := b.database(seg[0])
+	if db == nil {
+		return nil, errNotFound("database", seg[0])
+	}
+
+	if len(seg) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return neon.DatabaseResponse{Database: *db}, nil
+		case http.MethodPatch:
+			var req neon.DatabaseUpdateRequest
+			if err := readJSON(r, &req); err != nil {
+				return nil, err
+			}
+			if req.Database.Name != nil {
+				db.Name = *req.Database.Name
+			}
+			if req.Database.OwnerName != nil {
+				db.OwnerName = *req.Database.OwnerName
+			}
+			db.UpdatedAt = s.Now().UTC()
+			return neon.DatabaseOperations{
+				DatabaseResponse:   neon.DatabaseResponse{Database: *db},
+				OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+			}, nil
+		case http.MethodDelete:
+			b.databases = append(b.databases[:i], b.databases[i+1:]...)
+			return neon.DatabaseOperations{
+				DatabaseResponse:   neon.DatabaseResponse{Database: *db},
+				OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+			}, nil
+		}
+	}
+
+	return nil, errNotFound("route", r.URL.Path)
+}
+
+func (s *Server) routeRoles(p *project, b *branch, seg []string, r *http.Request) (interface{}, error) {
+	if len(seg) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return neon.RolesResponse{Roles: b.listRoles()}, nil
+		case http.MethodPost:
+			var req neon.RoleCreateRequest
+			if err := readJSON(r, &req); err != nil {
+				return nil, err
+			}
+			role, err := s.addRole(b, req.Role.Name)
+			if err != nil {
+				return nil, err
+			}
+			v := *role
+			v.Password = stringPtr(b.passwords[role.Name])
+			return neon.RoleOperations{
+				RoleResponse:       neon.RoleResponse{Role: v},
+				OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+			}, nil
+		}
+		return nil, errNotFound("route", r.URL.Path)
+	}
+
+	role, i := b.role(seg[0])
+	if role == nil {
+		return nil, errNotFound("role", seg[0])
+	}
+
+	switch {
+	case len(seg) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			return neon.RoleResponse{Role: *role}, nil
+		case http.MethodDelete:
+			b.roles = append(b.roles[:i], b.roles[i+1:]...)
+			delete(b.passwords, role.Name)
+			return neon.RoleOperations{
+				RoleResponse:       neon.RoleResponse{Role: *role},
+				OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+			}, nil
+		}
+
+	case len(seg) == 2 && seg[1] == "reveal_password" && r.Method == http.MethodGet:
+		return neon.RolePasswordResponse{Password: b.passwords[role.Name]}, nil
+	}
+
+	return nil, errNotFound("route", r.URL.Path)
+}
+
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-fake-%06d", prefix, s.seq)
+}
+
+func (s *Server) project(id string) (*project, error) {
+	for _, p := range s.projects {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, errNotFound("project", id)
+}
+
+func (s *Server) isLocked(p *project) bool {
+	now := s.Now()
+	for _, o := range p.operations {
+		if now.Before(o.CreatedAt.Add(s.OperationDuration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// newOperations registers the operation and returns it; the operation finishes after OperationDuration.
+func (s *Server) newOperations(p *project, action neon.OperationAction, branchID, endpointID *string) neon.OperationsResponse {
+	now := s.Now().UTC()
+	o := neon.Operation{
+		Action:     action,
+		BranchID:   branchID,
+		CreatedAt:  now,
+		EndpointID: endpointID,
+		ID:         s.nextID("op"),
+		ProjectID:  p.ID,
+		Status:     neon.OperationStatusRunning,
+		UpdatedAt:  now,
+	}
+	p.operations = append(p.operations, o)
+	return neon.OperationsResponse{Operations: []neon.Operation{o}}
+}
+
+func (s *Server) listOperations(p *project) neon.ListOperations {
+	now := s.Now()
+	o := make([]neon.Operation, len(p.operations))
+	for i, v := range p.operations {
+		o[len(o)-1-i] = s.operationAt(v, now)
+	}
+	return neon.ListOperations{OperationsResponse: neon.OperationsResponse{Operations: o}}
+}
+
+func (s *Server) getOperation(p *project, id string) (interface{}, error) {
+	for _, v := range p.operations {
+		if v.ID == id {
+			return neon.OperationResponse{Operation: s.operationAt(v, s.Now())}, nil
+		}
+	}
+	return nil, errNotFound("operation", id)
+}
+
+// operationAt returns the operation's status at the moment now.
+func (s *Server) operationAt(v neon.Operation, now time.Time) neon.Operation {
+	if end := v.CreatedAt.Add(s.OperationDuration); !now.Before(end) {
+		v.Status = neon.OperationStatusFinished
+		v.UpdatedAt = end
+		v.TotalDurationMs = int32(s.OperationDuration.Milliseconds())
+	}
+	return v
+}
+
+func (s *Server) listProjects() neon.ListProjectsRespObj {
+	o := make([]neon.ProjectListItem, len(s.projects))
+	for i, p := range s.projects {
+		o[i] = neon.ProjectListItem{
+			CreatedAt:               p.CreatedAt,
+			DefaultEndpointSettings: p.DefaultEndpointSettings,
+			ID:                      p.ID,
+			Name:                    p.Name,
+			OrgID:                   p.OrgID,
+			OwnerID:                 p.OwnerID,
+			PgVersion:               p.PgVersion,
+			PlatformID:              p.PlatformID,
+			Provisioner:             p.Provisioner,
+			ProxyHost:               p.ProxyHost,
+			RegionID:                p.RegionID,
+			Settings:                p.Settings,
+			StorePasswords:          p.StorePasswords,
+			UpdatedAt:               p.UpdatedAt,
+		}
+	}
+	var v neon.ListProjectsRespObj
+	v.Projects = o
+	return v
+}
+
+func (s *Server) createProject(r *http.Request) (interface{}, error) {
+	var req neon.ProjectCreateRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	cfg := req.Project
+
+	now := s.Now().UTC()
+	p := &project{
+		Project: neon.Project{
+			CreatedAt:               now,
+			CreationSource:          "fakeapi",
+			DefaultEndpointSettings: cfg.DefaultEndpointSettings,
+			HistoryRetentionSeconds: 86400,
+			ID:                      s.nextID("project"),
+			Name:                    stringValue(cfg.Name, "main"),
+			OrgID:                   cfg.OrgID,
+			OwnerID:                 "fake-owner",
+			PgVersion:               16,
+			PlatformID:              "aws",
+			Provisioner:             "k8s-neonvm",
+			RegionID:                stringValue(cfg.RegionID, "aws-us-east-2"),
+			Settings:                cfg.Settings,
+			StorePasswords:          true,
+			UpdatedAt:               now,
+		},
+	}
+	p.ProxyHost = p.RegionID + ".aws.neon.tech"
+	if cfg.PgVersion != nil {
+		p.PgVersion = *cfg.PgVersion
+	}
+	if cfg.HistoryRetentionSeconds != nil {
+		p.HistoryRetentionSeconds = *cfg.HistoryRetentionSeconds
+	}
+	if cfg.StorePasswords != nil {
+		p.StorePasswords = *cfg.StorePasswords
+	}
+	if cfg.Provisioner != nil {
+		p.Provisioner = *cfg.Provisioner
+	}
+
+	branchName, dbName, roleName := "main", "neondb", ""
+	if cfg.Branch != nil {
+		branchName = stringValue(cfg.Branch.Name, branchName)
+		dbName = stringValue(cfg.Branch.DatabaseName, dbName)
+		roleName = stringValue(cfg.Branch.RoleName, roleName)
+	}
+	if roleName == "" {
+		roleName = dbName + "_owner"
+	}
+
+	b := s.newBranch(p, branchName, nil)
+	b.Default = true
+	b.Primary = boolPtr(true)
+
+	role, _ := s.addRole(b, roleName)
+	db, _ := s.addDatabase(b, dbName, roleName)
+
+	e := s.newEndpoint(p, b.ID, neon.EndpointTypeReadWrite)
+	if v := cfg.DefaultEndpointSettings; v != nil {
+		if v.AutoscalingLimitMinCu != nil {
+			e.AutoscalingLimitMinCu = *v.AutoscalingLimitMinCu
+		}
+		if v.AutoscalingLimitMaxCu != nil {
+			e.AutoscalingLimitMaxCu = *v.AutoscalingLimitMaxCu
+		}
+		if v.SuspendTimeoutSeconds != nil {
+			e.SuspendTimeoutSeconds = *v.SuspendTimeoutSeconds
+		}
+	}
+
+	s.projects = append(s.projects, p)
+	ops := s.newOperations(p, neon.OperationActionCreateTimeline, &b.ID, nil)
+	ops.Operations = append(ops.Operations, s.newOperations(p, neon.OperationActionStartCompute, &b.ID, &e.ID).Operations...)
+
+	withPass := *role
+	withPass.Password = stringPtr(b.passwords[role.Name])
+
+	var v neon.CreatedProject
+	v.Project = p.Project
+	v.Branch = b.Branch
+	v.Databases = []neon.Database{*db}
+	v.Roles = []neon.Role{withPass}
+	v.Endpoints = []neon.Endpoint{*e}
+	v.Operations = ops.Operations
+	v.ConnectionURIs = []neon.ConnectionDetails{
+		{
+			ConnectionURI: connectionURI(role.Name, b.passwords[role.Name], e.Host, db.Name),
+			ConnectionParameters: neon.ConnectionParameters{
+				Database:   db.Name,
+				Host:       e.Host,
+				Password:   b.passwords[role.Name],
+				PoolerHost: poolerHost(e.Host),
+				Role:       role.Name,
+			},
+		},
+	}
+	return v, nil
+}
+
+func (s *Server) updateProject(p *project, r *http.Request) (interface{}, error) {
+	var req neon.ProjectUpdateRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	cfg := req.Project
+	if cfg.Name != nil {
+		p.Name = *cfg.Name
+	}
+	if cfg.HistoryRetentionSeconds != nil {
+		p.HistoryRetentionSeconds = *cfg.HistoryRetentionSeconds
+	}
+	if cfg.Settings != nil {
+		p.Settings = cfg.Settings
+	}
+	if cfg.DefaultEndpointSettings != nil {
+		p.DefaultEndpointSettings = cfg.DefaultEndpointSettings
+	}
+	p.UpdatedAt = s.Now().UTC()
+
+	var v neon.UpdateProjectRespObj
+	v.Project = p.Project
+	v.Operations = s.newOperations(p, neon.OperationActionApplyConfig, nil, nil).Operations
+	return v, nil
+}
+
+func (s *Server) deleteProject(p *project) neon.ProjectResponse {
+	for i, v := range s.projects {
+		if v == p {
+			s.projects = append(s.projects[:i], s.projects[i+1:]...)
+			break
+		}
+	}
+	return neon.ProjectResponse{Project: p.Project}
+}
+
+func (s *Server) connectionURI(p *project, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	b, err := p.branch(q.Get("branch_id"))
+	if err != nil {
+		return nil, err
+	}
+
+	host := ""
+	if e := p.branchEndpoints(b.ID); len(e) > 0 {
+		host = e[0].Host
+	}
+	if id := q.Get("endpoint_id"); id != "" {
+		e, err := p.endpoint(id)
+		if err != nil {
+			return nil, err
+		}
+		host = e.Host
+	}
+	if q.Get("pooled") == "true" {
+		host = poolerHost(host)
+	}
+
+	role := q.Get("role_name")
+	return neon.ConnectionURIResponse{URI: connectionURI(role, b.passwords[role], host, q.Get("database_name"))}, nil
+}
+
+func (s *Server) newBranch(p *project, name string, parent *branch) *branch {
+	now := s.Now().UTC()
+	b := &branch{
+		Branch: neon.Branch{
+			CreatedAt:      now,
+			CreationSource: "fakeapi",
+			CurrentState:   "ready",
+			ID:             s.nextID("br"),
+			Name:           name,
+			ProjectID:      p.ID,
+			StateChangedAt: now,
+			UpdatedAt:      now,
+		},
+		passwords: map[string]string{},
+	}
+	if parent != nil {
+		b.ParentID = stringPtr(parent.ID)
+		for _, v := range parent.roles {
+			r := *v
+			r.BranchID = b.ID
+			b.roles = append(b.roles, &r)
+			b.passwords[r.Name] = parent.passwords[r.Name]
+		}
+		for _, v := range parent.databases {
+			db := *v
+			db.BranchID = b.ID
+			b.databases = append(b.databases, &db)
+		}
+	}
+	p.branches = append(p.branches, b)
+	return b
+}
+
+func (s *Server) listBranches(p *project) neon.ListProjectBranchesRespObj {
+	o := make([]neon.Branch, len(p.branches))
+	for i, b := range p.branches {
+		o[i] = b.Branch
+	}
+	var v neon.ListProjectBranchesRespObj
+	v.Branches = o
+	return v
+}
+
+func (s *Server) createBranch(p *project, r *http.Request) (interface{}, error) {
+	var req neon.CreateProjectBranchReqObj
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	parent, name := p.defaultBranch(), ""
+	if cfg := req.Branch; cfg != nil {
+		if cfg.ParentID != nil {
+			v, err := p.branch(*cfg.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			parent = v
+		}
+		name = stringValue(cfg.Name, name)
+	}
+
+	b := s.newBranch(p, name, parent)
+	if b.Name == "" {
+		b.Name = b.ID
+	}
+	if cfg := req.Branch; cfg != nil {
+		b.ParentLsn = cfg.ParentLsn
+		b.ParentTimestamp = cfg.ParentTimestamp
+		if cfg.Protected != nil {
+			b.Protected = *cfg.Protected
+		}
+	}
+	if req.AnnotationValue != nil {
+		b.annotations = *req.AnnotationValue
+	}
+
+	ops := s.newOperations(p, neon.OperationActionCreateTimeline, &b.ID, nil)
+
+	var endpoints []neon.Endpoint
+	if req.Endpoints != nil {
+		for _, cfg := range *req.Endpoints {
+			e := s.newEndpoint(p, b.ID, cfg.Type)
+			if cfg.AutoscalingLimitMinCu != nil {
+				e.AutoscalingLimitMinCu = *cfg.AutoscalingLimitMinCu
+			}
+			if cfg.AutoscalingLimitMaxCu != nil {
+				e.AutoscalingLimitMaxCu = *cfg.AutoscalingLimitMaxCu
+			}
+			if cfg.SuspendTimeoutSeconds != nil {
+				e.SuspendTimeoutSeconds = *cfg.SuspendTimeoutSeconds
+			}
+			endpoints = append(endpoints, *e)
+			ops.Operations = append(ops.Operations,
+				s.newOperations(p, neon.OperationActionStartCompute, &b.ID, &e.ID).Operations...)
+		}
+	}
+
+	var v neon.CreatedBranch
+	v.Branch = b.Branch
+	v.Endpoints = endpoints
+	v.Databases = b.listDatabases()
+	v.Roles = b.listRoles()
+	v.Operations = ops.Operations
+	return v, nil
+}
+
+func (s *Server) updateBranch(p *project, b *branch, r *http.Request) (interface{}, error) {
+	var req neon.BranchUpdateRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Branch.Name != nil {
+		b.Name = *req.Branch.Name
+	}
+	if req.Branch.Protected != nil {
+		b.Protected = *req.Branch.Protected
+	}
+	b.UpdatedAt = s.Now().UTC()
+
+	return neon.BranchOperations{
+		BranchResponse:     neon.BranchResponse{Branch: b.Branch},
+		OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &b.ID, nil),
+	}, nil
+}
+
+func (s *Server) deleteBranch(p *project, b *branch) (interface{}, error) {
+	if b.Default {
+		return nil, errBadRequest("cannot delete the default branch %s", b.ID)
+	}
+	for _, v := range p.branches {
+		if v.ParentID != nil && *v.ParentID == b.ID {
+			return nil, errBadRequest("branch %s has children", b.ID)
+		}
+	}
+
+	for i, v := range p.branches {
+		if v == b {
+			p.branches = append(p.branches[:i], p.branches[i+1:]...)
+			break
+		}
+	}
+	var endpoints []*neon.Endpoint
+	for _, e := range p.endpoints {
+		if e.BranchID != b.ID {
+			endpoints = append(endpoints, e)
+		}
+	}
+	p.endpoints = endpoints
+
+	return neon.BranchOperations{
+		BranchResponse:     neon.BranchResponse{Branch: b.Branch},
+		OperationsResponse: s.newOperations(p, neon.OperationActionDeleteTimeline, &b.ID, nil),
+	}, nil
+}
+
+func (s *Server) addRole(b *branch, name string) (*neon.Role, error) {
+	if name == "" {
+		return nil, errBadRequest("role name must be set")
+	}
+	if v, _ := b.role(name); v != nil {
+		return nil, apiError{code: http.StatusConflict, msg: fmt.Sprintf("role %s already exists", name)}
+	}
+	now := s.Now().UTC()
+	v := &neon.Role{
+		BranchID:  b.ID,
+		CreatedAt: now,
+		Name:      name,
+		Protected: boolPtr(false),
+		UpdatedAt: now,
+	}
+	b.roles = append(b.roles, v)
+	b.passwords[name] = s.nextID("pass")
+	return v, nil
+}
+
+func (s *Server) addDatabase(b *branch, name, owner string) (*neon.Database, error) {
+	if name == "" {
+		return nil, errBadRequest("database name must be set")
+	}
+	if v, _ := b.database(name); v != nil {
+		return nil, apiError{code: http.StatusConflict, msg: fmt.Sprintf("database %s already exists", name)}
+	}
+	if v, _ := b.role(owner); v == nil {
+		return nil, errBadRequest("role %s not found", owner)
+	}
+	now := s.Now().UTC()
+	s.seq++
+	v := &neon.Database{
+		BranchID:  b.ID,
+		CreatedAt: now,
+		ID:        int64(s.seq),
+		Name:      name,
+		OwnerName: owner,
+		UpdatedAt: now,
+	}
+	b.databases = append(b.databases, v)
+	return v, nil
+}
+
+func (s *Server) newEndpoint(p *project, branchID string, t neon.EndpointType) *neon.Endpoint {
+	now := s.Now().UTC()
+	id := s.nextID("ep")
+	e := &neon.Endpoint{
+		AutoscalingLimitMaxCu: 0.25,
+		AutoscalingLimitMinCu: 0.25,
+		BranchID:              branchID,
+		CreatedAt:             now,
+		CreationSource:        "fakeapi",
+		CurrentState:          neon.EndpointStateIdle,
+		Host:                  id + "." + p.ProxyHost,
+		ID:                    id,
+		PoolerMode:            "transaction",
+		ProjectID:             p.ID,
+		Provisioner:           p.Provisioner,
+		ProxyHost:             p.ProxyHost,
+		RegionID:              p.RegionID,
+		Type:                  t,
+		UpdatedAt:             now,
+	}
+	p.endpoints = append(p.endpoints, e)
+	return e
+}
+
+func (s *Server) createEndpoint(p *project, r *http.Request) (interface{}, error) {
+	var req neon.EndpointCreateRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	cfg := req.Endpoint
+
+	if _, err := p.branch(cfg.BranchID); err != nil {
+		return nil, err
+	}
+	if cfg.Type == neon.EndpointTypeReadWrite {
+		for _, e := range p.branchEndpoints(cfg.BranchID) {
+			if e.Type == neon.EndpointTypeReadWrite {
+				return nil, errBadRequest("read_write endpoint already exists for the branch %s", cfg.BranchID)
+			}
+		}
+	}
+
+	e := s.newEndpoint(p, cfg.BranchID, cfg.Type)
+	if cfg.AutoscalingLimitMinCu != nil {
+		e.AutoscalingLimitMinCu = *cfg.AutoscalingLimitMinCu
+	}
+	if cfg.AutoscalingLimitMaxCu != nil {
+		e.AutoscalingLimitMaxCu = *cfg.AutoscalingLimitMaxCu
+	}
+	if cfg.SuspendTimeoutSeconds != nil {
+		e.SuspendTimeoutSeconds = *cfg.SuspendTimeoutSeconds
+	}
+	if cfg.Disabled != nil {
+		e.Disabled = *cfg.Disabled
+	}
+	if cfg.PasswordlessAccess != nil {
+		e.PasswordlessAccess = *cfg.PasswordlessAccess
+	}
+	if cfg.PoolerEnabled != nil {
+		e.PoolerEnabled = *cfg.PoolerEnabled
+	}
+	if cfg.PoolerMode != nil {
+		e.PoolerMode = *cfg.PoolerMode
+	}
+	if cfg.Provisioner != nil {
+		e.Provisioner = *cfg.Provisioner
+	}
+	if cfg.RegionID != nil {
+		e.RegionID = *cfg.RegionID
+	}
+	if cfg.Settings != nil {
+		e.Settings = *cfg.Settings
+	}
+
+	return neon.EndpointOperations{
+		EndpointResponse:   neon.EndpointResponse{Endpoint: *e},
+		OperationsResponse: s.newOperations(p, neon.OperationActionStartCompute, &e.BranchID, &e.ID),
+	}, nil
+}
+
+func (s *Server) updateEndpoint(p *project, e *neon.Endpoint, r *http.Request) (interface{}, error) {
+	var req neon.EndpointUpdateRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	cfg := req.Endpoint
+
+	if cfg.BranchID != nil {
+		if _, err := p.branch(*cfg.BranchID); err != nil {
+			return nil, err
+		}
+		e.BranchID = *cfg.BranchID
+	}
+	if cfg.AutoscalingLimitMinCu != nil {
+		e.AutoscalingLimitMinCu = *cfg.AutoscalingLimitMinCu
+	}
+	if cfg.AutoscalingLimitMaxCu != nil {
+		e.AutoscalingLimitMaxCu = *cfg.AutoscalingLimitMaxCu
+	}
+	if cfg.SuspendTimeoutSeconds != nil {
+		e.SuspendTimeoutSeconds = *cfg.SuspendTimeoutSeconds
+	}
+	if cfg.Disabled != nil {
+		e.Disabled = *cfg.Disabled
+	}
+	if cfg.PasswordlessAccess != nil {
+		e.PasswordlessAccess = *cfg.PasswordlessAccess
+	}
+	if cfg.PoolerEnabled != nil {
+		e.PoolerEnabled = *cfg.PoolerEnabled
+	}
+	if cfg.PoolerMode != nil {
+		e.PoolerMode = *cfg.PoolerMode
+	}
+	if cfg.Provisioner != nil {
+		e.Provisioner = *cfg.Provisioner
+	}
+	if cfg.Settings != nil {
+		e.Settings = *cfg.Settings
+	}
+	e.UpdatedAt = s.Now().UTC()
+
+	return neon.EndpointOperations{
+		EndpointResponse:   neon.EndpointResponse{Endpoint: *e},
+		OperationsResponse: s.newOperations(p, neon.OperationActionApplyConfig, &e.BranchID, &e.ID),
+	}, nil
+}
+
+func (s *Server) deleteEndpoint(p *project, e *neon.Endpoint) neon.EndpointOperations {
+	for i, v := range p.endpoints {
+		if v == e {
+			p.endpoints = append(p.endpoints[:i], p.endpoints[i+1:]...)
+			break
+		}
+	}
+	return neon.EndpointOperations{
+		EndpointResponse:   neon.EndpointResponse{Endpoint: *e},
+		OperationsResponse: s.newOperations(p, neon.OperationActionSuspendCompute, &e.BranchID, &e.ID),
+	}
+}
+
+func (p *project) branch(id string) (*branch, error) {
+	for _, b := range p.branches {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, errNotFound("branch", id)
+}
+
+func (p *project) defaultBranch() *branch {
+	for _, b := range p.branches {
+		if b.Default {
+			return b
+		}
+	}
+	return nil
+}
+
+func (p *project) endpoint(id string) (*neon.Endpoint, error) {
+	for _, e := range p.endpoints {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, errNotFound("endpoint", id)
+}
+
+// branchEndpoints returns the endpoints of the branch, or all endpoints of the project if branchID is empty.
+func (p *project) branchEndpoints(branchID string) []neon.Endpoint {
+	o := []neon.Endpoint{}
+	for _, e := range p.endpoints {
+		if branchID == "" || e.BranchID == branchID {
+			o = append(o, *e)
+		}
+	}
+	return o
+}
+
+func (b *branch) database(name string) (*neon.Database, int) {
+	for i, v := range b.databases {
+		if v.Name == name {
+			return v, i
+		}
+	}
+	return nil, -1
+}
+
+func (b *branch) role(name string) (*neon.Role, int) {
+	for i, v := range b.roles {
+		if v.Name == name {
+			return v, i
+		}
+	}
+	return nil, -1
+}
+
+func (b *branch) listDatabases() []neon.Database {
+	o := make([]neon.Database, len(b.databases))
+	for i, v := range b.databases {
+		o[i] = *v
+	}
+	return o
+}
+
+func (b *branch) listRoles() []neon.Role {
+	o := make([]neon.Role, len(b.roles))
+	for i, v := range b.roles {
+		o[i] = *v
+	}
+	return o
+}
+
+func connectionURI(role, password, host, database string) string {
+	return fmt.Sprintf("postgresql://%s:%s@%s/%s?sslmode=require", role, password, host, database)
+}
+
+func poolerHost(host string) string {
+	if i := strings.Index(host, "."); i > 0 {
+		return host[:i] + "-pooler" + host[i:]
+	}
+	return host
+}
+
+func stringValue(v *string, defaultValue string) string {
+	if v == nil || *v == "" {
+		return defaultValue
+	}
+	return *v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
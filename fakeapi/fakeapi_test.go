@@ -0,0 +1,231 @@
+package fakeapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/internal/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T) (*neon.Client, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New()
+	s.Now = func() time.Time { return now }
+
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	httpClient := telemetry.NewHTTPClient("test", "0.0.1", "")
+	httpClient.BaseURL = srv.URL + "/api/v2"
+
+	c, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: httpClient})
+	assert.NoError(t, err)
+	return c, &now
+}
+
+func TestServer_project(t *testing.T) {
+	c, now := newTestClient(t)
+
+	// GIVEN the project is created
+	name := "foo"
+	p, err := c.CreateProject(neon.ProjectCreateRequest{Project: neon.ProjectCreateRequestProject{Name: &name}})
+	assert.NoError(t, err)
+
+	projectID := p.Project.ID
+	assert.Equal(t, "foo", p.Project.Name)
+	assert.True(t, p.Branch.Default)
+	assert.Len(t, p.Endpoints, 1)
+	assert.Equal(t, p.Branch.ID, p.Endpoints[0].BranchID)
+	assert.Equal(t, "neondb", p.Databases[0].Name)
+	assert.Equal(t, "neondb_owner", p.Roles[0].Name)
+	assert.NotEmpty(t, p.ConnectionURIs[0].ConnectionURI)
+
+	// THEN the mutations are rejected while the operations are running
+	_, err = c.CreateProjectBranch(projectID, nil)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusLocked, err.(neon.Error).HTTPCode)
+	}
+
+	// WHEN the operations finished
+	*now = now.Add(DefaultOperationDuration)
+
+	ops, err := c.ListProjectOperations(projectID, nil, nil)
+	assert.NoError(t, err)
+	for _, o := range ops.Operations {
+		assert.Equal(t, neon.OperationStatusFinished, o.Status)
+	}
+
+	// THEN the project can be updated
+	newName := "bar"
+	_, err = c.UpdateProject(projectID, neon.ProjectUpdateRequest{Project: neon.ProjectUpdateRequestProject{
+		Name: &newName,
+	}})
+	assert.NoError(t, err)
+
+	got, err := c.GetProject(projectID)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", got.Project.Name)
+
+	projects, err := c.ListProjects(nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, projects.Projects, 1)
+
+	// WHEN the project is deleted
+	_, err = c.DeleteProject(projectID)
+	assert.NoError(t, err)
+
+	// THEN it's not found
+	_, err = c.GetProject(projectID)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+	}
+}
+
+func TestServer_branch(t *testing.T) {
+	c, now := newTestClient(t)
+
+	p, err := c.CreateProject(neon.ProjectCreateRequest{})
+	assert.NoError(t, err)
+	projectID := p.Project.ID
+	*now = now.Add(DefaultOperationDuration)
+
+	// WHEN the branch is created with the endpoint
+	name := "dev"
+	b, err := c.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+		BranchCreateRequest: neon.BranchCreateRequest{
+			Branch:    &neon.BranchCreateRequestBranch{Name: &name},
+			Endpoints: &[]neon.BranchCreateRequestEndpointOptions{{Type: neon.EndpointTypeReadWrite}},
+		},
+	})
+	assert.NoError(t, err)
+	*now = now.Add(DefaultOperationDuration)
+
+	// THEN it inherits the roles and databases of the parent
+	branchID := b.Branch.ID
+	assert.Equal(t, p.Branch.ID, *b.Branch.ParentID)
+	assert.Equal(t, "neondb", b.Databases[0].Name)
+
+	pass, err := c.GetProjectBranchRolePassword(projectID, branchID, "neondb_owner")
+	assert.NoError(t, err)
+	assert.Equal(t, *p.Roles[0].Password, pass.Password)
+
+	endpoints, err := c.ListProjectBranchEndpoints(projectID, branchID)
+	assert.NoError(t, err)
+	assert.Len(t, endpoints.Endpoints, 1)
+
+	// THEN the second read_write endpoint is rejected
+	_, err = c.CreateProjectEndpoint(projectID, neon.EndpointCreateRequest{Endpoint: neon.EndpointCreateRequestEndpoint{
+		BranchID: branchID,
+		Type:     neon.EndpointTypeReadWrite,
+	}})
+	assert.Error(t, err)
+
+	// WHEN the role and database are created
+	_, err = c.CreateProjectBranchRole(projectID, branchID, neon.RoleCreateRequest{
+		Role: neon.RoleCreateRequestRole{Name: "qux"},
+	})
+	assert.NoError(t, err)
+	*now = now.Add(DefaultOperationDuration)
+
+	_, err = c.CreateProjectBranchDatabase(projectID, branchID, neon.DatabaseCreateRequest{
+		Database: neon.DatabaseCreateRequestDatabase{Name: "quxdb", OwnerName: "qux"},
+	})
+	assert.NoError(t, err)
+	*now = now.Add(DefaultOperationDuration)
+
+	db, err := c.GetProjectBranchDatabase(projectID, branchID, "quxdb")
+	assert.NoError(t, err)
+	assert.Equal(t, "qux", db.Database.OwnerName)
+
+	// THEN the default branch cannot be deleted
+	_, err = c.DeleteProjectBranch(projectID, p.Branch.ID)
+	assert.Error(t, err)
+
+	// WHEN the branch is deleted
+	_, err = c.DeleteProjectBranch(projectID, branchID)
+	assert.NoError(t, err)
+
+	// THEN its endpoints are deleted
+	_, err = c.GetProjectBranch(projectID, branchID)
+	assert.Error(t, err)
+
+	all, err := c.ListProjectEndpoints(projectID)
+	assert.NoError(t, err)
+	assert.Len(t, all.Endpoints, 1)
+}
+
+func TestServer_RoundTrip(t *testing.T) {
+	// GIVEN the server is the transport of the client
+	s := New()
+	s.OperationDuration = 0
+	c, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &http.Client{Transport: s}})
+	assert.NoError(t, err)
+
+	// WHEN the project is created
+	p, err := c.CreateProject(neon.ProjectCreateRequest{})
+	assert.NoError(t, err)
+
+	// THEN it's served in-process
+	got, err := c.GetProject(p.Project.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, p.Project.ID, got.Project.ID)
+
+	_, err = c.GetProject("unknown")
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+	}
+}
+
+func TestServer_StateFile(t *testing.T) {
+	// GIVEN two servers which share the state file, e.g. served by the processes started one after another
+	newClient := func(stateFile string) *neon.Client {
+		s := New()
+		s.OperationDuration = 0
+		s.StateFile = stateFile
+		c, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &http.Client{Transport: s}})
+		assert.NoError(t, err)
+		return c
+	}
+	stateFile := filepath.Join(t.TempDir(), "state", "fakeapi.json")
+	first, second := newClient(stateFile), newClient(stateFile)
+
+	// WHEN the project and the branch are created by the first server
+	p, err := first.CreateProject(neon.ProjectCreateRequest{})
+	assert.NoError(t, err)
+	b, err := first.CreateProjectBranch(p.Project.ID, &neon.CreateProjectBranchReqObj{
+		BranchCreateRequest: neon.BranchCreateRequest{
+			Branch: &neon.BranchCreateRequestBranch{Name: stringPtr("dev")},
+		},
+	})
+	assert.NoError(t, err)
+
+	// THEN the second server serves them
+	got, err := second.GetProjectBranch(p.Project.ID, b.Branch.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", got.Branch.Name)
+
+	roles, err := second.ListProjectBranchRoles(p.Project.ID, p.Branch.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, roles.Roles, 1) {
+		pwd, err := second.GetProjectBranchRolePassword(p.Project.ID, p.Branch.ID, roles.Roles[0].Name)
+		assert.NoError(t, err)
+		assert.Equal(t, *p.Roles[0].Password, pwd.Password)
+	}
+
+	// WHEN the branch is deleted by the second server
+	_, err = second.DeleteProjectBranch(p.Project.ID, b.Branch.ID)
+	assert.NoError(t, err)
+
+	// THEN the first server does not serve it
+	_, err = first.GetProjectBranch(p.Project.ID, b.Branch.ID)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+	}
+}
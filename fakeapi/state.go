@@ -0,0 +1,115 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// state defines the serialized state of the fake API.
+type state struct {
+	Seq      int            `json:"seq"`
+	Projects []projectState `json:"projects"`
+}
+
+type projectState struct {
+	Project    neon.Project     `json:"project"`
+	Branches   []branchState    `json:"branches"`
+	Endpoints  []*neon.Endpoint `json:"endpoints"`
+	Operations []neon.Operation `json:"operations"`
+}
+
+type branchState struct {
+	Branch      neon.Branch              `json:"branch"`
+	Annotations neon.AnnotationValueData `json:"annotations,omitempty"`
+	Databases   []*neon.Database         `json:"databases"`
+	Roles       []*neon.Role             `json:"roles"`
+	Passwords   map[string]string        `json:"passwords,omitempty"`
+}
+
+// load reads the state from the file StateFile. The state is kept as is if the file does not exist.
+func (s *Server) load() error {
+	if s.StateFile == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(s.StateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	var v state
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	s.seq = v.Seq
+	s.projects = make([]*project, len(v.Projects))
+	for i, p := range v.Projects {
+		o := &project{Project: p.Project, endpoints: p.Endpoints, operations: p.Operations}
+		for _, b := range p.Branches {
+			o.branches = append(o.branches, &branch{
+				Branch:      b.Branch,
+				annotations: b.Annotations,
+				databases:   b.Databases,
+				roles:       b.Roles,
+				passwords:   b.Passwords,
+			})
+		}
+		s.projects[i] = o
+	}
+	return nil
+}
+
+// save writes the state to the file StateFile.
+// The file is replaced atomically, i.e. the concurrent reader gets either the previous, or the new state.
+func (s *Server) save() error {
+	if s.StateFile == "" {
+		return nil
+	}
+
+	v := state{Seq: s.seq, Projects: make([]projectState, len(s.projects))}
+	for i, p := range s.projects {
+		o := projectState{Project: p.Project, Endpoints: p.endpoints, Operations: p.operations}
+		for _, b := range p.branches {
+			o.Branches = append(o.Branches, branchState{
+				Branch:      b.Branch,
+				Annotations: b.annotations,
+				Databases:   b.databases,
+				Roles:       b.roles,
+				Passwords:   b.passwords,
+			})
+		}
+		v.Projects[i] = o
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.StateFile)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(s.StateFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.StateFile)
+}
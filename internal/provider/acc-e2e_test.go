@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// TestAccE2E provisions the project, branch, endpoint, role and database against the Neon API, and connects
+// to the database. Unlike TestAcc, it always runs against the real API, i.e. neither the recorded fixtures,
+// nor the fake API are used. It's opt-in: set the environment variable TF_ACC_NEON_E2E to "1" to run it.
+func TestAccE2E(t *testing.T) {
+	if os.Getenv("TF_ACC_NEON_E2E") != "1" {
+		t.Skip("TF_ACC_NEON_E2E must be set to 1")
+	}
+	t.Setenv("TF_ACC", "1")
+
+	client, err := neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		resp, _ := client.ListProjects(nil, nil, &projectNamePrefix, nil)
+		for _, project := range resp.Projects {
+			_, _ = client.DeleteProject(project.ID)
+		}
+	})
+
+	const (
+		roleName     = "e2e_role"
+		databaseName = "e2e_db"
+	)
+
+	resourceDefinition := fmt.Sprintf(`
+resource "neon_project" "this" {
+  name = "%s"
+}
+
+resource "neon_branch" "this" {
+  project_id = neon_project.this.id
+  name       = "e2e"
+}
+
+resource "neon_endpoint" "this" {
+  project_id = neon_project.this.id
+  branch_id  = neon_branch.this.id
+}
+
+resource "neon_role" "this" {
+  project_id = neon_project.this.id
+  branch_id  = neon_branch.this.id
+  name       = "%s"
+}
+
+resource "neon_database" "this" {
+  project_id = neon_project.this.id
+  branch_id  = neon_branch.this.id
+  name       = "%s"
+  owner_name = neon_role.this.name
+}
+`, newProjectName(), roleName, databaseName)
+
+	resource.Test(
+		t, resource.TestCase{
+			ProviderFactories: map[string]func() (*schema.Provider, error){
+				"neon": func() (*schema.Provider, error) {
+					return New("e2e"), nil
+				},
+			},
+			Steps: []resource.TestStep{
+				{
+					Config: resourceDefinition,
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrPair(
+							"neon_endpoint.this", "branch_id", "neon_branch.this", "id",
+						),
+						resource.TestCheckResourceAttrSet("neon_endpoint.this", "host"),
+						resource.TestCheckResourceAttrSet("neon_role.this", "password"),
+						resource.TestCheckResourceAttr("neon_database.this", "owner_name", roleName),
+						func(s *terraform.State) error {
+							host := s.RootModule().Resources["neon_endpoint.this"].Primary.Attributes["host"]
+							password := s.RootModule().Resources["neon_role.this"].Primary.Attributes["password"]
+							return pgConnect(host, roleName, password, databaseName)
+						},
+					),
+				},
+				{
+					// the plan is expected to be empty after the apply
+					Config:   resourceDefinition,
+					PlanOnly: true,
+				},
+			},
+		},
+	)
+}
+
+// pgConnect establishes the TLS connection to the Postgres endpoint, authenticates the role using its password,
+// and runs the query SELECT 1. The Postgres protocol is implemented to the extent used by the Neon endpoints, i.e.
+// the cleartext password, and SCRAM-SHA-256 authentication, hence no Postgres driver is required.
+// See https://www.postgresql.org/docs/current/protocol-flow.html
+func pgConnect(host, user, password, database string) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, "5432"), 30*time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	// the compute endpoint may need time to wake up
+	if err := conn.SetDeadline(time.Now().Add(2 * time.Minute)); err != nil {
+		return err
+	}
+
+	// SSLRequest: https://www.postgresql.org/docs/current/protocol-message-formats.html
+	sslRequest := make([]byte, 8)
+	binary.BigEndian.PutUint32(sslRequest[0:4], 8)
+	binary.BigEndian.PutUint32(sslRequest[4:8], 80877103)
+	if _, err := conn.Write(sslRequest); err != nil {
+		return err
+	}
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 'S' {
+		return errors.New("the server does not support TLS")
+	}
+
+	// the server name is required by Neon to route the connection to the endpoint
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c := pgConn{rw: tlsConn}
+
+	var params bytes.Buffer
+	for _, v := range []string{"user", user, "database", database} {
+		params.WriteString(v)
+		params.WriteByte(0)
+	}
+	params.WriteByte(0)
+
+	startup := make([]byte, 8, 8+params.Len())
+	binary.BigEndian.PutUint32(startup[0:4], uint32(8+params.Len()))
+	binary.BigEndian.PutUint32(startup[4:8], 196608) // protocol version 3.0
+	startup = append(startup, params.Bytes()...)
+	if _, err := tlsConn.Write(startup); err != nil {
+		return err
+	}
+
+	if err := c.authenticate(password); err != nil {
+		return err
+	}
+	if err := c.readUntilReady(nil); err != nil {
+		return err
+	}
+
+	if err := c.write('Q', []byte("SELECT 1\x00")); err != nil {
+		return err
+	}
+	var rows [][]byte
+	if err := c.readUntilReady(func(typ byte, body []byte) error {
+		if typ == 'D' {
+			rows = append(rows, body)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// DataRow: the number of columns, the column's length, and its value
+	want := []byte{0, 1, 0, 0, 0, 1, '1'}
+	if len(rows) != 1 || !bytes.Equal(rows[0], want) {
+		return fmt.Errorf("unexpected result of SELECT 1: %q", rows)
+	}
+
+	_ = c.write('X', nil)
+	return nil
+}
+
+// pgConn reads and writes the messages of the Postgres protocol.
+type pgConn struct {
+	rw io.ReadWriter
+}
+
+func (c pgConn) write(typ byte, body []byte) error {
+	msg := make([]byte, 5, 5+len(body))
+	msg[0] = typ
+	binary.BigEndian.PutUint32(msg[1:5], uint32(4+len(body)))
+	_, err := c.rw.Write(append(msg, body...))
+	return err
+}
+
+func (c pgConn) read() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return 0, nil, err
+	}
+	size := binary.BigEndian.Uint32(header[1:5])
+	if size < 4 || size > 1<<16 {
+		return 0, nil, fmt.Errorf("unexpected message size %d", size)
+	}
+	body := make([]byte, size-4)
+	if _, err := io.ReadFull(c.rw, body); err != nil {
+		return 0, nil, err
+	}
+	if header[0] == 'E' {
+		return 0, nil, errors.New("server error: " + string(bytes.ReplaceAll(body, []byte{0}, []byte(" "))))
+	}
+	return header[0], body, nil
+}
+
+// readUntilReady reads the messages until the server is ready for the query, fn is called for every message.
+func (c pgConn) readUntilReady(fn func(typ byte, body []byte) error) error {
+	for {
+		typ, body, err := c.read()
+		if err != nil {
+			return err
+		}
+		if typ == 'Z' {
+			return nil
+		}
+		if fn != nil {
+			if err := fn(typ, body); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authenticate performs the authentication requested by the server.
+func (c pgConn) authenticate(password string) error {
+	typ, body, err := c.read()
+	if err != nil {
+		return err
+	}
+	if typ != 'R' || len(body) < 4 {
+		return fmt.Errorf("unexpected message type %q, the authentication request is expected", typ)
+	}
+
+	switch code := binary.BigEndian.Uint32(body[0:4]); code {
+	case 0:
+		return errors.New("the server did not request the authentication")
+	case 3:
+		if err := c.write('p', append([]byte(password), 0)); err != nil {
+			return err
+		}
+	case 10:
+		if !bytes.Contains(body[4:], []byte("SCRAM-SHA-256\x00")) {
+			return fmt.Errorf("unsupported SASL mechanisms %q", body[4:])
+		}
+		if err := c.scramSHA256(password); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported authentication method %d", code)
+	}
+
+	typ, body, err = c.read()
+	if err != nil {
+		return err
+	}
+	if typ != 'R' || len(body) < 4 || binary.BigEndian.Uint32(body[0:4]) != 0 {
+		return errors.New("the authentication failed")
+	}
+	return nil
+}
+
+// scramSHA256 performs the SASL authentication using SCRAM-SHA-256, see RFC 5802 and RFC 7677.
+func (c pgConn) scramSHA256(password string) error {
+	nonce := make([]byte, 18)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	clientNonce := base64.StdEncoding.EncodeToString(nonce)
+	// the user name is sent by the startup message, hence it's empty
+	clientFirst := "n=,r=" + clientNonce
+
+	msg := append([]byte("SCRAM-SHA-256\x00"), make([]byte, 4)...)
+	binary.BigEndian.PutUint32(msg[len(msg)-4:], uint32(len("n,,"+clientFirst)))
+	if err := c.write('p', append(msg, "n,,"+clientFirst...)); err != nil {
+		return err
+	}
+
+	serverFirst, err := c.readSASL(11)
+	if err != nil {
+		return err
+	}
+	attrs := map[string]string{}
+	for _, v := range strings.Split(serverFirst, ",") {
+		if k, v, ok := strings.Cut(v, "="); ok {
+			attrs[k] = v
+		}
+	}
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil {
+		return err
+	}
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(attrs["r"], clientNonce) {
+		return errors.New("the server's nonce does not match the client's")
+	}
+
+	clientFinal := "c=biws,r=" + attrs["r"]
+	authMessage := clientFirst + "," + serverFirst + "," + clientFinal
+
+	saltedPassword := pbkdf2SHA256([]byte(password), salt, iterations)
+	clientKey := hmacSHA256(saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	proof := hmacSHA256(storedKey[:], authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	clientFinal += ",p=" + base64.StdEncoding.EncodeToString(proof)
+	if err := c.write('p', []byte(clientFinal)); err != nil {
+		return err
+	}
+
+	serverFinal, err := c.readSASL(12)
+	if err != nil {
+		return err
+	}
+	serverSignature := hmacSHA256(hmacSHA256(saltedPassword, "Server Key"), authMessage)
+	if serverFinal != "v="+base64.StdEncoding.EncodeToString(serverSignature) {
+		return errors.New("the server's signature is invalid")
+	}
+	return nil
+}
+
+// readSASL reads the SASL message given its authentication code.
+func (c pgConn) readSASL(code uint32) (string, error) {
+	typ, body, err := c.read()
+	if err != nil {
+		return "", err
+	}
+	if typ != 'R' || len(body) < 4 || binary.BigEndian.Uint32(body[0:4]) != code {
+		return "", fmt.Errorf("unexpected message type %q, the SASL message %d is expected", typ, code)
+	}
+	return string(body[4:]), nil
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
+
+// pbkdf2SHA256 derives the key of the SHA-256 size, see RFC 8018.
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	h := hmac.New(sha256.New, password)
+	h.Write(salt)
+	h.Write([]byte{0, 0, 0, 1})
+	u := h.Sum(nil)
+	o := append([]byte(nil), u...)
+	for i := 1; i < iterations; i++ {
+		h.Reset()
+		h.Write(u)
+		u = h.Sum(u[:0])
+		for j := range o {
+			o[j] ^= u[j]
+		}
+	}
+	return o
+}
@@ -21,7 +21,7 @@ func TestAccResourcesImport(t *testing.T) {
 		projectID      string
 		customBranchID string
 	)
-	client, err := neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	client, err := newAccClient(t)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -21,7 +21,7 @@ func TestAccOrg(t *testing.T) {
 		t.Skip("ORG_ID must be set")
 	}
 
-	client, err := neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	client, err := newAccClient(t)
 	if err != nil {
 		t.Fatal(err)
 	}
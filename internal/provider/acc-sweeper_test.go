@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// sweepPrefix defines the name prefix of the resources created by the acceptance tests.
+const sweepPrefix = "acctest-"
+
+// TestMain runs the sweepers to delete the dangling resources created by the acceptance tests, e.g.
+//
+//	go test ./internal/provider -v -sweep=all
+//
+// The resources are swept in the personal account, and in the organization if the environment variable ORG_ID is set.
+// Note that the region passed to the flag -sweep is ignored.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("neon_project", &resource.Sweeper{
+		Name: "neon_project",
+		F:    sweepProjects,
+	})
+	resource.AddTestSweepers("neon_branch", &resource.Sweeper{
+		Name: "neon_branch",
+		F:    sweepBranches,
+	})
+}
+
+func newSweeperClient() (*neon.Client, error) {
+	return neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+}
+
+// listSweepProjects lists all projects in the personal account, and in the organization ORG_ID if set.
+func listSweepProjects(client *neon.Client) ([]neon.ProjectListItem, error) {
+	orgIDs := []*string{nil}
+	if v := os.Getenv("ORG_ID"); v != "" {
+		orgIDs = append(orgIDs, &v)
+	}
+
+	const limit = 100
+	var o []neon.ProjectListItem
+	for _, orgID := range orgIDs {
+		var cursor *string
+		for {
+			l := limit
+			resp, err := client.ListProjects(cursor, &l, nil, orgID)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, resp.Projects...)
+
+			if len(resp.Projects) < limit || resp.Pagination == nil || resp.Pagination.Cursor == "" {
+				break
+			}
+			cursor = &resp.Pagination.Cursor
+		}
+	}
+	return o, nil
+}
+
+func isNotFoundErr(err error) bool {
+	var e neon.Error
+	return errors.As(err, &e) && e.HTTPCode == http.StatusNotFound
+}
+
+// sweepRetry calls fn until the project is unlocked.
+func sweepRetry(fn func() error) error {
+	return projectReadiness.retry(
+		func(context.Context, *schema.ResourceData, interface{}) error {
+			return fn()
+		}, context.Background(), nil, nil,
+	)
+}
+
+func sweepProjects(_ string) error {
+	client, err := newSweeperClient()
+	if err != nil {
+		return err
+	}
+
+	projects, err := listSweepProjects(client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range projects {
+		if !strings.HasPrefix(p.Name, sweepPrefix) {
+			continue
+		}
+
+		log.Printf("[INFO] sweeping project %s (%s)", p.ID, p.Name)
+		if err := sweepRetry(func() error {
+			_, err := client.DeleteProject(p.ID)
+			return err
+		}); err != nil && !isNotFoundErr(err) {
+			errs = append(errs, fmt.Errorf("cannot delete project %s: %w", p.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sweepBranches deletes the test branches created in the projects which are not swept by sweepProjects.
+func sweepBranches(_ string) error {
+	client, err := newSweeperClient()
+	if err != nil {
+		return err
+	}
+
+	projects, err := listSweepProjects(client)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range projects {
+		if strings.HasPrefix(p.Name, sweepPrefix) {
+			continue
+		}
+
+		resp, err := client.ListProjectBranches(p.ID, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cannot list branches of project %s: %w", p.ID, err))
+			continue
+		}
+
+		for _, b := range resp.Branches {
+			if b.Default || !strings.HasPrefix(b.Name, sweepPrefix) {
+				continue
+			}
+
+			log.Printf("[INFO] sweeping branch %s (%s) of project %s", b.ID, b.Name, p.ID)
+			if err := sweepRetry(func() error {
+				_, err := client.DeleteProjectBranch(p.ID, b.ID)
+				return err
+			}); err != nil && !isNotFoundErr(err) {
+				errs = append(errs, fmt.Errorf("cannot delete branch %s of project %s: %w", b.ID, p.ID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
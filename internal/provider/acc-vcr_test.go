@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/kislerdm/terraform-provider-neon/internal/telemetry"
+	"github.com/kislerdm/terraform-provider-neon/internal/vcr"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAccClient initializes the API client for the acceptance tests.
+// The API calls are recorded to, or replayed from the cassette testdata/fixtures/<test name>.json
+// depending on the environment variable NEON_VCR_MODE:
+//   - "record": the API calls are sent to the Neon API and recorded;
+//   - "replay": the API calls are served from the cassette, the Neon account is not required;
+//   - otherwise: the API calls are sent to the Neon API.
+//
+// The API calls are served by the in-memory fake API if the environment variable NEON_API_FAKE is set to "1".
+func newAccClient(t *testing.T) (*neon.Client, error) {
+	if os.Getenv("NEON_API_FAKE") == "1" {
+		return newFakeAccClient(t)
+	}
+
+	mode := vcr.Mode(os.Getenv("NEON_VCR_MODE"))
+	if mode == vcr.ModeDisabled {
+		return neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	}
+
+	cassette := filepath.Join("testdata", "fixtures", strings.ReplaceAll(t.Name(), "/", "_")+".json")
+	if _, err := os.Stat(cassette); mode == vcr.ModeReplay && errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("no cassette %s is recorded, run make testacc-record to record it", cassette)
+	}
+	return newVCRClient(t, mode, cassette)
+}
+
+// newVCRClient initializes the API client which records the API calls to, or replays them from the cassette.
+// The API calls are recorded from the Neon API, or from the API defined by the environment variable NEON_API_BASE_URL,
+// e.g. the fake API server.
+func newVCRClient(t *testing.T, mode vcr.Mode, cassette string) (*neon.Client, error) {
+	httpClient := telemetry.NewHTTPClient(Name, "acc", "")
+	httpClient.BaseURL = os.Getenv("NEON_API_BASE_URL")
+
+	// the client is used to send the API calls in the record mode only
+	rec, err := vcr.New(mode, cassette, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	rec.Sanitize = redactSecrets
+
+	defaultNewSDKClient := newSDKClient
+	newSDKClient = func(cfg neon.Config) (*neon.Client, error) {
+		cfg.HTTPClient = rec
+		if cfg.Key == "" {
+			// the key is not used upon replay, but it's required to init the client
+			cfg.Key = "replay"
+		}
+		return defaultNewSDKClient(cfg)
+	}
+
+	t.Cleanup(func() {
+		newSDKClient = defaultNewSDKClient
+		if err := rec.Save(); err != nil {
+			t.Errorf("cannot save the cassette %s: %v", cassette, err)
+		}
+	})
+
+	return newSDKClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+}
+
+// newFakeAccClient initializes the API client to communicate with the in-memory fake API.
+func newFakeAccClient(t *testing.T) (*neon.Client, error) {
+	srv := httptest.NewServer(fakeapi.New())
+
+	httpClient := telemetry.NewHTTPClient(Name, "acc", "")
+	httpClient.BaseURL = srv.URL + "/api/v2"
+
+	defaultNewSDKClient := newSDKClient
+	newSDKClient = func(cfg neon.Config) (*neon.Client, error) {
+		cfg.HTTPClient = httpClient
+		cfg.Key = "fake"
+		return defaultNewSDKClient(cfg)
+	}
+
+	t.Cleanup(func() {
+		newSDKClient = defaultNewSDKClient
+		srv.Close()
+	})
+
+	return newSDKClient(neon.Config{})
+}
+
+// TestVCRReplay replays the API calls of the resources' lifecycle from the committed cassette
+// testdata/fixtures/TestVCRReplay.json. The cassette is recorded with NEON_VCR_MODE=record.
+// The test runs with the unit and the acceptance tests because it does not send the API calls upon replay.
+func TestVCRReplay(t *testing.T) {
+	mode := vcr.ModeReplay
+	if vcr.Mode(os.Getenv("NEON_VCR_MODE")) == vcr.ModeRecord {
+		mode = vcr.ModeRecord
+	}
+	client, err := newVCRClient(t, mode, filepath.Join("testdata", "fixtures", t.Name()+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.TODO()
+
+	// GIVEN
+	project := resourceProject().TestResourceData()
+	_ = project.Set("name", "acctest-vcr-replay")
+	_ = project.Set("history_retention_seconds", 3600)
+
+	// WHEN
+	if diags := resourceProjectCreateRetry(ctx, project, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.NotEmpty(t, project.Id())
+	assert.Equal(t, "acctest-vcr-replay", project.Get("name"))
+	assert.Equal(t, 3600, project.Get("history_retention_seconds"))
+
+	// WHEN
+	branch := resourceBranch().TestResourceData()
+	_ = branch.Set("project_id", project.Id())
+	_ = branch.Set("name", "dev")
+	if diags := resourceBranchCreateRetry(ctx, branch, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	endpoint := resourceEndpoint().TestResourceData()
+	_ = endpoint.Set("project_id", project.Id())
+	_ = endpoint.Set("branch_id", branch.Id())
+	_ = endpoint.Set("type", "read_write")
+	if diags := resourceEndpointCreateRetry(ctx, endpoint, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	if diags := resourceBranchReadRetry(ctx, branch, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	assert.Equal(t, "dev", branch.Get("name"))
+	assert.Equal(t, project.Get("default_branch_id"), branch.Get("parent_id"))
+	assert.NotEmpty(t, endpoint.Get("host"))
+	assert.Equal(t, branch.Id(), endpoint.Get("branch_id"))
+
+	// WHEN
+	if diags := resourceEndpointDeleteRetry(ctx, endpoint, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := resourceBranchDeleteRetry(ctx, branch, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if diags := resourceProjectDeleteRetry(ctx, project, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Empty(t, project.Id())
+}
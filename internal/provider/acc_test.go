@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,13 +19,23 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var projectNamePrefix string
+var (
+	projectNamePrefix string
+	projectNameCnt    atomic.Int64
+)
 
 func init() {
 	projectNamePrefix = "acctest-" + uuid.NewString() + "-"
+	// the recorded API calls are replayed given the same requests, i.e. the names must be deterministic
+	if os.Getenv("NEON_VCR_MODE") != "" {
+		projectNamePrefix = "acctest-vcr-"
+	}
 }
 
 func newProjectName() string {
+	if os.Getenv("NEON_VCR_MODE") != "" {
+		return projectNamePrefix + strconv.FormatInt(projectNameCnt.Add(1), 10)
+	}
 	return projectNamePrefix + strconv.FormatInt(time.Now().UnixMilli(), 10)
 }
 
@@ -33,7 +44,7 @@ func TestAcc(t *testing.T) {
 		t.Skip("TF_ACC must be set to 1")
 	}
 
-	client, err := neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	client, err := newAccClient(t)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -308,7 +319,7 @@ resource "neon_database" "this" {
 									}
 
 									for _, branch := range resp.Branches {
-										if branch.Primary != nil && *branch.Primary {
+										if branch.Default {
 											defaultBranchID = branch.ID
 											if err := resource.TestCheckResourceAttr(
 												resourceNameProject, "branch.0.id", defaultBranchID,
@@ -1075,7 +1086,7 @@ func TestAccBranch(t *testing.T) {
 		t.Skip("TF_ACC must be set to 1")
 	}
 
-	client, err := neon.NewClient(neon.Config{Key: os.Getenv("NEON_API_KEY")})
+	client, err := newAccClient(t)
 	if err != nil {
 		t.Fatal(err)
 	}
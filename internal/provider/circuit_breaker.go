@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// circuitBreakerThreshold defines the number of the API calls failed in a row because of the API's outage,
+// after which the remaining API calls fail fast.
+const circuitBreakerThreshold = 5
+
+// circuitBreaker fails the API calls fast once the API appears unavailable, i.e. the API calls of
+// circuitBreakerThreshold operations in a row failed with the server, or the network errors despite
+// the retries. It prevents the apply from waiting for the retries of every remaining resource.
+// Any API response other than the server error closes the circuit.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+}
+
+// circuitBreakers stores the circuit breaker per client, i.e. per provider's configuration.
+var circuitBreakers sync.Map
+
+func apiCircuitBreaker(client interface{}) *circuitBreaker {
+	if client == nil {
+		return nil
+	}
+	v, _ := circuitBreakers.LoadOrStore(client, &circuitBreaker{})
+	return v.(*circuitBreaker)
+}
+
+// check returns the error if the circuit is open.
+func (c *circuitBreaker) check() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures < circuitBreakerThreshold {
+		return nil
+	}
+	return errors.New("the Neon API appears unavailable: the API calls of " + strconv.Itoa(c.failures) +
+		" operations in a row failed, the remaining operations are not attempted. The last error: " +
+		c.lastErr.Error())
+}
+
+// record accounts the outcome of the operation's API calls.
+func (c *circuitBreaker) record(err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if isAPIOutage(err) {
+		c.failures++
+		c.lastErr = err
+		return
+	}
+	c.failures = 0
+}
+
+// isAPIOutage reports whether the error indicates that the API is unavailable, i.e. the server, or the network error.
+func isAPIOutage(err error) bool {
+	var e neon.Error
+	if errors.As(err, &e) {
+		return e.HTTPCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
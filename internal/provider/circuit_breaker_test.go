@@ -0,0 +1,80 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	r := delay{delay: 0, maxCnt: 2}
+	d := resourceBranch().TestResourceData()
+	d.SetId("br-foo")
+
+	t.Run("shall fail fast once the API appears unavailable", func(t *testing.T) {
+		// GIVEN
+		meta := &providerClient{}
+		var cnt int
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			cnt++
+			return neon.Error{HTTPCode: http.StatusServiceUnavailable}
+		}
+		for i := 0; i < circuitBreakerThreshold; i++ {
+			if diags := r.Retry(fn, context.TODO(), d, meta); !diags.HasError() {
+				t.Fatalf("error expected, got: %v", diags)
+			}
+		}
+
+		// WHEN
+		diags := r.Retry(fn, context.TODO(), d, meta)
+
+		// THEN
+		if !diags.HasError() || !strings.HasPrefix(diags[0].Summary, "the Neon API appears unavailable") {
+			t.Errorf("unexpected diagnostics: %v", diags)
+		}
+		if cnt != circuitBreakerThreshold {
+			t.Errorf("the API shall not be called once the circuit is open, got: %d calls", cnt)
+		}
+	})
+
+	t.Run("shall close the circuit if the API responds", func(t *testing.T) {
+		// GIVEN
+		meta := &providerClient{}
+		breaker := apiCircuitBreaker(meta)
+		for i := 0; i < circuitBreakerThreshold-1; i++ {
+			breaker.record(neon.Error{HTTPCode: http.StatusBadGateway})
+		}
+
+		// WHEN
+		breaker.record(neon.Error{HTTPCode: http.StatusNotFound})
+		breaker.record(neon.Error{HTTPCode: http.StatusBadGateway})
+
+		// THEN
+		if err := breaker.check(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("shall not account the client errors", func(t *testing.T) {
+		for _, err := range []error{
+			nil, errors.New("foo"), neon.Error{HTTPCode: http.StatusLocked}, neon.Error{HTTPCode: http.StatusNotFound},
+		} {
+			if isAPIOutage(err) {
+				t.Errorf("unexpected outage for %v", err)
+			}
+		}
+	})
+}
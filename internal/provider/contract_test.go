@@ -0,0 +1,299 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// The contract tests verify that the SDK's models and the provider's validations match the Neon OpenAPI specification.
+// The specification is read from the path, or URL defined by the environment variable NEON_OPENAPI_SPEC,
+// e.g. https://neon.tech/api_spec/release/v2.json; the specification shipped with the SDK is used otherwise.
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Required   []string                  `json:"required"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+	AllOf      []*openAPISchema          `json:"allOf"`
+	Enum       []interface{}             `json:"enum"`
+	Minimum    *float64                  `json:"minimum"`
+	Maximum    *float64                  `json:"maximum"`
+}
+
+type openAPISpec struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func readOpenAPISpec(t *testing.T) openAPISpec {
+	t.Helper()
+
+	src := os.Getenv("NEON_OPENAPI_SPEC")
+	if src == "" {
+		out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/kislerdm/neon-sdk-go").Output()
+		if err != nil {
+			t.Skipf("cannot locate the SDK module: %v", err)
+		}
+		src = filepath.Join(strings.TrimSpace(string(out)), "openAPIDefinition.json")
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		var resp *http.Response
+		resp, err = (&http.Client{Timeout: 30 * time.Second}).Get(src)
+		if err != nil {
+			t.Fatalf("cannot fetch the specification %s: %v", src, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		b, err = io.ReadAll(resp.Body)
+	} else {
+		b, err = os.ReadFile(src)
+	}
+	if err != nil {
+		t.Fatalf("cannot read the specification %s: %v", src, err)
+	}
+
+	var o openAPISpec
+	if err := json.Unmarshal(b, &o); err != nil {
+		t.Fatalf("cannot parse the specification %s: %v", src, err)
+	}
+	return o
+}
+
+// resolve returns the schema with the resolved reference, and merged allOf elements.
+func (s openAPISpec) resolve(v *openAPISchema) *openAPISchema {
+	if v == nil {
+		return nil
+	}
+	if v.Ref != "" {
+		return s.resolve(s.Components.Schemas[strings.TrimPrefix(v.Ref, "#/components/schemas/")])
+	}
+	if len(v.AllOf) == 0 {
+		return v
+	}
+
+	o := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	for _, el := range v.AllOf {
+		el = s.resolve(el)
+		o.Required = append(o.Required, el.Required...)
+		for k, p := range el.Properties {
+			o.Properties[k] = p
+		}
+	}
+	return o
+}
+
+// jsonFields returns the struct's fields by their JSON names, including the fields of the embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	o := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				o[k] = v
+			}
+			continue
+		}
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			o[name] = f
+		}
+	}
+	return o
+}
+
+func underlyingStruct(t reflect.Type) (reflect.Type, bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
+
+// missingRequiredFields returns the fields required by the specification which the model does not define.
+func (s openAPISpec) missingRequiredFields(model reflect.Type, schema *openAPISchema, path string) []string {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	var o []string
+	fields := jsonFields(model)
+	for _, name := range schema.Required {
+		f, ok := fields[name]
+		if !ok {
+			o = append(o, path+name)
+			continue
+		}
+		if t, ok := underlyingStruct(f.Type); ok {
+			prop := schema.Properties[name]
+			if p := s.resolve(prop); p != nil && p.Items != nil {
+				prop = p.Items
+			}
+			o = append(o, s.missingRequiredFields(t, prop, path+name+".")...)
+		}
+	}
+	return o
+}
+
+// unknownFields returns the model's fields which are not defined by the specification, e.g. renamed.
+func (s openAPISpec) unknownFields(model reflect.Type, schema *openAPISchema) []string {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	var o []string
+	for name := range jsonFields(model) {
+		if _, ok := schema.Properties[name]; !ok {
+			o = append(o, name)
+		}
+	}
+	return o
+}
+
+func (s openAPISpec) enum(t *testing.T, name string) []string {
+	t.Helper()
+
+	v := s.resolve(s.Components.Schemas[name])
+	if v == nil {
+		t.Fatalf("schema %s is not found", name)
+	}
+
+	o := make([]string, len(v.Enum))
+	for i, el := range v.Enum {
+		o[i], _ = el.(string)
+	}
+	return o
+}
+
+func TestContractRequestModels(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	spec := readOpenAPISpec(t)
+
+	// the request models sent by the provider must define all fields required by the API
+	tests := map[string]interface{}{
+		"ProjectCreateRequest":            neon.ProjectCreateRequest{},
+		"ProjectUpdateRequest":            neon.ProjectUpdateRequest{},
+		"BranchCreateRequest":             neon.BranchCreateRequest{},
+		"BranchUpdateRequest":             neon.BranchUpdateRequest{},
+		"EndpointCreateRequest":           neon.EndpointCreateRequest{},
+		"EndpointUpdateRequest":           neon.EndpointUpdateRequest{},
+		"RoleCreateRequest":               neon.RoleCreateRequest{},
+		"DatabaseCreateRequest":           neon.DatabaseCreateRequest{},
+		"DatabaseUpdateRequest":           neon.DatabaseUpdateRequest{},
+		"GrantPermissionToProjectRequest": neon.GrantPermissionToProjectRequest{},
+		"ApiKeyCreateRequest":             neon.ApiKeyCreateRequest{},
+	}
+
+	for name, model := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := spec.Components.Schemas[name]
+			if !ok {
+				t.Fatalf("schema %s is not found", name)
+			}
+			assert.Empty(t, spec.missingRequiredFields(reflect.TypeOf(model), schema, ""),
+				"the required fields are not defined by the SDK")
+		})
+	}
+}
+
+func TestContractResponseModels(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	spec := readOpenAPISpec(t)
+
+	// the response models read by the provider must not define the fields unknown to the API
+	tests := map[string]interface{}{
+		"Project":   neon.Project{},
+		"Branch":    neon.Branch{},
+		"Endpoint":  neon.Endpoint{},
+		"Role":      neon.Role{},
+		"Database":  neon.Database{},
+		"Operation": neon.Operation{},
+	}
+
+	for name, model := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema, ok := spec.Components.Schemas[name]
+			if !ok {
+				t.Fatalf("schema %s is not found", name)
+			}
+			assert.Empty(t, spec.unknownFields(reflect.TypeOf(model), schema),
+				"the fields are not defined by the API")
+		})
+	}
+}
+
+func TestContractEnums(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	spec := readOpenAPISpec(t)
+
+	t.Run("endpoint type", func(t *testing.T) {
+		values := spec.enum(t, "EndpointType")
+		assert.ElementsMatch(t, []string{string(neon.EndpointTypeReadWrite), string(neon.EndpointTypeReadOnly)}, values)
+
+		validate := resourceEndpoint().Schema["type"].ValidateFunc
+		for _, v := range values {
+			_, errs := validate(v, "type")
+			assert.Empty(t, errs, "the endpoint type %s is not supported by the provider", v)
+		}
+	})
+
+	t.Run("pooler mode", func(t *testing.T) {
+		assert.Contains(t, spec.enum(t, "EndpointPoolerMode"), string(neon.EndpointPoolerModeTransaction))
+	})
+
+	t.Run("operation status", func(t *testing.T) {
+		// the provider waits for the operations to reach the status "finished"
+		assert.Contains(t, spec.enum(t, "OperationStatus"), string(neon.OperationStatusFinished))
+	})
+
+	t.Run("postgres version", func(t *testing.T) {
+		v := spec.resolve(spec.Components.Schemas["PgVersion"])
+		if v == nil || v.Minimum == nil || v.Maximum == nil {
+			t.Fatal("the range of postgres versions is not defined")
+		}
+
+		validate := resourceProject().Schema["pg_version"].ValidateFunc
+		for version := int(*v.Minimum); version <= int(*v.Maximum); version++ {
+			_, errs := validate(version, "pg_version")
+			assert.Empty(t, errs, "the postgres version %d is not supported by the provider", version)
+		}
+	})
+
+	t.Run("autoscaling limit", func(t *testing.T) {
+		v := spec.resolve(spec.Components.Schemas["ComputeUnit"])
+		if v == nil || v.Minimum == nil {
+			t.Fatal("the minimum compute unit is not defined")
+		}
+
+		_, errs := validateAutoscallingLimit(*v.Minimum, "autoscaling_limit_min_cu")
+		assert.Empty(t, errs, "the minimum compute unit %v is not supported by the provider", *v.Minimum)
+	})
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The cost estimate is based on the list price of the compute and the storage, see details: https://neon.tech/pricing.
+// Note that the volume of data is unknown upon plan, hence the storage cost is bound by the project's quota.
+const (
+	computeUnitHourPriceUSD = 0.16
+	storageGiBMonthPriceUSD = 0.12
+	hoursPerMonth           = 730
+	secondsPerMonth         = hoursPerMonth * 3600
+	bytesPerGiB             = 1 << 30
+)
+
+// computeCostEstimate defines the range of the monthly compute cost of the endpoint.
+type computeCostEstimate struct {
+	minCU, maxCU float64
+	// suspendTimeoutSeconds defines the endpoint's inactivity timeout, the value -1 means never suspend.
+	suspendTimeoutSeconds int64
+	disabled              bool
+}
+
+// cost returns the lowest and the highest monthly cost. The endpoint which is never suspended runs at least
+// with the minimum compute size for the whole month, the endpoint which scales to zero may cost nothing.
+// Both run with the maximum compute size for the whole month at most.
+func (e computeCostEstimate) cost() (low, high float64) {
+	if e.disabled {
+		return 0, 0
+	}
+	if e.suspendTimeoutSeconds < 0 {
+		low = e.minCU * hoursPerMonth * computeUnitHourPriceUSD
+	}
+	return low, e.maxCU * hoursPerMonth * computeUnitHourPriceUSD
+}
+
+func (e computeCostEstimate) String() string {
+	low, high := e.cost()
+	o := fmt.Sprintf("$%.2f - $%.2f per month (%s - %s CU",
+		low, high, formatCU(e.minCU), formatCU(e.maxCU),
+	)
+	switch {
+	case e.disabled:
+		o += ", disabled"
+	case e.suspendTimeoutSeconds < 0:
+		o += ", never suspended"
+	case e.suspendTimeoutSeconds > 0:
+		o += ", suspended after " + strconv.FormatInt(e.suspendTimeoutSeconds, 10) + "s of inactivity"
+	default:
+		o += ", suspended after the default inactivity period"
+	}
+	return o + ")"
+}
+
+// costEstimateFromState reads the compute settings of the resource's state.
+// It returns false if the settings are unknown, or not defined.
+func costEstimateFromState(typeName string, v tftypes.Value) (computeCostEstimate, bool) {
+	attrs, ok := computeSettings(typeName, v)
+	if !ok {
+		return computeCostEstimate{}, false
+	}
+
+	var o computeCostEstimate
+	var suspendTimeout float64
+	if !attrAs(attrs, "autoscaling_limit_min_cu", &o.minCU) ||
+		!attrAs(attrs, "autoscaling_limit_max_cu", &o.maxCU) ||
+		!attrAs(attrs, "suspend_timeout_seconds", &suspendTimeout) {
+		return computeCostEstimate{}, false
+	}
+	o.suspendTimeoutSeconds = int64(suspendTimeout)
+	_ = attrAs(attrs, "disabled", &o.disabled)
+
+	return o, o.maxCU > 0
+}
+
+// storageCostEstimate defines the highest monthly storage cost of the project given its quota.
+// The zero limit means no quota, i.e. the cost is not bound.
+type storageCostEstimate struct {
+	// logicalSizeBytes defines the limit of the logical size of every project's branch.
+	logicalSizeBytes float64
+	// writtenDataBytes defines the limit of the data written to the project's branches per billing period.
+	writtenDataBytes        float64
+	historyRetentionSeconds int64
+}
+
+// cost returns the highest monthly cost of the branch's data, and of the history retained for the
+// point-in-time restore. The history is bound by the data written within the retention period,
+// assuming the data are written evenly throughout the billing period.
+func (e storageCostEstimate) cost() (data, history float64) {
+	data = e.logicalSizeBytes / bytesPerGiB * storageGiBMonthPriceUSD
+	retention := float64(e.historyRetentionSeconds)
+	if retention > secondsPerMonth {
+		retention = secondsPerMonth
+	}
+	history = e.writtenDataBytes * retention / secondsPerMonth / bytesPerGiB * storageGiBMonthPriceUSD
+	return data, history
+}
+
+func (e storageCostEstimate) String() string {
+	data, history := e.cost()
+
+	o := "data: not bound, no quota of the logical size"
+	if e.logicalSizeBytes > 0 {
+		o = fmt.Sprintf("data: up to $%.2f per month per branch (%g GiB logical size)",
+			data, e.logicalSizeBytes/bytesPerGiB)
+	}
+
+	retention := strconv.FormatInt(e.historyRetentionSeconds, 10) + "s"
+	if e.writtenDataBytes > 0 {
+		return o + fmt.Sprintf("; history: up to $%.2f per month (%s retention of %g GiB written data)",
+			history, retention, e.writtenDataBytes/bytesPerGiB)
+	}
+	return o + "; history: " + retention + " retention, not bound, no quota of the written data"
+}
+
+// storageCostEstimateFromState reads the history retention and the quota of the project's state.
+// It returns false if the history retention is unknown, or the resource is not the project.
+func storageCostEstimateFromState(typeName string, v tftypes.Value) (storageCostEstimate, bool) {
+	attrs, ok := objectAttributes(v)
+	if !ok || typeName != "neon_project" {
+		return storageCostEstimate{}, false
+	}
+
+	var retention float64
+	if !attrAs(attrs, "history_retention_seconds", &retention) {
+		return storageCostEstimate{}, false
+	}
+	o := storageCostEstimate{historyRetentionSeconds: int64(retention)}
+
+	var quota []tftypes.Value
+	if el, ok := attrs["quota"]; ok && el.IsFullyKnown() && el.As(&quota) == nil && len(quota) > 0 {
+		if q, ok := objectAttributes(quota[0]); ok {
+			_ = attrAs(q, "logical_size_bytes", &o.logicalSizeBytes)
+			_ = attrAs(q, "written_data_bytes", &o.writtenDataBytes)
+		}
+	}
+	return o, true
+}
+
+// computeSettings returns the attributes which define the endpoint's compute, i.e. the endpoint's attributes,
+// or the project's default endpoint settings.
+func computeSettings(typeName string, v tftypes.Value) (map[string]tftypes.Value, bool) {
+	attrs, ok := objectAttributes(v)
+	if !ok {
+		return nil, false
+	}
+
+	switch typeName {
+	case "neon_endpoint":
+		return attrs, true
+	case "neon_project":
+		var settings []tftypes.Value
+		if el, ok := attrs["default_endpoint_settings"]; !ok || !el.IsFullyKnown() || el.As(&settings) != nil ||
+			len(settings) == 0 {
+			return nil, false
+		}
+		return objectAttributes(settings[0])
+	default:
+		return nil, false
+	}
+}
+
+func objectAttributes(v tftypes.Value) (map[string]tftypes.Value, bool) {
+	if v.IsNull() || !v.IsKnown() {
+		return nil, false
+	}
+	var o map[string]tftypes.Value
+	if err := v.As(&o); err != nil {
+		return nil, false
+	}
+	return o, true
+}
+
+// attrAs reads the known and non-null attribute k to the pointer dst of *float64, or *bool types.
+func attrAs(attrs map[string]tftypes.Value, k string, dst interface{}) bool {
+	v, ok := attrs[k]
+	if !ok || v.IsNull() || !v.IsKnown() {
+		return false
+	}
+
+	switch ptr := dst.(type) {
+	case *float64:
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			return false
+		}
+		*ptr, _ = n.Float64()
+	case *bool:
+		if err := v.As(ptr); err != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// costEstimateDiagnostic returns the plan warning with the estimated monthly compute and storage cost of the resource.
+// It returns nil if the estimate is not changed by the plan.
+func costEstimateDiagnostic(typeName string, prior, planned tftypes.Value) *tfprotov5.Diagnostic {
+	var details []string
+	var changed bool
+
+	if estimate, ok := costEstimateFromState(typeName, planned); ok {
+		detail := "Estimated compute cost: " + estimate.String() + "."
+		previous, ok := costEstimateFromState(typeName, prior)
+		switch {
+		case !ok:
+			changed = true
+		case previous != estimate:
+			changed = true
+			detail += "\nPrevious estimate: " + previous.String() + "."
+		}
+		details = append(details, detail)
+	}
+
+	if estimate, ok := storageCostEstimateFromState(typeName, planned); ok {
+		detail := "Estimated storage cost: " + estimate.String() + "."
+		previous, ok := storageCostEstimateFromState(typeName, prior)
+		switch {
+		case !ok:
+			changed = true
+		case previous != estimate:
+			changed = true
+			detail += "\nPrevious estimate: " + previous.String() + "."
+		}
+		details = append(details, detail)
+	}
+
+	if !changed {
+		return nil
+	}
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Cost estimate of " + typeName,
+		Detail: strings.Join(details, "\n") + "\nThe estimate is based on the list price of " +
+			strconv.FormatFloat(computeUnitHourPriceUSD, 'f', -1, 64) + " USD per compute unit hour, and " +
+			strconv.FormatFloat(storageGiBMonthPriceUSD, 'f', -1, 64) + " USD per GiB-month of the storage.",
+	}
+}
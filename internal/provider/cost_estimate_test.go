@@ -0,0 +1,207 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_computeCostEstimate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name              string
+		estimate          computeCostEstimate
+		wantLow, wantHigh float64
+	}{
+		{
+			name:     "scales to zero",
+			estimate: computeCostEstimate{minCU: 0.25, maxCU: 1, suspendTimeoutSeconds: 300},
+			wantLow:  0,
+			wantHigh: 116.8,
+		},
+		{
+			name:     "never suspended",
+			estimate: computeCostEstimate{minCU: 0.25, maxCU: 1, suspendTimeoutSeconds: -1},
+			wantLow:  29.2,
+			wantHigh: 116.8,
+		},
+		{
+			name:     "disabled",
+			estimate: computeCostEstimate{minCU: 1, maxCU: 2, suspendTimeoutSeconds: -1, disabled: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high := tt.estimate.cost()
+			assert.InDelta(t, tt.wantLow, low, 1e-9)
+			assert.InDelta(t, tt.wantHigh, high, 1e-9)
+		})
+	}
+}
+
+func Test_storageCostEstimate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name                  string
+		estimate              storageCostEstimate
+		wantData, wantHistory float64
+		wantString            string
+	}{
+		{
+			name: "bound by the quota",
+			estimate: storageCostEstimate{
+				logicalSizeBytes: 10 * bytesPerGiB, writtenDataBytes: 30 * bytesPerGiB,
+				historyRetentionSeconds: secondsPerMonth / 10,
+			},
+			wantData:    1.2,
+			wantHistory: 0.36,
+			wantString: "data: up to $1.20 per month per branch (10 GiB logical size); " +
+				"history: up to $0.36 per month (262800s retention of 30 GiB written data)",
+		},
+		{
+			name: "retention longer than the billing period",
+			estimate: storageCostEstimate{
+				writtenDataBytes: 10 * bytesPerGiB, historyRetentionSeconds: 2 * secondsPerMonth,
+			},
+			wantHistory: 1.2,
+			wantString: "data: not bound, no quota of the logical size; " +
+				"history: up to $1.20 per month (5256000s retention of 10 GiB written data)",
+		},
+		{
+			name:     "no quota",
+			estimate: storageCostEstimate{historyRetentionSeconds: 86400},
+			wantString: "data: not bound, no quota of the logical size; " +
+				"history: 86400s retention, not bound, no quota of the written data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, history := tt.estimate.cost()
+			assert.InDelta(t, tt.wantData, data, 1e-9)
+			assert.InDelta(t, tt.wantHistory, history, 1e-9)
+			assert.Equal(t, tt.wantString, tt.estimate.String())
+		})
+	}
+}
+
+func Test_costEstimateDiagnostic(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	endpointType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"autoscaling_limit_min_cu": tftypes.Number,
+		"autoscaling_limit_max_cu": tftypes.Number,
+		"suspend_timeout_seconds":  tftypes.Number,
+		"disabled":                 tftypes.Bool,
+	}}
+	newEndpoint := func(minCU, maxCU interface{}, suspendTimeout int) tftypes.Value {
+		return tftypes.NewValue(endpointType, map[string]tftypes.Value{
+			"autoscaling_limit_min_cu": tftypes.NewValue(tftypes.Number, minCU),
+			"autoscaling_limit_max_cu": tftypes.NewValue(tftypes.Number, maxCU),
+			"suspend_timeout_seconds":  tftypes.NewValue(tftypes.Number, suspendTimeout),
+			"disabled":                 tftypes.NewValue(tftypes.Bool, false),
+		})
+	}
+	null := tftypes.NewValue(endpointType, nil)
+
+	t.Run("shall estimate the cost of the new endpoint", func(t *testing.T) {
+		// WHEN
+		got := costEstimateDiagnostic("neon_endpoint", null, newEndpoint(0.25, 1, -1))
+
+		// THEN
+		if assert.NotNil(t, got) {
+			assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, got.Severity)
+			assert.Contains(t, got.Detail, "$29.20 - $116.80 per month (0.25 - 1 CU, never suspended)")
+			assert.False(t, strings.Contains(got.Detail, "Previous estimate"))
+		}
+	})
+
+	t.Run("shall report the previous estimate", func(t *testing.T) {
+		// WHEN
+		got := costEstimateDiagnostic("neon_endpoint", newEndpoint(0.25, 1, 300), newEndpoint(0.25, 2, 300))
+
+		// THEN
+		if assert.NotNil(t, got) {
+			assert.Contains(t, got.Detail, "$0.00 - $233.60 per month")
+			assert.Contains(t, got.Detail, "Previous estimate: $0.00 - $116.80 per month")
+		}
+	})
+
+	t.Run("shall skip the unchanged estimate", func(t *testing.T) {
+		assert.Nil(t, costEstimateDiagnostic("neon_endpoint", newEndpoint(0.25, 1, 300), newEndpoint(0.25, 1, 300)))
+	})
+
+	t.Run("shall skip the unknown compute settings", func(t *testing.T) {
+		assert.Nil(t, costEstimateDiagnostic("neon_endpoint", null, newEndpoint(0.25, tftypes.UnknownValue, 300)))
+	})
+
+	t.Run("shall estimate the cost of the project's default endpoint", func(t *testing.T) {
+		// GIVEN
+		projectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"default_endpoint_settings": tftypes.List{ElementType: endpointType},
+		}}
+		planned := tftypes.NewValue(projectType, map[string]tftypes.Value{
+			"default_endpoint_settings": tftypes.NewValue(
+				tftypes.List{ElementType: endpointType}, []tftypes.Value{newEndpoint(1, 1, 0)},
+			),
+		})
+
+		// WHEN
+		got := costEstimateDiagnostic("neon_project", tftypes.NewValue(projectType, nil), planned)
+
+		// THEN
+		if assert.NotNil(t, got) {
+			assert.Contains(t, got.Detail, "$0.00 - $116.80 per month (1 - 1 CU, suspended after the default inactivity period)")
+		}
+	})
+
+	t.Run("shall estimate the storage cost of the project", func(t *testing.T) {
+		// GIVEN
+		quotaType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"logical_size_bytes": tftypes.Number,
+			"written_data_bytes": tftypes.Number,
+		}}
+		projectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"history_retention_seconds": tftypes.Number,
+			"quota":                     tftypes.List{ElementType: quotaType},
+		}}
+		newProject := func(retention int, logicalSize int64) tftypes.Value {
+			return tftypes.NewValue(projectType, map[string]tftypes.Value{
+				"history_retention_seconds": tftypes.NewValue(tftypes.Number, retention),
+				"quota": tftypes.NewValue(tftypes.List{ElementType: quotaType}, []tftypes.Value{
+					tftypes.NewValue(quotaType, map[string]tftypes.Value{
+						"logical_size_bytes": tftypes.NewValue(tftypes.Number, logicalSize),
+						"written_data_bytes": tftypes.NewValue(tftypes.Number, 0),
+					}),
+				}),
+			})
+		}
+
+		// WHEN
+		got := costEstimateDiagnostic("neon_project", newProject(86400, bytesPerGiB), newProject(604800, bytesPerGiB))
+
+		// THEN
+		if assert.NotNil(t, got) {
+			assert.Contains(t, got.Detail, "Estimated storage cost: data: up to $0.12 per month per branch (1 GiB logical size); "+
+				"history: 604800s retention, not bound")
+			assert.Contains(t, got.Detail, "Previous estimate: data: up to $0.12 per month per branch (1 GiB logical size); "+
+				"history: 86400s retention")
+			assert.Contains(t, got.Detail, "0.12 USD per GiB-month of the storage")
+		}
+		assert.Nil(t, costEstimateDiagnostic("neon_project", newProject(86400, bytesPerGiB), newProject(86400, bytesPerGiB)))
+	})
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// costGuardrails defines the limits of the compute size, the zero value means no limit.
+type costGuardrails struct {
+	maxTotalCU    float64
+	maxEndpointCU float64
+}
+
+func newCostGuardrails(v []interface{}) costGuardrails {
+	if len(v) == 0 || v[0] == nil {
+		return costGuardrails{}
+	}
+	m := v[0].(map[string]interface{})
+	return costGuardrails{
+		maxTotalCU:    m["max_total_cu"].(float64),
+		maxEndpointCU: m["max_endpoint_cu"].(float64),
+	}
+}
+
+// plannedComputeUnits accumulates the maximum autoscaling limits of the endpoints planned by the provider's
+// instance, i.e. by single plan of the configuration.
+type plannedComputeUnits struct {
+	mu sync.Mutex
+	// byResource defines the limit per resource to account every existing resource once, if it's planned repeatedly.
+	byResource map[string]float64
+	cntNew     int
+}
+
+// check adds the planned endpoint's limit to the total, and returns the error if any of the guardrails is exceeded.
+func (p *plannedComputeUnits) check(g costGuardrails, typeName string, planned tftypes.Value) *tfprotov5.Diagnostic {
+	if g == (costGuardrails{}) {
+		return nil
+	}
+
+	attrs, ok := computeSettings(typeName, planned)
+	var maxCU float64
+	if !ok || !attrAs(attrs, "autoscaling_limit_max_cu", &maxCU) {
+		return nil
+	}
+
+	if g.maxEndpointCU > 0 && maxCU > g.maxEndpointCU {
+		return &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Cost guardrail exceeded",
+			Detail: "The maximum autoscaling limit " + formatCU(maxCU) + " CU of " + typeName +
+				" exceeds the provider's cost_guardrails.max_endpoint_cu " + formatCU(g.maxEndpointCU) + " CU.",
+		}
+	}
+
+	total := p.add(typeName, plannedID(planned), maxCU)
+	if g.maxTotalCU > 0 && total > g.maxTotalCU {
+		return &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Cost guardrail exceeded",
+			Detail: "The sum of the maximum autoscaling limits of the planned endpoints " + formatCU(total) +
+				" CU exceeds the provider's cost_guardrails.max_total_cu " + formatCU(g.maxTotalCU) + " CU.",
+		}
+	}
+	return nil
+}
+
+// add stores the resource's limit and returns the total of all resources.
+// The resource without ID, i.e. the resource to create, is stored as a new entry upon every plan because the plan's
+// request defines no resource's address, hence the identical new resources, e.g. created with count, are told apart.
+func (p *plannedComputeUnits) add(typeName, id string, cu float64) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.byResource == nil {
+		p.byResource = map[string]float64{}
+	}
+	if id == "" {
+		p.cntNew++
+		id = "new-" + strconv.Itoa(p.cntNew)
+	}
+	p.byResource[typeName+"/"+id] = cu
+
+	var o float64
+	for _, v := range p.byResource {
+		o += v
+	}
+	return o
+}
+
+func plannedID(v tftypes.Value) string {
+	attrs, ok := objectAttributes(v)
+	if !ok {
+		return ""
+	}
+	var id string
+	if el, ok := attrs["id"]; ok && el.IsKnown() && !el.IsNull() {
+		_ = el.As(&id)
+	}
+	return id
+}
+
+func formatCU(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,103 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_plannedComputeUnits_check(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	endpointType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                       tftypes.String,
+		"autoscaling_limit_min_cu": tftypes.Number,
+		"autoscaling_limit_max_cu": tftypes.Number,
+		"suspend_timeout_seconds":  tftypes.Number,
+	}}
+	newEndpoint := func(id interface{}, maxCU float64) tftypes.Value {
+		return tftypes.NewValue(endpointType, map[string]tftypes.Value{
+			"id":                       tftypes.NewValue(tftypes.String, id),
+			"autoscaling_limit_min_cu": tftypes.NewValue(tftypes.Number, 0.25),
+			"autoscaling_limit_max_cu": tftypes.NewValue(tftypes.Number, maxCU),
+			"suspend_timeout_seconds":  tftypes.NewValue(tftypes.Number, 300),
+		})
+	}
+
+	t.Run("shall fail if the endpoint's limit is exceeded", func(t *testing.T) {
+		// GIVEN
+		var p plannedComputeUnits
+
+		// WHEN
+		got := p.check(costGuardrails{maxEndpointCU: 2}, "neon_endpoint", newEndpoint("ep-foo", 4))
+
+		// THEN
+		if assert.NotNil(t, got) {
+			assert.Equal(t, tfprotov5.DiagnosticSeverityError, got.Severity)
+			assert.Contains(t, got.Detail, "max_endpoint_cu 2 CU")
+		}
+	})
+
+	t.Run("shall fail if the total limit is exceeded", func(t *testing.T) {
+		// GIVEN
+		var p plannedComputeUnits
+		g := costGuardrails{maxTotalCU: 4}
+
+		// WHEN
+		first := p.check(g, "neon_endpoint", newEndpoint("ep-foo", 2))
+		// the repeated plan of the same endpoint is accounted once
+		repeated := p.check(g, "neon_endpoint", newEndpoint("ep-foo", 2))
+		second := p.check(g, "neon_endpoint", newEndpoint(tftypes.UnknownValue, 2))
+		third := p.check(g, "neon_endpoint", newEndpoint(tftypes.UnknownValue, 1))
+
+		// THEN
+		assert.Nil(t, first)
+		assert.Nil(t, repeated)
+		assert.Nil(t, second)
+		if assert.NotNil(t, third) {
+			assert.Contains(t, third.Detail, "planned endpoints 5 CU exceeds the provider's cost_guardrails.max_total_cu 4 CU")
+		}
+	})
+
+	t.Run("shall account the identical new endpoints separately", func(t *testing.T) {
+		// GIVEN e.g. the endpoints created with count, their planned states are identical
+		var p plannedComputeUnits
+		g := costGuardrails{maxTotalCU: 3}
+
+		// WHEN
+		first := p.check(g, "neon_endpoint", newEndpoint(tftypes.UnknownValue, 2))
+		second := p.check(g, "neon_endpoint", newEndpoint(tftypes.UnknownValue, 2))
+
+		// THEN
+		assert.Nil(t, first)
+		if assert.NotNil(t, second) {
+			assert.Contains(t, second.Detail, "planned endpoints 4 CU exceeds the provider's cost_guardrails.max_total_cu 3 CU")
+		}
+		assert.Len(t, p.byResource, 2)
+	})
+
+	t.Run("shall skip if no guardrails are set", func(t *testing.T) {
+		var p plannedComputeUnits
+		assert.Nil(t, p.check(costGuardrails{}, "neon_endpoint", newEndpoint("ep-foo", 10)))
+		assert.Empty(t, p.byResource)
+	})
+}
+
+func Test_newCostGuardrails(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	assert.Equal(t, costGuardrails{}, newCostGuardrails(nil))
+	assert.Equal(t, costGuardrails{maxTotalCU: 16, maxEndpointCU: 4}, newCostGuardrails([]interface{}{
+		map[string]interface{}{"max_total_cu": 16., "max_endpoint_cu": 4.},
+	}))
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceAccount() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the account of the API key's owner, e.g. to assert the account's plan before creating resources.
+
+See details: https://neon.tech/docs/introduction/plans`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceAccountRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User ID.",
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "User email.",
+			},
+			"plan": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Billing plan of the account, e.g. free.",
+			},
+			"billing_email": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Email to receive the invoices and the notifications about the subscription.",
+			},
+			"billing_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State of the billing account, e.g. active.",
+			},
+			"subscription_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the subscription, e.g. launch.",
+			},
+			"projects_limit": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of projects.",
+			},
+			"branches_limit": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of branches per project.",
+			},
+			"max_autoscaling_limit_cu": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Maximum autoscaling limit of the endpoints in compute units.",
+			},
+			"active_seconds_limit": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum active time of the endpoints per billing period in seconds.",
+			},
+			"compute_seconds_limit": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum compute time of the endpoints per billing period in seconds, 0 if not limited.",
+			},
+		},
+	}
+}
+
+func dataSourceAccountRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Account")
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetCurrentUserInfo()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resp.ID)
+	if err := updateStateAccount(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+func updateStateAccount(d *schema.ResourceData, v neon.CurrentUserInfoResponse) error {
+	var computeSecondsLimit int64
+	if v.ComputeSecondsLimit != nil {
+		computeSecondsLimit = *v.ComputeSecondsLimit
+	}
+
+	for k, val := range map[string]interface{}{
+		"email":                    v.Email,
+		"plan":                     v.Plan,
+		"billing_email":            v.BillingAccount.Email,
+		"billing_state":            string(v.BillingAccount.State),
+		"subscription_type":        string(v.BillingAccount.SubscriptionType),
+		"projects_limit":           int(v.ProjectsLimit),
+		"branches_limit":           int(v.BranchesLimit),
+		"max_autoscaling_limit_cu": float64(v.MaxAutoscalingLimit),
+		"active_seconds_limit":     int(v.ActiveSecondsLimit),
+		"compute_seconds_limit":    int(computeSecondsLimit),
+	} {
+		if err := d.Set(k, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateStateAccount(t *testing.T) {
+	d := dataSourceAccount().TestResourceData()
+
+	err := updateStateAccount(d, neon.CurrentUserInfoResponse{
+		ID:    "foo-123",
+		Email: "foo@example.com",
+		Plan:  "launch",
+		BillingAccount: neon.BillingAccount{
+			Email:            "billing@example.com",
+			State:            "active",
+			SubscriptionType: "launch",
+		},
+		ProjectsLimit:       100,
+		BranchesLimit:       500,
+		MaxAutoscalingLimit: 4,
+		ActiveSecondsLimit:  360000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, "foo@example.com", d.Get("email"))
+	assert.Equal(t, "launch", d.Get("plan"))
+	assert.Equal(t, "billing@example.com", d.Get("billing_email"))
+	assert.Equal(t, "active", d.Get("billing_state"))
+	assert.Equal(t, "launch", d.Get("subscription_type"))
+	assert.Equal(t, 100, d.Get("projects_limit"))
+	assert.Equal(t, 500, d.Get("branches_limit"))
+	assert.Equal(t, 4., d.Get("max_autoscaling_limit_cu"))
+	assert.Equal(t, 360000, d.Get("active_seconds_limit"))
+	assert.Equal(t, 0, d.Get("compute_seconds_limit"))
+}
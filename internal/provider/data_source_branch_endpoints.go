@@ -6,7 +6,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	neon "github.com/kislerdm/neon-sdk-go"
 )
 
 func dataSourceBranchEndpoints() *schema.Resource {
@@ -54,6 +53,16 @@ func dataSourceBranchEndpoints() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"compute_provisioner": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Provisioner of the compute, e.g. k8s-neonvm.",
+						},
+						"creation_source": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Source of the endpoint creation, e.g. console.",
+						},
 					},
 				},
 			},
@@ -69,7 +78,11 @@ func dataSourceBranchEndpointsRead(ctx context.Context, d *schema.ResourceData,
 
 	d.SetId(projectID + "/" + branchID)
 
-	resp, err := meta.(*neon.Client).ListProjectBranchEndpoints(
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectBranchEndpoints(
 		projectID,
 		branchID,
 	)
@@ -80,11 +93,13 @@ func dataSourceBranchEndpointsRead(ctx context.Context, d *schema.ResourceData,
 	var endpoints []map[string]interface{}
 	for _, v := range resp.Endpoints {
 		endpoints = append(endpoints, map[string]interface{}{
-			"id":         v.ID,
-			"host":       v.Host,
-			"type":       string(v.Type),
-			"region_id":  v.RegionID,
-			"proxy_host": v.ProxyHost,
+			"id":                  v.ID,
+			"host":                v.Host,
+			"type":                string(v.Type),
+			"region_id":           v.RegionID,
+			"proxy_host":          v.ProxyHost,
+			"compute_provisioner": string(v.Provisioner),
+			"creation_source":     v.CreationSource,
 		})
 	}
 
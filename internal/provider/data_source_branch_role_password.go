@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	neon "github.com/kislerdm/neon-sdk-go"
 )
 
 func dataSourceBranchRolePassword() *schema.Resource {
@@ -50,7 +49,11 @@ func dataSourceBranchRolePasswordRead(ctx context.Context, d *schema.ResourceDat
 
 	d.SetId(fmt.Sprintf("%s/%s/%s/password", projectID, branchID, roleName))
 
-	resp, err := meta.(*neon.Client).GetProjectBranchRolePassword(
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetProjectBranchRolePassword(
 		projectID,
 		branchID,
 		roleName,
@@ -7,7 +7,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	neon "github.com/kislerdm/neon-sdk-go"
 )
 
 func dataSourceBranchRoles() *schema.Resource {
@@ -55,7 +54,11 @@ func dataSourceBranchRolesRead(ctx context.Context, d *schema.ResourceData, meta
 
 	d.SetId(fmt.Sprintf("%s/%s/roles", projectID, branchID))
 
-	resp, err := meta.(*neon.Client).ListProjectBranchRoles(
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectBranchRoles(
 		projectID,
 		branchID,
 	)
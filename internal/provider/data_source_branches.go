@@ -15,7 +15,7 @@ func dataSourceBranches() *schema.Resource {
 		Description:   "Fetch Project Branches.",
 		SchemaVersion: 1,
 		ReadContext:   dataSourceBranchesRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withConsumptionExport(map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -25,7 +25,7 @@ func dataSourceBranches() *schema.Resource {
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
+					Schema: withConsumptionMetrics(map[string]*schema.Schema{
 						"id": {
 							Type:        schema.TypeString,
 							Computed:    true,
@@ -54,9 +54,12 @@ func dataSourceBranches() *schema.Resource {
 							Description: "Primary branch flag.",
 						},
 					},
+						"active_time_seconds", "compute_time_seconds", "written_data_bytes", "data_transfer_bytes",
+					),
 				},
 			},
-		},
+		}, "branches'", `"branch" to render the metrics per branch,
+or "project" to render the sum of the metrics of all branches`, "branch", "project"),
 	}
 }
 
@@ -68,9 +71,25 @@ func dataSourceBranchesRead(ctx context.Context, d *schema.ResourceData, meta in
 	d.SetId(fmt.Sprintf("%s/branches", projectID))
 
 	// TODO: add search qualifier for branches
-	resp, err := meta.(*neon.Client).ListProjectBranches(projectID, nil)
+	client, err := providerClientFromMeta(meta)
 	if err != nil {
-		diag.FromErr(err)
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if v, ok := d.GetOk("export_format"); ok {
+		export, err := exportBranchesConsumption(
+			projectID, resp.Branches, v.(string), d.Get("export_group_by").(string),
+		)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("export", export); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	var branches []map[string]interface{}
@@ -84,13 +103,17 @@ func dataSourceBranchesRead(ctx context.Context, d *schema.ResourceData, meta in
 			logicalSize = *v.LogicalSize
 		}
 
-		branches = append(branches, map[string]interface{}{
+		branch := map[string]interface{}{
 			"id":           v.ID,
 			"name":         v.Name,
 			"parent_id":    parentID,
 			"logical_size": logicalSize,
 			"primary":      v.Primary,
-		})
+		}
+		for k, v := range branchConsumptionMetrics(v) {
+			branch[k] = v
+		}
+		branches = append(branches, branch)
 	}
 
 	if err := d.Set("branches", branches); err != nil {
@@ -99,3 +122,31 @@ func dataSourceBranchesRead(ctx context.Context, d *schema.ResourceData, meta in
 
 	return diag.FromErr(nil)
 }
+
+// exportBranchesConsumption renders the branches' consumption metrics in the format json, or csv.
+// The metrics are rendered per branch, or summed up for the project given the grouping groupBy.
+func exportBranchesConsumption(projectID string, branches []neon.Branch, format, groupBy string) (string, error) {
+	var (
+		keys []string
+		rows [][]string
+		sum  = map[string]int64{}
+	)
+	switch groupBy {
+	case "project":
+		keys = []string{"project_id"}
+		for _, br := range branches {
+			for k, v := range branchConsumptionMetrics(br) {
+				sum[k] += v
+			}
+		}
+		rows = append(rows, consumptionRow([]string{projectID}, sum, consumptionMetricsColumns))
+	default:
+		keys = []string{"branch_id", "name"}
+		for _, br := range branches {
+			rows = append(rows, consumptionRow(
+				[]string{br.ID, br.Name}, branchConsumptionMetrics(br), consumptionMetricsColumns,
+			))
+		}
+	}
+	return exportConsumption(format, keys, consumptionMetricsColumns, rows)
+}
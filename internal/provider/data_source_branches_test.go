@@ -0,0 +1,101 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_dataSourceBranchesRead_error(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			return newHTTPResponse(http.StatusNotFound, `{"code":"","message":"project not found"}`), nil
+		}),
+	})
+
+	d := dataSourceBranches().TestResourceData()
+	_ = d.Set("project_id", "bar")
+
+	// WHEN
+	diags := dataSourceBranchesRead(context.TODO(), d, &providerClient{Client: client})
+
+	// THEN
+	if !diags.HasError() {
+		t.Error("the listing error shall be returned")
+	}
+}
+
+func Test_exportBranchesConsumption(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	branches := []neon.Branch{
+		{ID: "br-foo", Name: "main", ActiveTimeSeconds: 100, ComputeTimeSeconds: 10, WrittenDataBytes: 1000},
+		{ID: "br-bar", Name: "pr-1", ActiveTimeSeconds: 50, DataTransferBytes: 20},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		groupBy string
+		want    string
+	}{
+		{
+			name:    "csv per branch",
+			format:  "csv",
+			groupBy: "branch",
+			want: `branch_id,name,active_time_seconds,compute_time_seconds,written_data_bytes,data_transfer_bytes
+br-foo,main,100,10,1000,0
+br-bar,pr-1,50,0,0,20
+`,
+		},
+		{
+			name:    "csv per project",
+			format:  "csv",
+			groupBy: "project",
+			want: `project_id,active_time_seconds,compute_time_seconds,written_data_bytes,data_transfer_bytes
+shiny-wind-028834,150,10,1000,20
+`,
+		},
+		{
+			name:    "json per branch",
+			format:  "json",
+			groupBy: "branch",
+			want: `[{"active_time_seconds":100,"branch_id":"br-foo","compute_time_seconds":10,` +
+				`"data_transfer_bytes":0,"name":"main","written_data_bytes":1000},` +
+				`{"active_time_seconds":50,"branch_id":"br-bar","compute_time_seconds":0,` +
+				`"data_transfer_bytes":20,"name":"pr-1","written_data_bytes":0}]`,
+		},
+		{
+			name:    "json per project",
+			format:  "json",
+			groupBy: "project",
+			want: `[{"active_time_seconds":150,"compute_time_seconds":10,"data_transfer_bytes":20,` +
+				`"project_id":"shiny-wind-028834","written_data_bytes":1000}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exportBranchesConsumption("shiny-wind-028834", branches, tt.format, tt.groupBy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected export:\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
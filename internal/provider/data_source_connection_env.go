@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceConnectionEnv() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the connection details as the map of environment variables,
+e.g. to inject them into the container, or to the Kubernetes secret.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceConnectionEnvRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Branch ID.",
+			},
+			"role_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Role name.",
+			},
+			"database_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Database name.",
+			},
+			"endpoint_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: `Endpoint ID. The branch's default read-write endpoint is used if not set.
+**Note** that the endpoint must belong to the branch.`,
+			},
+			"pooled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set to true to connect using the connection pooler.",
+			},
+			"env": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Description: `Environment variables: DATABASE_URL, PGHOST, PGUSER, PGPASSWORD, PGDATABASE.
+**Note** that it contains access credentials.`,
+			},
+		},
+	}
+}
+
+func dataSourceConnectionEnvRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read connection env")
+
+	projectID := d.Get("project_id").(string)
+	branchID := d.Get("branch_id").(string)
+	roleName := d.Get("role_name").(string)
+	dbName := d.Get("database_name").(string)
+	endpointID := d.Get("endpoint_id").(string)
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoints, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoint, err := findBranchEndpoint(endpoints.Endpoints, branchID, endpointID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resp, err := client.GetProjectBranchRolePassword(projectID, branchID, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	info := dbConnectionInfo{
+		userName:   roleName,
+		pass:       resp.Password,
+		dbName:     dbName,
+		host:       endpoint.Host,
+		endpointID: endpoint.ID,
+	}
+	if d.Get("pooled").(bool) {
+		info.host = poolerHost(info.host)
+	}
+
+	d.SetId(projectID + "/" + branchID + "/" + endpoint.ID + "/" + roleName + "/" + dbName)
+	if err := d.Set("env", connectionEnv(info)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+// findBranchEndpoint returns the branch's endpoint endpointID, or the default read-write endpoint if endpointID is empty.
+func findBranchEndpoint(endpoints []neon.Endpoint, branchID, endpointID string) (neon.Endpoint, error) {
+	var endpoint neon.Endpoint
+	if endpointID == "" {
+		endpoint = findDefaultEndpoint(endpoints, branchID)
+	} else {
+		for _, v := range endpoints {
+			if v.ID == endpointID {
+				endpoint = v
+				break
+			}
+		}
+	}
+	if endpoint.ID == "" {
+		return endpoint, errors.New("no endpoint found for the branch " + branchID)
+	}
+	return endpoint, nil
+}
+
+func connectionEnv(info dbConnectionInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"DATABASE_URL": info.connectionURI(),
+		"PGHOST":       info.host,
+		"PGUSER":       info.userName,
+		"PGPASSWORD":   info.pass,
+		"PGDATABASE":   info.dbName,
+	}
+}
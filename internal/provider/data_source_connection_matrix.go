@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceConnectionMatrix() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the connection URIs of all combinations of the branch's databases and roles,
+e.g. to configure the application which uses many databases with a single data source.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceConnectionMatrixRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Branch ID.",
+			},
+			"pooled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Set to true to connect using the connection pooler.",
+			},
+			"connection_uris": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Description: `Connection URIs to the branch's default read-write endpoint by the key <database>/<role>.
+The protected roles are omitted. **Note** that it contains access credentials.`,
+			},
+		},
+	}
+}
+
+func dataSourceConnectionMatrixRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read connection matrix")
+
+	projectID := d.Get("project_id").(string)
+	branchID := d.Get("branch_id").(string)
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoints, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoint, err := findBranchEndpoint(endpoints.Endpoints, branchID, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	host := endpoint.Host
+	if d.Get("pooled").(bool) {
+		host = poolerHost(host)
+	}
+
+	databases, err := client.ListProjectBranchDatabases(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roles, err := client.ListProjectBranchRoles(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	passwords := map[string]string{}
+	for _, v := range roles.Roles {
+		if v.Protected != nil && *v.Protected {
+			continue
+		}
+		resp, err := client.GetProjectBranchRolePassword(projectID, branchID, v.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		passwords[v.Name] = resp.Password
+	}
+
+	d.SetId(projectID + "/" + branchID + "/" + endpoint.ID)
+	if err := d.Set("connection_uris", connectionMatrix(host, databases.Databases, passwords)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+// connectionMatrix returns the connection URIs by the key <database>/<role> given the roles' passwords.
+func connectionMatrix(host string, databases []neon.Database, passwords map[string]string) map[string]interface{} {
+	o := make(map[string]interface{}, len(databases)*len(passwords))
+	for _, db := range databases {
+		for role, pass := range passwords {
+			info := dbConnectionInfo{userName: role, pass: pass, dbName: db.Name, host: host}
+			o[db.Name+"/"+role] = info.connectionURI()
+		}
+	}
+	return o
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceDefaultEndpoint() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the branch's default read-write Endpoint, e.g. to refer to the endpoint created
+together with the project, or the branch without importing it.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceDefaultEndpointRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Branch ID.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Endpoint URI.",
+			},
+			"pooler_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host to connect to the endpoint using the connection pooler.",
+			},
+			"autoscaling_limit_min_cu": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Minimum number of Compute Units.",
+			},
+			"autoscaling_limit_max_cu": {
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "Maximum number of Compute Units.",
+			},
+			"suspend_timeout_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Duration of inactivity in seconds after which the compute endpoint is suspended.",
+			},
+			"compute_provisioner": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Provisioner of the compute, e.g. k8s-neonvm.",
+			},
+		},
+	}
+}
+
+func dataSourceDefaultEndpointRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read default Endpoint")
+
+	projectID := d.Get("project_id").(string)
+	branchID := d.Get("branch_id").(string)
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoint, err := findBranchEndpoint(resp.Endpoints, branchID, "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(endpoint.ID)
+	if err := updateStateDefaultEndpoint(d, endpoint); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+func updateStateDefaultEndpoint(d *schema.ResourceData, v neon.Endpoint) error {
+	if err := d.Set("host", v.Host); err != nil {
+		return err
+	}
+	if err := d.Set("pooler_host", poolerHost(v.Host)); err != nil {
+		return err
+	}
+	if err := d.Set("autoscaling_limit_min_cu", float64(v.AutoscalingLimitMinCu)); err != nil {
+		return err
+	}
+	if err := d.Set("autoscaling_limit_max_cu", float64(v.AutoscalingLimitMaxCu)); err != nil {
+		return err
+	}
+	if err := d.Set("suspend_timeout_seconds", int64(v.SuspendTimeoutSeconds)); err != nil {
+		return err
+	}
+	return d.Set("compute_provisioner", string(v.Provisioner))
+}
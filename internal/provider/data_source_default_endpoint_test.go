@@ -0,0 +1,34 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateStateDefaultEndpoint(t *testing.T) {
+	d := dataSourceDefaultEndpoint().TestResourceData()
+
+	err := updateStateDefaultEndpoint(d, neon.Endpoint{
+		ID:                    "ep-cool-darkness-123456",
+		Host:                  "ep-cool-darkness-123456.us-east-2.aws.neon.tech",
+		AutoscalingLimitMinCu: 0.25,
+		AutoscalingLimitMaxCu: 2,
+		SuspendTimeoutSeconds: 300,
+		Provisioner:           "k8s-neonvm",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, "ep-cool-darkness-123456.us-east-2.aws.neon.tech", d.Get("host"))
+	assert.Equal(t, "ep-cool-darkness-123456-pooler.us-east-2.aws.neon.tech", d.Get("pooler_host"))
+	assert.Equal(t, 0.25, d.Get("autoscaling_limit_min_cu"))
+	assert.Equal(t, 2., d.Get("autoscaling_limit_max_cu"))
+	assert.Equal(t, 300, d.Get("suspend_timeout_seconds"))
+	assert.Equal(t, "k8s-neonvm", d.Get("compute_provisioner"))
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	endpointHealthCheckTCP = "tcp"
+	endpointHealthCheckTLS = "tls"
+)
+
+func dataSourceEndpointHealth() *schema.Resource {
+	return &schema.Resource{
+		Description: `Check whether the endpoint accepts the connections, and measure the latency.
+The check runs upon every read, e.g. to run the smoke tests using the ` + "`check`" + ` blocks.
+**Note** that the unreachable endpoint does not fail the read, it's reported by the attribute ` + "`reachable`" + `.
+**Note** that the endpoint suspended due to inactivity is woken up by the connection.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceEndpointHealthRead,
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Endpoint host, e.g. the attribute `host` of the resource `neon_endpoint`.",
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5432,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "Port to connect to.",
+			},
+			"check": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      endpointHealthCheckTLS,
+				ValidateFunc: validation.StringInSlice([]string{endpointHealthCheckTCP, endpointHealthCheckTLS}, false),
+				Description: `Type of the check: ` + "`tcp`" + ` to open the TCP connection,
+` + "`tls`" + ` to negotiate the TLS session using the Postgres protocol, and verify the server's certificate.`,
+			},
+			"timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Timeout of the check in seconds.",
+			},
+			"reachable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the check succeeded.",
+			},
+			"latency_ms": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Duration of the check in milliseconds.",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Reason of the failed check. Empty if the check succeeded.",
+			},
+		},
+	}
+}
+
+func dataSourceEndpointHealthRead(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Endpoint Health")
+
+	addr := net.JoinHostPort(d.Get("host").(string), strconv.Itoa(d.Get("port").(int)))
+	timeout := time.Duration(d.Get("timeout_seconds").(int)) * time.Second
+
+	latency, err := checkEndpointHealth(ctx, addr, d.Get("check").(string), timeout)
+
+	d.SetId(addr)
+	if err := d.Set("reachable", err == nil); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("latency_ms", int(latency.Milliseconds())); err != nil {
+		return diag.FromErr(err)
+	}
+	var msg string
+	if err != nil {
+		tflog.Warn(ctx, "endpoint health check failed", map[string]interface{}{"addr": addr, "error": err.Error()})
+		msg = err.Error()
+	}
+	return diag.FromErr(d.Set("error", msg))
+}
+
+// checkEndpointHealth connects to the endpoint, and negotiates the TLS session if the check is tls.
+// It returns the duration of the check.
+func checkEndpointHealth(ctx context.Context, addr, check string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return time.Since(start), err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if check == endpointHealthCheckTLS {
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+		if err := negotiateTLS(ctx, conn, addr); err != nil {
+			return time.Since(start), err
+		}
+	}
+
+	return time.Since(start), nil
+}
+
+// negotiateTLS requests the TLS session using the Postgres protocol, and runs the TLS handshake.
+// See details: https://www.postgresql.org/docs/current/protocol-flow.html#PROTOCOL-FLOW-SSL
+func negotiateTLS(ctx context.Context, conn net.Conn, addr string) error {
+	const sslRequestCode = 80877103
+
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[:4], 8)
+	binary.BigEndian.PutUint32(req[4:], sslRequestCode)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+	if resp[0] != 'S' {
+		return errors.New("the server does not support TLS")
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	return tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}).HandshakeContext(ctx)
+}
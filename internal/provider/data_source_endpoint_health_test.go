@@ -0,0 +1,75 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkEndpointHealth(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	// the server accepts the connections, and declines the TLS requests
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			req := make([]byte, 8)
+			if _, err := conn.Read(req); err == nil {
+				_, _ = conn.Write([]byte("N"))
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	ctx := context.TODO()
+
+	t.Run("shall succeed the tcp check", func(t *testing.T) {
+		// WHEN
+		_, err := checkEndpointHealth(ctx, l.Addr().String(), endpointHealthCheckTCP, time.Second)
+
+		// THEN
+		assert.NoError(t, err)
+	})
+
+	t.Run("shall fail the tls check if the server does not support TLS", func(t *testing.T) {
+		// WHEN
+		_, err := checkEndpointHealth(ctx, l.Addr().String(), endpointHealthCheckTLS, time.Second)
+
+		// THEN
+		assert.EqualError(t, err, "the server does not support TLS")
+	})
+
+	t.Run("shall fail the check if the endpoint is unreachable", func(t *testing.T) {
+		// GIVEN
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := closed.Addr().String()
+		_ = closed.Close()
+
+		// WHEN
+		_, err = checkEndpointHealth(ctx, addr, endpointHealthCheckTCP, time.Second)
+
+		// THEN
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceIdleEndpoints() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the project's endpoints which are idle longer than the given duration,
+e.g. to clean up the abandoned preview environments.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceIdleEndpointsRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"idle_for_seconds": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: intValidationNotNegative,
+				Description: `Minimum duration in seconds since the endpoint was active last time.
+The endpoint which never was active is accounted since its creation.`,
+			},
+			"endpoints": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Endpoint ID.",
+						},
+						"branch_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Branch ID.",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Endpoint URI.",
+						},
+						"current_state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Endpoint state, e.g. idle.",
+						},
+						"last_active": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timestamp when the endpoint was active last time, RFC3339. Empty if it never was active.",
+						},
+						"idle_seconds": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Duration in seconds since the endpoint was active last time.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIdleEndpointsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Idle Endpoints")
+
+	projectID := d.Get("project_id").(string)
+	idleFor := time.Duration(d.Get("idle_for_seconds").(int)) * time.Second
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectEndpoints(projectID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(projectID + "/idle-endpoints/" + strconv.Itoa(d.Get("idle_for_seconds").(int)))
+	if err := d.Set("endpoints", idleEndpoints(resp.Endpoints, idleFor, time.Now())); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+// idleEndpoints returns the endpoints which are not active, and were active last time earlier than idleFor ago.
+func idleEndpoints(endpoints []neon.Endpoint, idleFor time.Duration, now time.Time) []map[string]interface{} {
+	o := make([]map[string]interface{}, 0)
+	for _, v := range endpoints {
+		if v.CurrentState == neon.EndpointStateActive {
+			continue
+		}
+
+		since := v.CreatedAt
+		lastActive := ""
+		if v.LastActive != nil {
+			since = *v.LastActive
+			lastActive = v.LastActive.UTC().Format(time.RFC3339)
+		}
+
+		idle := now.Sub(since)
+		if idle < idleFor {
+			continue
+		}
+
+		o = append(o, map[string]interface{}{
+			"id":            v.ID,
+			"branch_id":     v.BranchID,
+			"host":          v.Host,
+			"current_state": string(v.CurrentState),
+			"last_active":   lastActive,
+			"idle_seconds":  int(idle.Seconds()),
+		})
+	}
+	return o
+}
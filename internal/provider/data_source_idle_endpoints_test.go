@@ -0,0 +1,74 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_idleEndpoints(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	hoursAgo := func(h int) *time.Time {
+		v := now.Add(-time.Duration(h) * time.Hour)
+		return &v
+	}
+	endpoints := []neon.Endpoint{
+		{
+			ID:           "ep-active",
+			CurrentState: neon.EndpointStateActive,
+			LastActive:   hoursAgo(48),
+		},
+		{
+			ID:           "ep-recently-active",
+			CurrentState: neon.EndpointStateIdle,
+			LastActive:   hoursAgo(1),
+		},
+		{
+			ID:           "ep-abandoned",
+			BranchID:     "br-foo",
+			Host:         "ep-abandoned.us-east-2.aws.neon.tech",
+			CurrentState: neon.EndpointStateIdle,
+			LastActive:   hoursAgo(48),
+		},
+		{
+			ID:           "ep-never-active",
+			BranchID:     "br-bar",
+			CurrentState: neon.EndpointStateIdle,
+			CreatedAt:    now.Add(-25 * time.Hour),
+		},
+	}
+
+	// WHEN
+	got := idleEndpoints(endpoints, 24*time.Hour, now)
+
+	// THEN
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":            "ep-abandoned",
+			"branch_id":     "br-foo",
+			"host":          "ep-abandoned.us-east-2.aws.neon.tech",
+			"current_state": "idle",
+			"last_active":   "2024-05-13T10:00:00Z",
+			"idle_seconds":  172800,
+		},
+		{
+			"id":            "ep-never-active",
+			"branch_id":     "br-bar",
+			"host":          "",
+			"current_state": "idle",
+			"last_active":   "",
+			"idle_seconds":  90000,
+		},
+	}, got)
+}
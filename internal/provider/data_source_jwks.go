@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceJWKS() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the JWKS URLs configured for the Project, e.g. to verify that only approved
+identity providers are registered.
+
+See details: https://neon.tech/docs/guides/neon-authorize`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceJWKSRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"jwks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "JWKS ID.",
+						},
+						"jwks_url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "URL which lists the JWKS.",
+						},
+						"provider_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the authentication provider, e.g. Clerk, Stytch, Auth0.",
+						},
+						"branch_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the branch on which the JWKS is accepted. Empty if accepted on any branch.",
+						},
+						"jwt_audience": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Required JWT audience.",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timestamp of the JWKS creation, RFC3339.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceJWKSRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read JWKS")
+
+	projectID := d.Get("project_id").(string)
+
+	d.SetId(projectID + "/jwks")
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetProjectJWKS(projectID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("jwks", jwksToList(resp.Jwks)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+func jwksToList(v []neon.JWKS) []map[string]interface{} {
+	o := make([]map[string]interface{}, len(v))
+	for i, el := range v {
+		var branchID, audience string
+		if el.BranchID != nil {
+			branchID = *el.BranchID
+		}
+		if el.JwtAudience != nil {
+			audience = *el.JwtAudience
+		}
+		o[i] = map[string]interface{}{
+			"id":            el.ID,
+			"jwks_url":      el.JwksURL,
+			"provider_name": el.ProviderName,
+			"branch_id":     branchID,
+			"jwt_audience":  audience,
+			"created_at":    el.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return o
+}
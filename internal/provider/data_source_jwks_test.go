@@ -0,0 +1,56 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_jwksToList(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	got := jwksToList([]neon.JWKS{
+		{
+			ID:           "foo",
+			JwksURL:      "https://example.com/.well-known/jwks.json",
+			ProviderName: "Clerk",
+			BranchID:     pointer("br-foo"),
+			JwtAudience:  pointer("bar"),
+			CreatedAt:    createdAt,
+		},
+		{
+			ID:           "baz",
+			JwksURL:      "https://example.org/.well-known/jwks.json",
+			ProviderName: "Auth0",
+			CreatedAt:    createdAt,
+		},
+	})
+
+	want := []map[string]interface{}{
+		{
+			"id":            "foo",
+			"jwks_url":      "https://example.com/.well-known/jwks.json",
+			"provider_name": "Clerk",
+			"branch_id":     "br-foo",
+			"jwt_audience":  "bar",
+			"created_at":    "2024-05-01T10:00:00Z",
+		},
+		{
+			"id":            "baz",
+			"jwks_url":      "https://example.org/.well-known/jwks.json",
+			"provider_name": "Auth0",
+			"branch_id":     "",
+			"jwt_audience":  "",
+			"created_at":    "2024-05-01T10:00:00Z",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected result. want: %v, got: %v", want, got)
+	}
+}
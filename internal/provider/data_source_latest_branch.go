@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceLatestBranch() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Fetch the most recently created Project Branch which name matches the pattern.",
+		SchemaVersion: 1,
+		ReadContext:   dataSourceLatestBranchRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"name_regex", "name_glob"},
+				Description:  "Regular expression to match the branch name, e.g. `^preview/.+`.",
+				ValidateFunc: func(i interface{}, s string) (warns []string, errs []error) {
+					if _, err := regexp.Compile(i.(string)); err != nil {
+						errs = append(errs, errors.New(s+" is not valid regular expression: "+err.Error()))
+					}
+					return
+				},
+			},
+			"name_glob": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"name_regex", "name_glob"},
+				Description:  "Glob pattern to match the branch name, e.g. `preview/*`.",
+				ValidateFunc: func(i interface{}, s string) (warns []string, errs []error) {
+					if _, err := path.Match(i.(string), ""); err != nil {
+						errs = append(errs, errors.New(s+" is not valid glob pattern: "+err.Error()))
+					}
+					return
+				},
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Branch ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Branch name.",
+			},
+			"parent_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the parent branch.",
+			},
+			"logical_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Branch logical size in MB.",
+			},
+			"protected": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Protected branch flag.",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Default branch flag.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Branch creation timestamp in the RFC3339 format.",
+			},
+		},
+	}
+}
+
+func dataSourceLatestBranchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read latest Branch")
+
+	projectID := d.Get("project_id").(string)
+
+	var (
+		match   func(string) bool
+		pattern string
+	)
+	if v, ok := d.GetOk("name_regex"); ok {
+		pattern = v.(string)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		match = re.MatchString
+	} else {
+		pattern = d.Get("name_glob").(string)
+		match = func(s string) bool {
+			ok, _ := path.Match(pattern, s)
+			return ok
+		}
+	}
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	branch, ok := findLatestBranch(resp.Branches, match)
+	if !ok {
+		return diag.Errorf("no branch matching %s found in the project %s", pattern, projectID)
+	}
+
+	d.SetId(branch.ID)
+
+	parentID := ""
+	if branch.ParentID != nil {
+		parentID = *branch.ParentID
+	}
+	logicalSize := int64(0)
+	if branch.LogicalSize != nil {
+		logicalSize = *branch.LogicalSize
+	}
+
+	for k, v := range map[string]interface{}{
+		"name":         branch.Name,
+		"parent_id":    parentID,
+		"logical_size": logicalSize,
+		"protected":    branch.Protected,
+		"default":      branch.Default,
+		"created_at":   branch.CreatedAt.Format(time.RFC3339),
+	} {
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diag.FromErr(nil)
+}
+
+// findLatestBranch returns the most recently created branch which name matches.
+func findLatestBranch(branches []neon.Branch, match func(string) bool) (neon.Branch, bool) {
+	var (
+		o     neon.Branch
+		found bool
+	)
+	for _, br := range branches {
+		if match(br.Name) && (!found || br.CreatedAt.After(o.CreatedAt)) {
+			o = br
+			found = true
+		}
+	}
+	return o, found
+}
@@ -0,0 +1,79 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"path"
+	"regexp"
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_findLatestBranch(t *testing.T) {
+	now := time.Now()
+	branches := []neon.Branch{
+		{ID: "br-main", Name: "main", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "br-preview-1", Name: "preview/1", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "br-preview-3", Name: "preview/3", CreatedAt: now},
+		{ID: "br-preview-2", Name: "preview/2", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+
+	tests := map[string]struct {
+		match     func(string) bool
+		wantID    string
+		wantFound bool
+	}{
+		"shall find the latest branch matching the regex": {
+			match:     regexp.MustCompile(`^preview/\d+$`).MatchString,
+			wantID:    "br-preview-3",
+			wantFound: true,
+		},
+		"shall find the latest branch matching the glob": {
+			match: func(s string) bool {
+				ok, _ := path.Match("preview/[12]", s)
+				return ok
+			},
+			wantID:    "br-preview-2",
+			wantFound: true,
+		},
+		"shall find no branch": {
+			match:     regexp.MustCompile(`^release/`).MatchString,
+			wantFound: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, found := findLatestBranch(branches, tt.match)
+			if found != tt.wantFound {
+				t.Fatalf("unexpected found flag. want: %v, got: %v", tt.wantFound, found)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("unexpected branch. want: %s, got: %s", tt.wantID, got.ID)
+			}
+		})
+	}
+}
+
+func FuzzLatestBranchNameFilters(f *testing.F) {
+	for _, seed := range []string{"^preview/.+", "preview/*", "[", "(", "\\", "[a-", "*?", ""} {
+		f.Add(seed)
+	}
+
+	s := dataSourceLatestBranch().Schema
+	f.Fuzz(func(t *testing.T, pattern string) {
+		if _, errs := s["name_regex"].ValidateFunc(pattern, "name_regex"); len(errs) == 0 {
+			if _, err := regexp.Compile(pattern); err != nil {
+				t.Errorf("malformed regular expression %q is accepted", pattern)
+			}
+		}
+		if _, errs := s["name_glob"].ValidateFunc(pattern, "name_glob"); len(errs) == 0 {
+			if _, err := path.Match(pattern, ""); err != nil {
+				t.Errorf("malformed glob pattern %q is accepted", pattern)
+			}
+		}
+	})
+}
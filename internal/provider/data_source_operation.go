@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceOperation() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the Project's Operation by ID, e.g. to poll the status of the operation
+started outside of Terraform, or to check why the operation failed.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceOperationRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"operation_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Operation ID.",
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operation action, e.g. `create_branch`, or `start_compute`.",
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Operation status, i.e. one of `scheduling`, `running`, `finished`, `failed`, " +
+					"`error`, `cancelling`, `cancelled`, `skipped`.",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Error message of the failed operation.",
+			},
+			"failures_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the operation's failed attempts.",
+			},
+			"retry_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the operation's next attempt in the RFC3339 format.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the branch the operation applies to.",
+			},
+			"endpoint_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the endpoint the operation applies to.",
+			},
+			"total_duration_ms": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Operation duration in milliseconds.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operation creation timestamp in the RFC3339 format.",
+			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Operation last update timestamp in the RFC3339 format.",
+			},
+		},
+	}
+}
+
+func dataSourceOperationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Operation")
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetProjectOperation(
+		d.Get("project_id").(string), d.Get("operation_id").(string),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	o := resp.Operation
+	d.SetId(o.ID)
+
+	return diag.FromErr(updateStateOperation(d, o))
+}
+
+func updateStateOperation(d *schema.ResourceData, o neon.Operation) error {
+	var errMsg, branchID, endpointID, retryAt string
+	if o.Error != nil {
+		errMsg = *o.Error
+	}
+	if o.BranchID != nil {
+		branchID = *o.BranchID
+	}
+	if o.EndpointID != nil {
+		endpointID = *o.EndpointID
+	}
+	if o.RetryAt != nil {
+		retryAt = o.RetryAt.Format(time.RFC3339)
+	}
+
+	for k, v := range map[string]interface{}{
+		"action":            string(o.Action),
+		"status":            string(o.Status),
+		"error":             errMsg,
+		"failures_count":    int(o.FailuresCount),
+		"retry_at":          retryAt,
+		"branch_id":         branchID,
+		"endpoint_id":       endpointID,
+		"total_duration_ms": int(o.TotalDurationMs),
+		"created_at":        o.CreatedAt.Format(time.RFC3339),
+		"updated_at":        o.UpdatedAt.Format(time.RFC3339),
+	} {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dataSourceOperationRead(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+	if !assert.NotEmpty(t, project.Operations) {
+		return
+	}
+	op := project.Operations[0]
+
+	meta := &providerClient{Client: client}
+	ctx := context.TODO()
+
+	t.Run("shall read the operation", func(t *testing.T) {
+		// GIVEN
+		d := dataSourceOperation().TestResourceData()
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("operation_id", op.ID)
+
+		// WHEN
+		diags := dataSourceOperationRead(ctx, d, meta)
+
+		// THEN
+		assert.False(t, diags.HasError(), diags)
+		assert.Equal(t, op.ID, d.Id())
+		assert.Equal(t, string(op.Action), d.Get("action"))
+		assert.Equal(t, string(neon.OperationStatusFinished), d.Get("status"))
+		assert.Empty(t, d.Get("error"))
+		if op.BranchID != nil {
+			assert.Equal(t, *op.BranchID, d.Get("branch_id"))
+		}
+		assert.NotEmpty(t, d.Get("created_at"))
+	})
+
+	t.Run("shall fail if the operation is not found", func(t *testing.T) {
+		// GIVEN
+		d := dataSourceOperation().TestResourceData()
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("operation_id", "unknown")
+
+		// WHEN
+		diags := dataSourceOperationRead(ctx, d, meta)
+
+		// THEN
+		assert.True(t, diags.HasError())
+		assert.Empty(t, d.Id())
+	})
+}
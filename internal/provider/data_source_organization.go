@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceOrganization() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch Organization, e.g. to choose the compute size based on the organization's plan.
+
+See details: https://neon.tech/docs/manage/organizations`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceOrganizationRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Organization ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Organization name.",
+			},
+			"handle": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Organization handle.",
+			},
+			"plan": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Billing plan of the organization, e.g. scale.",
+			},
+			"managed_by": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `Organizations created via the Console, or the API are managed by console.
+Organizations created by other methods cannot be deleted via the Console, or the API.`,
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the organization creation, RFC3339.",
+			},
+		},
+	}
+}
+
+func dataSourceOrganizationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Organization")
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetOrganization(d.Get("id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(resp.ID)
+	if err := updateStateOrganization(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+func updateStateOrganization(d *schema.ResourceData, v neon.Organization) error {
+	if err := d.Set("name", v.Name); err != nil {
+		return err
+	}
+	if err := d.Set("handle", v.Handle); err != nil {
+		return err
+	}
+	if err := d.Set("plan", v.Plan); err != nil {
+		return err
+	}
+	if err := d.Set("managed_by", v.ManagedBy); err != nil {
+		return err
+	}
+	return d.Set("created_at", v.CreatedAt.Format(time.RFC3339))
+}
@@ -0,0 +1,34 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateStateOrganization(t *testing.T) {
+	d := dataSourceOrganization().TestResourceData()
+
+	err := updateStateOrganization(d, neon.Organization{
+		ID:        "org-foo-123",
+		Name:      "foo",
+		Handle:    "foo-handle",
+		Plan:      "scale",
+		ManagedBy: "console",
+		CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Equal(t, "foo", d.Get("name"))
+	assert.Equal(t, "foo-handle", d.Get("handle"))
+	assert.Equal(t, "scale", d.Get("plan"))
+	assert.Equal(t, "console", d.Get("managed_by"))
+	assert.Equal(t, "2024-05-01T10:00:00Z", d.Get("created_at"))
+}
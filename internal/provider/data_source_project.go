@@ -14,7 +14,7 @@ func dataSourceProject() *schema.Resource {
 		Description:   `Fetch Project.`,
 		SchemaVersion: 1,
 		ReadContext:   dataSourceProjectRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withConsumptionExport(withConsumptionMetrics(map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
 				Description: "Project ID.",
@@ -58,13 +58,19 @@ func dataSourceProject() *schema.Resource {
 				Description: "Default connection uri. **Note** that it contains access credentials.",
 			},
 		},
+			projectConsumptionMetricsColumns...,
+		), "project's", `"project" to render the project's metrics,
+or "branch" to render the metrics per branch`, "project", "branch"),
 	}
 }
 
 func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tflog.Trace(ctx, "get Project")
 
-	client := meta.(*neon.Client)
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	resp, err := client.GetProject(d.Get("id").(string))
 	if err != nil {
@@ -78,14 +84,28 @@ func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
+	if err := setConsumptionMetrics(d, projectConsumptionMetrics(project)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	branches, err := client.ListProjectBranches(project.ID, nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if v, ok := d.GetOk("export_format"); ok {
+		export, err := exportProjectConsumption(project, branches.Branches, v.(string), d.Get("export_group_by").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("export", export); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	var defaultBranch neon.Branch
 	for _, v := range branches.Branches {
-		if v.Primary != nil && *v.Primary {
+		if v.Default {
 			defaultBranch = v
 			break
 		}
@@ -133,3 +153,19 @@ func dataSourceProjectRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	return diag.FromErr(nil)
 }
+
+// projectConsumptionMetricsColumns defines the order of the exported project's consumption metrics.
+var projectConsumptionMetricsColumns = append(
+	append([]string{}, consumptionMetricsColumns...), "data_storage_bytes_hour", "synthetic_storage_size",
+)
+
+// exportProjectConsumption renders the project's consumption metrics in the format json, or csv.
+// The project's metrics are rendered, or the metrics per branch given the grouping groupBy.
+func exportProjectConsumption(project neon.Project, branches []neon.Branch, format, groupBy string) (string, error) {
+	if groupBy == "branch" {
+		return exportBranchesConsumption(project.ID, branches, format, groupBy)
+	}
+	return exportConsumption(format, []string{"project_id"}, projectConsumptionMetricsColumns, [][]string{
+		consumptionRow([]string{project.ID}, projectConsumptionMetrics(project), projectConsumptionMetricsColumns),
+	})
+}
@@ -0,0 +1,69 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_exportProjectConsumption(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	project := neon.Project{
+		ID: "shiny-wind-028834", ActiveTimeSeconds: 150, ComputeTimeSeconds: 10, WrittenDataBytes: 1000,
+		DataTransferBytes: 20, DataStorageBytesHour: 5, SyntheticStorageSize: pointer(int64(3)),
+	}
+	branches := []neon.Branch{
+		{ID: "br-foo", Name: "main", ActiveTimeSeconds: 100, ComputeTimeSeconds: 10, WrittenDataBytes: 1000},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		groupBy string
+		want    string
+	}{
+		{
+			name:    "csv per project",
+			format:  "csv",
+			groupBy: "project",
+			want: `project_id,active_time_seconds,compute_time_seconds,written_data_bytes,data_transfer_bytes,` +
+				`data_storage_bytes_hour,synthetic_storage_size
+shiny-wind-028834,150,10,1000,20,5,3
+`,
+		},
+		{
+			name:    "json per project",
+			format:  "json",
+			groupBy: "project",
+			want: `[{"active_time_seconds":150,"compute_time_seconds":10,"data_storage_bytes_hour":5,` +
+				`"data_transfer_bytes":20,"project_id":"shiny-wind-028834","synthetic_storage_size":3,` +
+				`"written_data_bytes":1000}]`,
+		},
+		{
+			name:    "csv per branch",
+			format:  "csv",
+			groupBy: "branch",
+			want: `branch_id,name,active_time_seconds,compute_time_seconds,written_data_bytes,data_transfer_bytes
+br-foo,main,100,10,1000,0
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := exportProjectConsumption(project, branches, tt.format, tt.groupBy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected export:\nwant: %s\ngot:  %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func dataSourceRegions() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the regions supported by Neon, including the regions hosted on AWS and Azure.
+
+See details: https://neon.tech/docs/introduction/regions`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceRegionsRead,
+		Schema: map[string]*schema.Schema{
+			"cloud_provider": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Cloud provider to filter the regions by, e.g. aws, or azure. All regions are listed if not set.",
+			},
+			"default_region_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the region used by default for the new projects.",
+			},
+			"regions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Region ID, e.g. azure-eastus2.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Region name, e.g. Azure East US 2 (Virginia).",
+						},
+						"cloud_provider": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Cloud provider hosting the region, e.g. azure.",
+						},
+						"default": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the region is used by default for the new projects.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRegionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Regions")
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	regions, err := activeRegions(client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	cloudProvider := d.Get("cloud_provider").(string)
+	d.SetId("regions/" + cloudProvider)
+	if err := updateStateRegions(d, regions, cloudProvider); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+func updateStateRegions(d *schema.ResourceData, regions []neon.RegionResponse, cloudProvider string) error {
+	o := make([]map[string]interface{}, 0, len(regions))
+	var defaultRegionID string
+	for _, v := range regions {
+		if v.Default {
+			defaultRegionID = v.RegionID
+		}
+
+		provider := regionCloudProvider(v.RegionID)
+		if cloudProvider != "" && provider != cloudProvider {
+			continue
+		}
+
+		o = append(o, map[string]interface{}{
+			"id":             v.RegionID,
+			"name":           v.Name,
+			"cloud_provider": provider,
+			"default":        v.Default,
+		})
+	}
+
+	if err := d.Set("default_region_id", defaultRegionID); err != nil {
+		return err
+	}
+	return d.Set("regions", o)
+}
@@ -0,0 +1,48 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_updateStateRegions(t *testing.T) {
+	regions := []neon.RegionResponse{
+		{RegionID: "aws-us-east-2", Name: "AWS US East 2 (Ohio)", Default: true},
+		{RegionID: "azure-eastus2", Name: "Azure East US 2 (Virginia)"},
+	}
+
+	t.Run("shall list all regions", func(t *testing.T) {
+		d := dataSourceRegions().TestResourceData()
+
+		if err := updateStateRegions(d, regions, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assert.Equal(t, "aws-us-east-2", d.Get("default_region_id"))
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"id": "aws-us-east-2", "name": "AWS US East 2 (Ohio)", "cloud_provider": "aws", "default": true,
+			},
+			map[string]interface{}{
+				"id": "azure-eastus2", "name": "Azure East US 2 (Virginia)", "cloud_provider": "azure", "default": false,
+			},
+		}, d.Get("regions"))
+	})
+
+	t.Run("shall filter the regions by the cloud provider", func(t *testing.T) {
+		d := dataSourceRegions().TestResourceData()
+
+		if err := updateStateRegions(d, regions, "azure"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assert.Equal(t, "aws-us-east-2", d.Get("default_region_id"))
+		assert.Len(t, d.Get("regions"), 1)
+		assert.Equal(t, "azure-eastus2", d.Get("regions.0.id"))
+	})
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceRole() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch Role with the hosts to connect to the endpoint,
+e.g. to configure the application which uses the existing role.`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceRoleRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Branch ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Role name.",
+			},
+			"endpoint_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				Description: `Endpoint ID. The branch's default read-write endpoint is used if not set.
+**Note** that the endpoint must belong to the branch.`,
+			},
+			"protected": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the role is protected.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host to connect to the endpoint directly.",
+			},
+			"pooler_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host to connect to the endpoint using the connection pooler.",
+			},
+		},
+	}
+}
+
+func dataSourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read Role")
+
+	projectID := d.Get("project_id").(string)
+	branchID := d.Get("branch_id").(string)
+	roleName := d.Get("name").(string)
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	role, err := client.GetProjectBranchRole(projectID, branchID, roleName)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoints, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpoint, err := findBranchEndpoint(endpoints.Endpoints, branchID, d.Get("endpoint_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(projectID + "/" + branchID + "/" + roleName)
+	if err := d.Set("endpoint_id", endpoint.ID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	protected := role.Role.Protected != nil && *role.Role.Protected
+	if err := d.Set("protected", protected); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("host", endpoint.Host); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("pooler_host", poolerHost(endpoint.Host)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTimeTravelConnectionURI() *schema.Resource {
+	return &schema.Resource{
+		Description: `Fetch the connection URI to query the database state in the past.
+See details: https://neon.tech/docs/guides/time-travel-assist`,
+		SchemaVersion: 1,
+		ReadContext:   dataSourceTimeTravelConnectionURIRead,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Branch ID.",
+			},
+			"database_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Database name.",
+			},
+			"role_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Role name.",
+			},
+			"lsn": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"lsn", "timestamp"},
+				ValidateFunc: validateLSN,
+				Description: `Log Sequence Number (LSN) to query the database state at.
+See details: https://neon.tech/docs/reference/glossary/#lsn`,
+			},
+			"timestamp": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"lsn", "timestamp"},
+				ValidateFunc: validateRFC3339,
+				Description:  "Timestamp in the RFC3339 format to query the database state at, e.g. `2024-10-01T12:00:00Z`.",
+			},
+			"uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Connection URI. **Note** that it contains access credentials.",
+			},
+		},
+	}
+}
+
+func dataSourceTimeTravelConnectionURIRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "read time travel connection URI")
+
+	projectID := d.Get("project_id").(string)
+	branchID := d.Get("branch_id").(string)
+	dbName := d.Get("database_name").(string)
+	roleName := d.Get("role_name").(string)
+	lsn := d.Get("lsn").(string)
+	timestamp := d.Get("timestamp").(string)
+
+	client, err := providerClientFromMeta(meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resp, err := client.GetConnectionURI(projectID, &branchID, nil, dbName, roleName, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	uri, err := timeTravelConnectionURI(resp.URI, lsn, timestamp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(projectID + "/" + branchID + "/" + dbName + "/" + roleName + "@" + lsn + timestamp)
+	if err := d.Set("uri", uri); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(nil)
+}
+
+// timeTravelConnectionURI pins the connection URI to the LSN, or to the timestamp.
+func timeTravelConnectionURI(uri, lsn, timestamp string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", errors.New("cannot parse connection URI")
+	}
+
+	var option string
+	switch {
+	case lsn != "":
+		option = "neon_lsn:" + lsn
+	case timestamp != "":
+		option = "neon_timestamp:" + timestamp
+	default:
+		return "", errors.New("either lsn, or timestamp must be set")
+	}
+
+	q := u.Query()
+	q.Set("options", option)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+var reLSN = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}$`)
+
+func validateLSN(v interface{}, s string) (warns []string, errs []error) {
+	if vv, ok := v.(string); !ok || !reLSN.MatchString(vv) {
+		errs = append(errs, errors.New(s+" must be valid LSN, e.g. 0/1A2B3C4"))
+	}
+	return
+}
+
+func validateRFC3339(v interface{}, s string) (warns []string, errs []error) {
+	vv, ok := v.(string)
+	if !ok {
+		errs = append(errs, errors.New(s+" must be string"))
+		return
+	}
+	if _, err := time.Parse(time.RFC3339, vv); err != nil {
+		errs = append(errs, errors.New(s+" must be timestamp in the RFC3339 format, e.g. 2024-10-01T12:00:00Z"))
+	}
+	return
+}
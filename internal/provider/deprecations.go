@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+// apiDeprecations lists the attributes per resource, or data source, which are backed by the API fields
+// deprecated by the Neon API, and the replacements. The attributes of the nested blocks are defined
+// using the dot notation, e.g. "branches.primary". See the deprecation notes of the SDK's models.
+var apiDeprecations = map[string]map[string]string{
+	"neon_project": {
+		"allowed_ips_primary_branch_only": "The API deprecated the field `primary_branch_only` of the allow-list. " +
+			"Use the attribute `allowed_ips_protected_branches_only` instead.",
+	},
+	"neon_endpoint": {
+		"proxy_host": "The API deprecated the field `proxy_host`. Use the attribute `host` instead.",
+	},
+	"neon_branch_endpoints": {
+		"endpoints.proxy_host": "The API deprecated the field `proxy_host`. Use the attribute `host` instead.",
+	},
+	"neon_branches": {
+		"branches.primary": "The API deprecated the field `primary` of the branch. " +
+			"Use the attribute `id` of the data source `neon_project`'s default branch instead.",
+	},
+}
+
+// withAPIDeprecations marks the attributes backed by the deprecated API fields. Terraform warns about
+// the configurable attributes if they are set, the computed attributes are marked in the description.
+func withAPIDeprecations(name string, r *schema.Resource) {
+	for path, msg := range apiDeprecations[name] {
+		s := schemaAttribute(r, path)
+		if s == nil {
+			continue
+		}
+		if s.Optional || s.Required {
+			s.Deprecated = msg
+			continue
+		}
+		s.Description = strings.TrimSpace("**Deprecated**: " + msg + " " + s.Description)
+	}
+}
+
+// schemaAttribute returns the attribute's schema given its path in the dot notation, or nil if not found.
+func schemaAttribute(r *schema.Resource, path string) *schema.Schema {
+	keys := strings.Split(path, ".")
+	for i, k := range keys {
+		s, ok := r.Schema[k]
+		if !ok {
+			return nil
+		}
+		if i == len(keys)-1 {
+			return s
+		}
+		if r, ok = s.Elem.(*schema.Resource); !ok {
+			return nil
+		}
+	}
+	return nil
+}
+
+// apiDeprecatedFields lists the fields of the API responses deprecated by the Neon API, see apiDeprecations.
+var apiDeprecatedFields = map[string]bool{
+	"primary":             true,
+	"proxy_host":          true,
+	"primary_branch_only": true,
+}
+
+// deprecationDetector detects the deprecations in the API responses: the deprecated fields, and the endpoints
+// marked by the API with the headers Deprecation and Sunset, see RFC 9745 and RFC 8594.
+// Every deprecation is reported once per provider's process.
+type deprecationDetector struct {
+	neon.HTTPClient
+
+	mu        sync.Mutex
+	seen      map[string]bool
+	fields    []string
+	endpoints []string
+}
+
+func (c *deprecationDetector) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if resp.Header.Get("Deprecation") != "" {
+		msg := req.Method + " " + req.URL.Path + " is deprecated by the Neon API"
+		if v := resp.Header.Get("Sunset"); v != "" {
+			msg += ", it will be removed after " + v
+		}
+		c.add(&c.endpoints, msg)
+	}
+
+	if resp.Body == nil {
+		return resp, nil
+	}
+	b, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if json.Unmarshal(b, &v) == nil {
+		walkJSONKeys(v, func(k string) {
+			if apiDeprecatedFields[k] {
+				c.add(&c.fields, k)
+			}
+		})
+	}
+	return resp, nil
+}
+
+func (c *deprecationDetector) add(pending *[]string, v string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seen == nil {
+		c.seen = map[string]bool{}
+	}
+	if c.seen[v] {
+		return
+	}
+	c.seen[v] = true
+	*pending = append(*pending, v)
+}
+
+// pending returns the deprecations detected since the previous call.
+func (c *deprecationDetector) pending() (fields, endpoints []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fields, endpoints = c.fields, c.endpoints
+	c.fields, c.endpoints = nil, nil
+	sort.Strings(fields)
+	return fields, endpoints
+}
+
+// walkJSONKeys calls fn for every key of the decoded JSON document's objects.
+func walkJSONKeys(v interface{}, fn func(string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, el := range v {
+			fn(k)
+			walkJSONKeys(el, fn)
+		}
+	case []interface{}:
+		for _, el := range v {
+			walkJSONKeys(el, fn)
+		}
+	}
+}
+
+// apiDeprecationDiagnostics returns the warnings about the deprecated API endpoints detected since the previous
+// call, the deprecated fields are logged only because the provider reads them to set the deprecated attributes.
+func apiDeprecationDiagnostics(ctx context.Context, meta interface{}) diag.Diagnostics {
+	c, ok := meta.(*providerClient)
+	if !ok || c == nil || c.deprecations == nil {
+		return nil
+	}
+
+	fields, endpoints := c.deprecations.pending()
+	for _, v := range fields {
+		tflog.Warn(ctx, "the Neon API response contains the deprecated field", map[string]interface{}{"field": v})
+	}
+
+	var o diag.Diagnostics
+	for _, v := range endpoints {
+		o = append(o, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Deprecated Neon API endpoint",
+			Detail:   v + ". Upgrade the provider, or report the issue if the latest version uses the endpoint.",
+		})
+	}
+	return o
+}
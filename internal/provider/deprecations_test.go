@@ -0,0 +1,113 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_apiDeprecations(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	provider := New("test")
+
+	for name, attributes := range apiDeprecations {
+		r, ok := provider.ResourcesMap[name]
+		if !ok {
+			r, ok = provider.DataSourcesMap[name]
+		}
+		if !assert.True(t, ok, "unknown resource %s", name) {
+			continue
+		}
+
+		for path, msg := range attributes {
+			// WHEN
+			s := schemaAttribute(r, path)
+
+			// THEN
+			if !assert.NotNil(t, s, "unknown attribute %s of %s", path, name) {
+				continue
+			}
+			if s.Optional || s.Required {
+				assert.Equal(t, msg, s.Deprecated, "%s of %s", path, name)
+			} else {
+				assert.True(t, strings.HasPrefix(s.Description, "**Deprecated**: "+msg), "%s of %s", path, name)
+			}
+		}
+	}
+}
+
+func Test_deprecationDetector(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	const body = `{"branches":[{"id":"br-foo","primary":true,"default":true}],"endpoint":{"proxy_host":"foo"}}`
+	detector := &deprecationDetector{
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			resp := newHTTPResponse(http.StatusOK, body)
+			if strings.HasSuffix(r.URL.Path, "/deprecated") {
+				resp.Header = http.Header{"Deprecation": {"@1735689600"}, "Sunset": {"Wed, 31 Dec 2025 00:00:00 GMT"}}
+			}
+			return resp, nil
+		}),
+	}
+	meta := &providerClient{deprecations: detector}
+
+	for _, path := range []string{"/projects", "/deprecated", "/deprecated"} {
+		// WHEN
+		req, _ := http.NewRequest(http.MethodGet, "https://console.neon.tech/api/v2"+path, nil)
+		resp, err := detector.Do(req)
+
+		// THEN
+		assert.NoError(t, err)
+		b, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, body, string(b), "the response's body must be kept")
+	}
+
+	fields, endpoints := detector.pending()
+	assert.Equal(t, []string{"primary", "proxy_host"}, fields)
+	assert.Equal(t,
+		[]string{"GET /api/v2/deprecated is deprecated by the Neon API, it will be removed after Wed, 31 Dec 2025 00:00:00 GMT"},
+		endpoints, "every deprecation must be reported once")
+
+	t.Run("shall warn about the deprecated endpoints upon the resource's operation", func(t *testing.T) {
+		// GIVEN
+		detector.endpoints = []string{"GET /foo is deprecated by the Neon API"}
+
+		// WHEN
+		diags := (&delay{maxCnt: 1}).Retry(
+			func(context.Context, *schema.ResourceData, interface{}) error { return errors.New("foo") },
+			context.TODO(), resourceProject().TestResourceData(), meta,
+		)
+
+		// THEN
+		if assert.Len(t, diags, 2) {
+			assert.Equal(t, diag.Error, diags[0].Severity)
+			assert.Equal(t, diag.Warning, diags[1].Severity)
+			assert.Contains(t, diags[1].Detail, "GET /foo is deprecated by the Neon API")
+		}
+		assert.Empty(t, apiDeprecationDiagnostics(context.TODO(), meta), "the warning must be reported once")
+	})
+
+	t.Run("shall skip the clients other than the provider's", func(t *testing.T) {
+		assert.Nil(t, apiDeprecationDiagnostics(context.TODO(), &sdkClientStub{}))
+		assert.Nil(t, apiDeprecationDiagnostics(context.TODO(), &providerClient{Client: &neon.Client{}}))
+	})
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// externalChangesGroups defines the groups of the compute settings which can be tuned outside of terraform,
+// e.g. in the Neon console, given the attributes of every group.
+var externalChangesGroups = map[string][]string{
+	"autoscaling":     {"autoscaling_limit_min_cu", "autoscaling_limit_max_cu"},
+	"suspend_timeout": {"suspend_timeout_seconds"},
+	"pooler":          {"pooler_enabled", "pooler_mode"},
+}
+
+// schemaIgnoreExternalChanges defines the attribute to list the groups of settings which are ignored
+// upon plan once the resource is created.
+func schemaIgnoreExternalChanges(groups ...string) *schema.Schema {
+	var descriptions []string
+	for _, g := range groups {
+		attrs := make([]string, len(externalChangesGroups[g]))
+		for i, v := range externalChangesGroups[g] {
+			attrs[i] = "`" + v + "`"
+		}
+		descriptions = append(descriptions, "`"+g+"` ("+strings.Join(attrs, ", ")+")")
+	}
+	sort.Strings(descriptions)
+
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(groups, false),
+		},
+		Description: `Groups of the settings which are managed outside of terraform, e.g. in the Neon console.
+The settings are applied upon create, and their changes are ignored afterwards, unlike ` +
+			"`lifecycle.ignore_changes`" + ` which ignores the whole block.
+Supported groups: ` + strings.Join(descriptions, ", ") + ".",
+	}
+}
+
+// suppressExternalChange returns the function which suppresses the diff of the attribute of the group
+// if the group is listed in ignore_external_changes of the existing resource.
+func suppressExternalChange(group string) schema.SchemaDiffSuppressFunc {
+	return func(_, _, _ string, d *schema.ResourceData) bool {
+		if d.Id() == "" {
+			return false
+		}
+		v, ok := d.Get("ignore_external_changes").(*schema.Set)
+		return ok && v.Contains(group)
+	}
+}
@@ -0,0 +1,80 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_suppressExternalChange(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	state := &terraform.InstanceState{
+		ID: "ep-cool-darkness-123456",
+		Attributes: map[string]string{
+			"id":                       "ep-cool-darkness-123456",
+			"project_id":               "shiny-wind-028834",
+			"branch_id":                "br-aged-salad-637688",
+			"autoscaling_limit_min_cu": "0.5",
+			"autoscaling_limit_max_cu": "4",
+			"suspend_timeout_seconds":  "600",
+		},
+	}
+
+	diff := func(t *testing.T, s *terraform.InstanceState, ignore []interface{}) map[string]bool {
+		t.Helper()
+
+		r := resourceEndpoint()
+		// the diff is customized using the API
+		r.CustomizeDiff = nil
+
+		o, err := r.Diff(context.TODO(), s, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_id":               "shiny-wind-028834",
+			"branch_id":                "br-aged-salad-637688",
+			"autoscaling_limit_min_cu": 0.25,
+			"autoscaling_limit_max_cu": 2,
+			"suspend_timeout_seconds":  300,
+			"ignore_external_changes":  ignore,
+		}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		changed := map[string]bool{}
+		if o != nil {
+			for k := range o.Attributes {
+				changed[k] = true
+			}
+		}
+		return changed
+	}
+
+	t.Run("shall plan the changes of all settings", func(t *testing.T) {
+		got := diff(t, state, nil)
+		assert.True(t, got["autoscaling_limit_min_cu"])
+		assert.True(t, got["autoscaling_limit_max_cu"])
+		assert.True(t, got["suspend_timeout_seconds"])
+	})
+
+	t.Run("shall ignore the changes of the autoscaling limits", func(t *testing.T) {
+		got := diff(t, state, []interface{}{"autoscaling"})
+		assert.False(t, got["autoscaling_limit_min_cu"])
+		assert.False(t, got["autoscaling_limit_max_cu"])
+		assert.True(t, got["suspend_timeout_seconds"])
+	})
+
+	t.Run("shall apply all settings upon create", func(t *testing.T) {
+		got := diff(t, nil, []interface{}{"autoscaling", "suspend_timeout"})
+		assert.True(t, got["autoscaling_limit_min_cu"])
+		assert.True(t, got["autoscaling_limit_max_cu"])
+		assert.True(t, got["suspend_timeout_seconds"])
+	})
+}
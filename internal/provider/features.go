@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// previewFeatures defines the preview capabilities of the Neon API which are opted in using the provider's
+// block features, given the capability's name.
+var previewFeatures = map[string]string{
+	"snapshots": "the branch snapshots",
+	"data_api":  "the Data API",
+	"auth":      "Neon Auth",
+}
+
+// previewResources maps the resources and data sources which use the preview capabilities to the features.
+// They fail unless the feature is enabled. The resource graduates once it's removed from the map, hence
+// the configurations which enable the feature keep working.
+var previewResources = map[string]string{}
+
+// enabledFeatures defines the preview features enabled in the provider's configuration.
+type enabledFeatures map[string]bool
+
+func newEnabledFeatures(v []interface{}) enabledFeatures {
+	o := enabledFeatures{}
+	if len(v) == 0 || v[0] == nil {
+		return o
+	}
+	for k, v := range v[0].(map[string]interface{}) {
+		o[k] = v.(bool)
+	}
+	return o
+}
+
+func schemaFeatures() *schema.Schema {
+	o := map[string]*schema.Schema{}
+	for k, v := range previewFeatures {
+		description := "Set to true to enable the resources of " + v + "."
+		if !isFeatureUsed(k) {
+			description = "Reserved to enable the resources of " + v + " once they are released, " +
+				"no resource uses it yet."
+		}
+		o[k] = &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: description,
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Description: `Preview capabilities of the Neon API to opt in. The resources which use the capability fail
+unless it's enabled. **Note** that the preview resources may change in the minor releases, and that the block is
+reserved for the upcoming preview resources, i.e. the capabilities which no resource uses yet have no effect.`,
+		Elem: &schema.Resource{Schema: o},
+	}
+}
+
+// isFeatureUsed returns true if any resource, or data source uses the preview feature.
+func isFeatureUsed(feature string) bool {
+	for _, v := range previewResources {
+		if v == feature {
+			return true
+		}
+	}
+	return false
+}
+
+func previewFeatureError(name, feature string) error {
+	return fmt.Errorf(
+		"%s uses the preview of %s, set `%s = true` in the block features of the provider's configuration to enable it",
+		name, previewFeatures[feature], feature,
+	)
+}
+
+// withPreviewFeature wraps the callbacks of the resource which uses the preview capability to fail
+// unless the feature is enabled. The resource's plan, and the data source's read fail.
+func withPreviewFeature(name string, r *schema.Resource) *schema.Resource {
+	feature, ok := previewResources[name]
+	if !ok {
+		return r
+	}
+
+	enabled := func(meta interface{}) bool {
+		c, ok := meta.(*providerClient)
+		return ok && c.features[feature]
+	}
+
+	if fn := r.ReadContext; fn != nil {
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if !enabled(meta) {
+				return diag.FromErr(previewFeatureError(name, feature))
+			}
+			return fn(ctx, d, meta)
+		}
+	}
+
+	if r.CreateContext != nil {
+		fn := r.CustomizeDiff
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if !enabled(meta) {
+				return previewFeatureError(name, feature)
+			}
+			if fn != nil {
+				return fn(ctx, d, meta)
+			}
+			return nil
+		}
+	}
+
+	return r
+}
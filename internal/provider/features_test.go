@@ -0,0 +1,99 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_withPreviewFeature(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	previewResources["neon_snapshot"] = "snapshots"
+	t.Cleanup(func() { delete(previewResources, "neon_snapshot") })
+
+	var calls int
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			ReadContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+				calls++
+				return nil
+			},
+			CreateContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+				return nil
+			},
+		}
+	}
+
+	t.Run("shall fail unless the feature is enabled", func(t *testing.T) {
+		// GIVEN
+		calls = 0
+		r := withPreviewFeature("neon_snapshot", newResource())
+		meta := &providerClient{features: newEnabledFeatures(nil)}
+
+		// WHEN
+		diags := r.ReadContext(context.TODO(), r.TestResourceData(), meta)
+		err := r.CustomizeDiff(context.TODO(), nil, meta)
+
+		// THEN
+		if assert.True(t, diags.HasError()) {
+			assert.Equal(t,
+				"neon_snapshot uses the preview of the branch snapshots, "+
+					"set `snapshots = true` in the block features of the provider's configuration to enable it",
+				diags[0].Summary,
+			)
+		}
+		assert.Error(t, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("shall call the resource once the feature is enabled", func(t *testing.T) {
+		// GIVEN
+		calls = 0
+		r := withPreviewFeature("neon_snapshot", newResource())
+		meta := &providerClient{
+			features: newEnabledFeatures([]interface{}{
+				map[string]interface{}{"snapshots": true, "data_api": false, "auth": false},
+			}),
+		}
+
+		// WHEN
+		diags := r.ReadContext(context.TODO(), r.TestResourceData(), meta)
+		err := r.CustomizeDiff(context.TODO(), nil, meta)
+
+		// THEN
+		assert.False(t, diags.HasError())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("shall not change the generally available resource", func(t *testing.T) {
+		r := withPreviewFeature("neon_branch", newResource())
+		assert.Nil(t, r.CustomizeDiff)
+	})
+}
+
+func Test_schemaFeatures(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	previewResources["neon_snapshot"] = "snapshots"
+	t.Cleanup(func() { delete(previewResources, "neon_snapshot") })
+
+	// WHEN
+	got := schemaFeatures().Elem.(*schema.Resource).Schema
+
+	// THEN
+	assert.Equal(t, "Set to true to enable the resources of the branch snapshots.", got["snapshots"].Description)
+	assert.Contains(t, got["auth"].Description, "Reserved", "the feature used by no resource shall be marked reserved")
+}
@@ -1,11 +1,22 @@
 package provider
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	neon "github.com/kislerdm/neon-sdk-go"
 )
 
@@ -17,6 +28,82 @@ func pgSettingsToMap(v neon.PgSettingsData) map[string]interface{} {
 	return o
 }
 
+// suppressPgSettingsDiff suppresses the diff between semantically equal Postgres settings values,
+// e.g. "4MB" and "4096kB", because the API returns the values normalized.
+func suppressPgSettingsDiff(k, old, new string, _ *schema.ResourceData) bool {
+	if strings.HasSuffix(k, ".%") {
+		return false
+	}
+	return pgSettingValuesEqual(old, new)
+}
+
+var rePgSettingValueWithUnit = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*([a-zA-Z]+)\s*$`)
+
+// pgSettingUnits defines the Postgres units multiples within the group of memory and time units.
+// See details: https://www.postgresql.org/docs/current/config-setting.html#CONFIG-SETTING-NAMES-VALUES
+var pgSettingUnits = map[string]struct {
+	group      string
+	multiplier float64
+}{
+	"B":   {"memory", 1},
+	"kB":  {"memory", 1 << 10},
+	"MB":  {"memory", 1 << 20},
+	"GB":  {"memory", 1 << 30},
+	"TB":  {"memory", 1 << 40},
+	"us":  {"time", 1},
+	"ms":  {"time", 1e3},
+	"s":   {"time", 1e6},
+	"min": {"time", 60e6},
+	"h":   {"time", 3600e6},
+	"d":   {"time", 86400e6},
+}
+
+func pgSettingValuesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	if va, ga, ok := parsePgSettingValueWithUnit(a); ok {
+		if vb, gb, ok := parsePgSettingValueWithUnit(b); ok {
+			return ga == gb && va == vb
+		}
+	}
+
+	if ba, ok := parsePgSettingBool(a); ok {
+		if bb, ok := parsePgSettingBool(b); ok {
+			return ba == bb
+		}
+	}
+
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+func parsePgSettingValueWithUnit(s string) (float64, string, bool) {
+	m := rePgSettingValueWithUnit.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", false
+	}
+	unit, ok := pgSettingUnits[m[2]]
+	if !ok {
+		return 0, "", false
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return v * unit.multiplier, unit.group, true
+}
+
+func parsePgSettingBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "on", "true", "yes":
+		return true, true
+	case "off", "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
 func mapToPgSettings(v map[string]interface{}) *neon.PgSettingsData {
 	if len(v) == 0 {
 		return nil
@@ -43,6 +130,125 @@ var schemaRegionID = &schema.Schema{
 	Description: "Deployment region: https://neon.tech/docs/introduction/regions",
 }
 
+var schemaSkipRegionValidation = &schema.Schema{
+	Type:     schema.TypeBool,
+	Optional: true,
+	Default:  false,
+	Description: `Set to true to skip validation of ` + "`region_id`" + ` against the list of the supported regions,
+e.g. to deploy to the private, or early-access region which is not listed by the API.`,
+}
+
+// consumptionMetricsDescription defines the descriptions of the consumption metrics reported by the API.
+// Note that the values have some lag, and they are reset at the beginning of each billing period.
+var consumptionMetricsDescription = map[string]string{
+	"active_time_seconds": "Seconds. The wall-clock time the computes were active during the current billing period.",
+	"compute_time_seconds": `Seconds. The CPU time used by the computes during the current billing period,
+including the deleted computes.`,
+	"written_data_bytes": "Bytes. The amount of WAL written to the storage during the current billing period.",
+	"data_transfer_bytes": `Bytes. The egress traffic from the Neon cloud to the clients during the current
+billing period, including the deleted computes.`,
+	"data_storage_bytes_hour": "Bytes-hour. The storage consumed hourly during the current billing period.",
+	"synthetic_storage_size": `Bytes. The current storage size, it combines the logical data size and WAL size
+of all branches.`,
+}
+
+// withConsumptionMetrics adds the computed attributes of the consumption metrics to the schema.
+func withConsumptionMetrics(s map[string]*schema.Schema, names ...string) map[string]*schema.Schema {
+	for _, name := range names {
+		s[name] = &schema.Schema{
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: consumptionMetricsDescription[name],
+		}
+	}
+	return s
+}
+
+func setConsumptionMetrics(d *schema.ResourceData, v map[string]int64) error {
+	for k, v := range v {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withConsumptionExport adds the attributes to render the consumption metrics of subject, e.g. "branches'",
+// in the format json, or csv. groupBy defines the supported groupings, the first one is the default,
+// and groupByDescription describes them.
+func withConsumptionExport(
+	s map[string]*schema.Schema, subject, groupByDescription string, groupBy ...string,
+) map[string]*schema.Schema {
+	s["export_format"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.StringInSlice([]string{"json", "csv"}, false),
+		Description: `Format to render the ` + subject + ` consumption metrics to the attribute ` + "`export`" + `,
+i.e. "json", or "csv". The metrics are not rendered if not set.`,
+	}
+	s["export_group_by"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      groupBy[0],
+		ValidateFunc: validation.StringInSlice(groupBy, false),
+		Description:  `Grouping of the exported consumption metrics, i.e. ` + groupByDescription + `.`,
+	}
+	s["export"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+		Description: `Consumption metrics rendered in the format ` + "`export_format`" + `, e.g. to upload the report
+to the object storage.`,
+	}
+	return s
+}
+
+// consumptionMetricsColumns defines the order of the exported consumption metrics.
+var consumptionMetricsColumns = []string{
+	"active_time_seconds", "compute_time_seconds", "written_data_bytes", "data_transfer_bytes",
+}
+
+// exportConsumption renders the rows of the consumption metrics in the format json, or csv.
+// Every row holds the values of the keys followed by the values of the metrics.
+func exportConsumption(format string, keys, metrics []string, rows [][]string) (string, error) {
+	columns := append(append([]string{}, keys...), metrics...)
+
+	switch format {
+	case "csv":
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(columns); err != nil {
+			return "", err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	default:
+		o := make([]map[string]interface{}, len(rows))
+		for i, row := range rows {
+			o[i] = map[string]interface{}{}
+			for j, k := range columns {
+				if j < len(keys) {
+					o[i][k] = row[j]
+					continue
+				}
+				v, _ := strconv.ParseInt(row[j], 10, 64)
+				o[i][k] = v
+			}
+		}
+		b, err := json.Marshal(o)
+		return string(b), err
+	}
+}
+
+func consumptionRow(keys []string, values map[string]int64, metrics []string) []string {
+	o := append([]string{}, keys...)
+	for _, k := range metrics {
+		o = append(o, strconv.FormatInt(values[k], 10))
+	}
+	return o
+}
+
 type t interface {
 	bool | string | int | int32 | int64 | float64 | float32 | neon.PgVersion | neon.ComputeUnit | neon.Provisioner | neon.EndpointPoolerMode | neon.SuspendTimeoutSeconds
 }
@@ -76,6 +282,215 @@ details: https://neon.tech/docs/manage/endpoints#compute-size-and-autoscaling-co
 	return
 }
 
+type sdkAccountLimits interface {
+	GetCurrentUserInfo() (neon.CurrentUserInfoResponse, error)
+}
+
+// maxAutoscalingLimitCache caches the account's maximum autoscaling limit to avoid redundant API calls upon plan.
+var maxAutoscalingLimitCache sync.Map
+
+func maxAutoscalingLimit(client sdkAccountLimits) (float64, error) {
+	if v, ok := maxAutoscalingLimitCache.Load(client); ok {
+		return v.(float64), nil
+	}
+
+	resp, err := client.GetCurrentUserInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	o := float64(resp.MaxAutoscalingLimit)
+	maxAutoscalingLimitCache.Store(client, o)
+	return o, nil
+}
+
+// customizeDiffAutoscalingLimitMax fails the plan if the attribute key exceeds the maximum autoscaling limit
+// permitted by the account's plan. The validation is skipped if the account's limit cannot be fetched,
+// e.g. when the organization's API key is used.
+func customizeDiffAutoscalingLimitMax(
+	ctx context.Context, d *schema.ResourceDiff, client sdkAccountLimits, key string,
+) error {
+	if !d.HasChange(key) || !d.NewValueKnown(key) {
+		return nil
+	}
+
+	v, ok := d.Get(key).(float64)
+	if !ok || v == 0 {
+		return nil
+	}
+
+	limit, err := maxAutoscalingLimit(client)
+	if err != nil {
+		tflog.Warn(ctx, "cannot fetch the account's maximum autoscaling limit, skip validation of "+key,
+			map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+
+	return validateAutoscalingLimitMax(v, limit, key)
+}
+
+func validateAutoscalingLimitMax(v, limit float64, name string) error {
+	if limit <= 0 || v <= limit {
+		return nil
+	}
+	return fmt.Errorf(
+		"%v exceeds the maximum autoscaling limit of the account's plan for %s, the limit is %v CU", v, name, limit,
+	)
+}
+
+type sdkRegions interface {
+	GetActiveRegions() (neon.ActiveRegionsResponse, error)
+}
+
+// activeRegionsCache caches the regions supported by the API to avoid redundant API calls upon plan.
+var activeRegionsCache sync.Map
+
+func activeRegions(client sdkRegions) ([]neon.RegionResponse, error) {
+	if v, ok := activeRegionsCache.Load(client); ok {
+		return v.([]neon.RegionResponse), nil
+	}
+
+	resp, err := client.GetActiveRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	activeRegionsCache.Store(client, resp.Regions)
+	return resp.Regions, nil
+}
+
+// customizeDiffRegionID fails the plan if the region is not supported by the API, e.g. mistyped.
+// The validation is skipped if the supported regions cannot be fetched.
+func customizeDiffRegionID(ctx context.Context, d *schema.ResourceDiff, client sdkRegions) error {
+	const key = "region_id"
+	if !d.HasChange(key) || !d.NewValueKnown(key) {
+		return nil
+	}
+
+	v, _ := d.Get(key).(string)
+	if v == "" {
+		return nil
+	}
+
+	if d.Get("skip_region_validation").(bool) {
+		tflog.Warn(ctx, "skip validation of "+key, map[string]interface{}{key: v})
+		return nil
+	}
+
+	regions, err := activeRegions(client)
+	if err != nil {
+		tflog.Warn(ctx, "cannot fetch the supported regions, skip validation of "+key,
+			map[string]interface{}{"error": err.Error()})
+		return nil
+	}
+
+	return validateRegionID(v, regions)
+}
+
+func validateRegionID(v string, regions []neon.RegionResponse) error {
+	if len(regions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(regions))
+	for i, r := range regions {
+		if r.RegionID == v {
+			return nil
+		}
+		ids[i] = r.RegionID
+	}
+	return errors.New("region_id " + v + " is not supported, the supported regions: " + strings.Join(ids, ", "))
+}
+
+// regionCloudProvider returns the cloud provider of the region given its ID, e.g. azure for azure-eastus2.
+func regionCloudProvider(regionID string) string {
+	if v, _, ok := strings.Cut(regionID, "-"); ok {
+		return v
+	}
+	return ""
+}
+
+// defaultConsoleURL defines the base URL of the Neon console of the default API's base URL.
+const defaultConsoleURL = "https://console.neon.tech/app"
+
+// consoleURL returns the link to the Neon console page defined by the path elements,
+// e.g. "projects", "shiny-wind-028834", given the console's base URL, or defaultConsoleURL if empty.
+func consoleURL(base string, elem ...string) string {
+	if base == "" {
+		base = defaultConsoleURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(elem, "/")
+}
+
+// consoleBaseURL returns the console's base URL configured by the provider, or the empty string if meta is not
+// the provider's client, e.g. in the unit tests.
+func consoleBaseURL(meta interface{}) string {
+	if c, ok := meta.(*providerClient); ok && c != nil {
+		return c.consoleURL
+	}
+	return ""
+}
+
+// newConsoleURL returns the console's base URL given the provider's attribute console_url, and the API's base URL.
+// The console is served by the API's host unless the attribute is set, e.g. https://console.neon.tech/app
+// for https://console.neon.tech/api/v2.
+func newConsoleURL(console, apiBaseURL string) (string, error) {
+	if console != "" {
+		return console, nil
+	}
+	if apiBaseURL == "" {
+		return defaultConsoleURL, nil
+	}
+	u, err := url.Parse(apiBaseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %s", apiBaseURL)
+	}
+	return u.Scheme + "://" + u.Host + "/app", nil
+}
+
+// poolerHost returns the hostname to connect to the endpoint using the connection pooler.
+// See details: https://neon.tech/docs/connect/connection-pooling
+func poolerHost(host string) string {
+	const suffix = "-pooler"
+	endpointID, domain, ok := strings.Cut(host, ".")
+	if !ok || endpointID == "" || strings.HasSuffix(endpointID, suffix) {
+		return host
+	}
+	return endpointID + suffix + "." + domain
+}
+
+// isDefinedInConfig reports whether the attribute is defined in the configuration explicitly,
+// including the zero value, e.g. 0, which cannot be distinguished from the unset value using d.GetOk.
+// The key follows the notation of d.Get, e.g. "default_endpoint_settings.0.suspend_timeout_seconds".
+func isDefinedInConfig(d *schema.ResourceData, key string) bool {
+	return isDefinedInRawConfig(d.GetRawConfig(), key)
+}
+
+func isDefinedInRawConfig(v cty.Value, key string) bool {
+	for _, k := range strings.Split(key, ".") {
+		if v.IsNull() || !v.IsKnown() {
+			return false
+		}
+
+		switch t := v.Type(); {
+		case t.IsObjectType():
+			if !t.HasAttribute(k) {
+				return false
+			}
+			v = v.GetAttr(k)
+		case t.IsListType() || t.IsTupleType():
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= v.LengthInt() {
+				return false
+			}
+			v = v.AsValueSlice()[i]
+		default:
+			return false
+		}
+	}
+	return v.IsKnown() && !v.IsNull()
+}
+
 type complexID struct {
 	ProjectID, BranchID, Name string
 }
@@ -86,6 +501,51 @@ func setResourceAttrsFromComplexID(d *schema.ResourceData, r complexID) {
 	_ = d.Set("name", r.Name)
 }
 
+// branchIDFromConfig returns the branch ID, it resolves the branch name if it's set instead of the ID.
+func branchIDFromConfig(d *schema.ResourceData, client sdkBranchLister) (string, error) {
+	v, ok := d.GetOk("branch_name")
+	if !ok {
+		return d.Get("branch_id").(string), nil
+	}
+
+	branchID, err := findBranchIDByName(client, d.Get("project_id").(string), v.(string))
+	if err != nil {
+		return "", err
+	}
+
+	return branchID, d.Set("branch_id", branchID)
+}
+
+// customizeDiffBranchName resolves the configured branch name to the branch ID, i.e. the resource is only replaced
+// if the name refers to another branch, and not if the reference switches between the branch's ID and name.
+func customizeDiffBranchName(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(sdkBranchLister)
+	// the name is resolved upon creation
+	if d.Id() == "" || !ok {
+		return nil
+	}
+	if !d.NewValueKnown("branch_name") {
+		return d.SetNewComputed("branch_id")
+	}
+	name := d.Get("branch_name").(string)
+	if name == "" {
+		return nil
+	}
+
+	branch, ok, err := findBranchByName(client, d.Get("project_id").(string), name)
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		// the branch is created by the same apply
+		return d.SetNewComputed("branch_id")
+	case branch.ID != d.Get("branch_id").(string):
+		return d.SetNew("branch_id", branch.ID)
+	default:
+		return nil
+	}
+}
+
 func (v complexID) toString() string {
 	return v.ProjectID + "/" + v.BranchID + "/" + v.Name
 }
@@ -4,7 +4,11 @@
 package provider
 
 import (
+	"net/http"
 	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	neon "github.com/kislerdm/neon-sdk-go"
 )
 
 func Test_validateAutoscallingLimit(t *testing.T) {
@@ -46,3 +50,258 @@ func Test_validateAutoscallingLimit(t *testing.T) {
 		},
 	)
 }
+
+func Test_validateAutoscalingLimitMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       float64
+		limit   float64
+		wantErr bool
+	}{
+		{name: "below the limit", v: 2, limit: 4},
+		{name: "equal to the limit", v: 4, limit: 4},
+		{name: "no limit defined", v: 10, limit: 0},
+		{name: "above the limit", v: 8, limit: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAutoscalingLimitMax(tt.v, tt.limit, "autoscaling_limit_max_cu")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAutoscalingLimitMax() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_maxAutoscalingLimit(t *testing.T) {
+	// GIVEN
+	var cnt int
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			cnt++
+			return newHTTPResponse(http.StatusOK, `{"max_autoscaling_limit":4}`), nil
+		}),
+	})
+	t.Cleanup(func() { maxAutoscalingLimitCache.Delete(client) })
+
+	for i := 0; i < 2; i++ {
+		// WHEN
+		got, err := maxAutoscalingLimit(client)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != 4 {
+			t.Errorf("unexpected limit: %v", got)
+		}
+	}
+	if cnt != 1 {
+		t.Errorf("the user info shall be requested once, got: %d", cnt)
+	}
+}
+
+func Test_validateRegionID(t *testing.T) {
+	regions := []neon.RegionResponse{{RegionID: "aws-us-east-2"}, {RegionID: "azure-eastus2"}}
+
+	tests := []struct {
+		name    string
+		v       string
+		regions []neon.RegionResponse
+		wantErr bool
+	}{
+		{name: "aws region", v: "aws-us-east-2", regions: regions},
+		{name: "azure region", v: "azure-eastus2", regions: regions},
+		{name: "unknown region", v: "azure-westus9", regions: regions, wantErr: true},
+		{name: "no regions fetched", v: "azure-westus9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRegionID(tt.v, tt.regions); (err != nil) != tt.wantErr {
+				t.Errorf("validateRegionID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_regionCloudProvider(t *testing.T) {
+	for in, want := range map[string]string{
+		"aws-us-east-2": "aws",
+		"azure-eastus2": "azure",
+		"unknown":       "",
+	} {
+		if got := regionCloudProvider(in); got != want {
+			t.Errorf("regionCloudProvider(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func Test_branchIDFromConfig(t *testing.T) {
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			return newHTTPResponse(http.StatusOK, `{"branches":[{"id":"br-dev","name":"dev"}]}`), nil
+		}),
+	})
+
+	t.Run("shall resolve the branch name", func(t *testing.T) {
+		d := resourceDatabase().TestResourceData()
+		_ = d.Set("project_id", "bar")
+		_ = d.Set("branch_name", "dev")
+
+		got, err := branchIDFromConfig(d, client)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "br-dev" {
+			t.Errorf("unexpected branch ID: %s", got)
+		}
+		if v := d.Get("branch_id").(string); v != "br-dev" {
+			t.Errorf("unexpected branch_id attribute: %s", v)
+		}
+	})
+
+	t.Run("shall return the branch ID", func(t *testing.T) {
+		d := resourceRole().TestResourceData()
+		_ = d.Set("project_id", "bar")
+		_ = d.Set("branch_id", "br-qux")
+
+		// the nil client guarantees that no API call is made
+		got, err := branchIDFromConfig(d, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "br-qux" {
+			t.Errorf("unexpected branch ID: %s", got)
+		}
+	})
+}
+
+func Test_poolerHost(t *testing.T) {
+	tests := map[string]string{
+		"ep-foo-123.us-east-2.aws.neon.tech":        "ep-foo-123-pooler.us-east-2.aws.neon.tech",
+		"ep-foo-123-pooler.us-east-2.aws.neon.tech": "ep-foo-123-pooler.us-east-2.aws.neon.tech",
+		"ep-foo-123.eastus2.azure.neon.tech":        "ep-foo-123-pooler.eastus2.azure.neon.tech",
+		"localhost":                                 "localhost",
+		"":                                          "",
+	}
+	for in, want := range tests {
+		if got := poolerHost(in); got != want {
+			t.Errorf("unexpected pooler host for %s. want: %s, got: %s", in, want, got)
+		}
+	}
+}
+
+func Test_newConsoleURL(t *testing.T) {
+	tests := []struct {
+		console, apiBaseURL string
+		want                string
+		wantErr             bool
+	}{
+		{apiBaseURL: "", want: "https://console.neon.tech/app"},
+		{apiBaseURL: "https://console.neon.tech/api/v2", want: "https://console.neon.tech/app"},
+		{apiBaseURL: "https://console.stage.neon.tech/api/v2", want: "https://console.stage.neon.tech/app"},
+		{apiBaseURL: "http://localhost:8080/api/v2", want: "http://localhost:8080/app"},
+		{console: "https://neon.example.com/app", apiBaseURL: "http://localhost:8080/api/v2", want: "https://neon.example.com/app"},
+		{apiBaseURL: "localhost", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := newConsoleURL(tt.console, tt.apiBaseURL)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newConsoleURL(%q, %q) error = %v, wantErr %v", tt.console, tt.apiBaseURL, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("newConsoleURL(%q, %q) = %s, want %s", tt.console, tt.apiBaseURL, got, tt.want)
+		}
+	}
+
+	t.Run("shall link the console of the provider's client", func(t *testing.T) {
+		meta := &providerClient{consoleURL: "http://localhost:8080/app/"}
+		if got := consoleURL(consoleBaseURL(meta), "projects", "foo"); got != "http://localhost:8080/app/projects/foo" {
+			t.Errorf("unexpected console URL %s", got)
+		}
+		if got := consoleURL(consoleBaseURL(&sdkClientStub{}), "projects", "foo"); got != "https://console.neon.tech/app/projects/foo" {
+			t.Errorf("unexpected default console URL %s", got)
+		}
+	})
+}
+
+func Test_pgSettingValuesEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"4MB", "4096kB", true},
+		{"1GB", "1024MB", true},
+		{"4MB", "4000kB", false},
+		{"1min", "60s", true},
+		{"1h", "3600000ms", true},
+		{"10s", "10000", false},
+		{"1GB", "1s", false},
+		{"on", "true", true},
+		{"off", "on", false},
+		{"100", "100", true},
+		{"100", "200", false},
+		{"Replica", "replica", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := pgSettingValuesEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("pgSettingValuesEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isDefinedInRawConfig(t *testing.T) {
+	v := cty.ObjectVal(map[string]cty.Value{
+		"parent_timestamp": cty.NumberIntVal(0),
+		"name":             cty.NullVal(cty.String),
+		"default_endpoint_settings": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"suspend_timeout_seconds":  cty.NumberIntVal(0),
+				"autoscaling_limit_min_cu": cty.NullVal(cty.Number),
+			}),
+		}),
+		"unknown": cty.UnknownVal(cty.String),
+	})
+
+	tests := map[string]bool{
+		"parent_timestamp": true,
+		"name":             false,
+		"missing":          false,
+		"unknown":          false,
+		"default_endpoint_settings.0.suspend_timeout_seconds":  true,
+		"default_endpoint_settings.0.autoscaling_limit_min_cu": false,
+		"default_endpoint_settings.1.suspend_timeout_seconds":  false,
+		"default_endpoint_settings.foo":                        false,
+	}
+	for key, want := range tests {
+		if got := isDefinedInRawConfig(v, key); got != want {
+			t.Errorf("unexpected result for %s. want: %v, got: %v", key, want, got)
+		}
+	}
+
+	if isDefinedInRawConfig(cty.NilVal, "name") {
+		t.Errorf("no attributes are expected to be defined in the empty configuration")
+	}
+}
+
+func FuzzPgSettingValuesEqual(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"1GB", "1024MB"}, {"4MB", "4096kB"}, {"1h", "60min"}, {"on", "true"}, {"off", "0"}, {"", "0"},
+		{"9223372036854775807TB", "1"}, {"-1s", "-1000ms"}, {"1.5GB", "1536MB"}, {"kB", "MB"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if !pgSettingValuesEqual(a, a) {
+			t.Errorf("value %q is not equal to itself", a)
+		}
+		if pgSettingValuesEqual(a, b) != pgSettingValuesEqual(b, a) {
+			t.Errorf("comparison of %q and %q is not symmetric", a, b)
+		}
+	})
+}
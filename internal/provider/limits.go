@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+const plansURL = "https://neon.tech/docs/introduction/plans"
+
+// apiLimit defines the limit of the account's plan which the API enforces by rejecting the request
+// with the status code 422.
+type apiLimit struct {
+	name string
+	// keywords matches the words of the lower-cased API error's message which identify the limit.
+	keywords *regexp.Regexp
+	// usage returns the limit, and the current usage, or the requested value.
+	usage       func(client sdkLimitUsage, d *schema.ResourceData) (string, error)
+	remediation string
+}
+
+// apiLimits lists the limits in the order of matching, the more specific limits go first, e.g. the message
+// "compute size limit exceeded for the branch" corresponds to the compute size limit, and the message
+// "project size limit exceeded" corresponds to the storage limit, rather than to the branches, or projects limit.
+var apiLimits = []apiLimit{
+	{
+		name:     "compute size",
+		keywords: regexp.MustCompile(`\b(autoscaling|compute|cus?)\b`),
+		usage:    computeLimitUsage,
+		remediation: "Reduce the autoscaling limits of the endpoint, e.g. " + "`autoscaling_limit_max_cu`" +
+			", or upgrade the plan to raise the limit: " + plansURL,
+	},
+	{
+		name:     "storage",
+		keywords: regexp.MustCompile(`\b(storage|size)\b`),
+		usage:    storageLimitUsage,
+		remediation: "Delete the unused branches and data, or reduce the project's " +
+			"`history_retention_seconds`, or upgrade the plan to raise the limit: " + plansURL,
+	},
+	{
+		name:     "branches",
+		keywords: regexp.MustCompile(`\bbranch`),
+		usage:    branchesLimitUsage,
+		remediation: "Delete the unused branches, e.g. the preview branches of the merged pull requests, " +
+			"or upgrade the plan to raise the limit: " + plansURL,
+	},
+	{
+		name:     "projects",
+		keywords: regexp.MustCompile(`\bproject`),
+		usage:    projectsLimitUsage,
+		remediation: "Delete the unused projects, or transfer them to another account, " +
+			"or upgrade the plan to raise the limit: " + plansURL,
+	},
+}
+
+type sdkLimitUsage interface {
+	sdkBranchLister
+	sdkAccountLimits
+	GetProject(string) (neon.ProjectResponse, error)
+	ListProjects(*string, *int, *string, *string) (neon.ListProjectsRespObj, error)
+}
+
+var reAPILimitError = regexp.MustCompile(`\[HTTP Code: 422\]\[Error Code: [^\]]*\] (.+)`)
+
+// findAPILimit returns the limit which the API error corresponds to, and the API error's message.
+// It returns false if the error is not the limit error.
+func findAPILimit(s string) (apiLimit, string, bool) {
+	m := reAPILimitError.FindStringSubmatch(s)
+	if m == nil {
+		return apiLimit{}, "", false
+	}
+
+	msg := m[1]
+	v := strings.ToLower(msg)
+	if !strings.Contains(v, "limit") && !strings.Contains(v, "exceed") {
+		return apiLimit{}, "", false
+	}
+	for _, l := range apiLimits {
+		if l.keywords.MatchString(v) {
+			return l, msg, true
+		}
+	}
+	return apiLimit{}, "", false
+}
+
+// diagnostic returns the diagnostic which states the exceeded limit, the current usage,
+// and the options to proceed. The usage is omitted if it cannot be fetched.
+func (l apiLimit) diagnostic(
+	ctx context.Context, d *schema.ResourceData, meta interface{}, msg string,
+) diag.Diagnostic {
+	detail := "The API rejected the request: " + msg + "."
+	if client, ok := meta.(sdkLimitUsage); ok && l.usage != nil {
+		usage, err := l.usage(client, d)
+		switch {
+		case err != nil:
+			tflog.Debug(ctx, "cannot fetch the usage of the limit", map[string]interface{}{
+				"limit": l.name, "error": err.Error(),
+			})
+		case usage != "":
+			detail += "\n" + usage
+		}
+	}
+	return diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  "The limit of " + l.name + " of the account's plan is exceeded",
+		Detail:   detail + "\n" + l.remediation,
+	}
+}
+
+// withLimitDiagnostics wraps the resource's callbacks which create and update the resources to
+// replace the API errors caused by the limits of the account's plan with the actionable diagnostics.
+func withLimitDiagnostics(r *schema.Resource) *schema.Resource {
+	type callback = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+	wrap := func(fn callback) callback {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			diags := fn(ctx, d, meta)
+			for i, v := range diags {
+				if v.Severity != diag.Error {
+					continue
+				}
+				if l, msg, ok := findAPILimit(v.Summary); ok {
+					diags[i] = l.diagnostic(ctx, d, meta, msg)
+				}
+			}
+			return diags
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	return r
+}
+
+// limitProjectID returns the ID of the project the resource belongs to.
+func limitProjectID(d *schema.ResourceData) string {
+	if v, ok := d.Get("project_id").(string); ok && v != "" {
+		return v
+	}
+	return d.Id()
+}
+
+func branchesLimitUsage(client sdkLimitUsage, d *schema.ResourceData) (string, error) {
+	projectID := limitProjectID(d)
+	if projectID == "" {
+		return "", nil
+	}
+
+	project, err := client.GetProject(projectID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return "", err
+	}
+
+	o := fmt.Sprintf("Current usage: %d branches", len(resp.Branches))
+	if project.Project.Owner != nil && project.Project.Owner.BranchesLimit > 0 {
+		o = fmt.Sprintf("Current usage: %d of %d branches", len(resp.Branches), project.Project.Owner.BranchesLimit)
+	}
+	o += " in the project " + projectID
+	if v := deletableBranches(resp.Branches); v != "" {
+		return o + v, nil
+	}
+	return o + ".", nil
+}
+
+func computeLimitUsage(client sdkLimitUsage, d *schema.ResourceData) (string, error) {
+	limit, err := maxAutoscalingLimit(client)
+	if err != nil {
+		return "", err
+	}
+
+	o := "Limit: " + formatCU(limit) + " CU per endpoint"
+	for _, k := range []string{"autoscaling_limit_max_cu", "default_endpoint_settings.0.autoscaling_limit_max_cu"} {
+		if v, ok := d.GetOk(k); ok {
+			return o + ", requested: " + formatCU(v.(float64)) + " CU.", nil
+		}
+	}
+	return o + ".", nil
+}
+
+func storageLimitUsage(client sdkLimitUsage, d *schema.ResourceData) (string, error) {
+	projectID := limitProjectID(d)
+	if projectID == "" {
+		return "", nil
+	}
+
+	resp, err := client.GetProject(projectID)
+	if err != nil {
+		return "", err
+	}
+
+	var o []string
+	if q := resp.Project.Settings; q != nil && q.Quota != nil && q.Quota.LogicalSizeBytes != nil &&
+		*q.Quota.LogicalSizeBytes > 0 {
+		o = append(o, fmt.Sprintf("Limit: %d bytes of the logical size per branch.", *q.Quota.LogicalSizeBytes))
+	}
+	if v := resp.Project.SyntheticStorageSize; v != nil {
+		o = append(o, fmt.Sprintf("Current usage: %d bytes in the project %s.", *v, projectID))
+	}
+	return strings.Join(o, " "), nil
+}
+
+func projectsLimitUsage(client sdkLimitUsage, d *schema.ResourceData) (string, error) {
+	user, err := client.GetCurrentUserInfo()
+	if err != nil {
+		return "", err
+	}
+
+	var orgID *string
+	if v, ok := d.GetOk("org_id"); ok {
+		orgID = pointer(v.(string))
+	}
+	resp, err := client.ListProjects(nil, pointer(400), nil, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	o := fmt.Sprintf("Current usage: %d projects", len(resp.Projects))
+	if user.ProjectsLimit > 0 {
+		o += fmt.Sprintf(" of the limit of %d projects", user.ProjectsLimit)
+	}
+	return o + ".", nil
+}
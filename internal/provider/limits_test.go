@@ -0,0 +1,141 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_findAPILimit(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := map[string]struct {
+		err       string
+		wantLimit string
+		wantOK    bool
+	}{
+		"shall match the branches limit": {
+			err:       "[HTTP Code: 422][Error Code: ] branches limit exceeded",
+			wantLimit: "branches",
+			wantOK:    true,
+		},
+		"shall match the compute size limit": {
+			err:       "[HTTP Code: 422][Error Code: ] max autoscaling limit exceeds the plan's limit",
+			wantLimit: "compute size",
+			wantOK:    true,
+		},
+		"shall match the storage limit before the projects limit": {
+			err:       "[HTTP Code: 422][Error Code: ] project size limit exceeded",
+			wantLimit: "storage",
+			wantOK:    true,
+		},
+		"shall match the projects limit": {
+			err:       "[HTTP Code: 422][Error Code: ] projects limit exceeded",
+			wantLimit: "projects",
+			wantOK:    true,
+		},
+		"shall match the compute size limit before the branches limit": {
+			err:       "[HTTP Code: 422][Error Code: ] compute size limit exceeded for the branch",
+			wantLimit: "compute size",
+			wantOK:    true,
+		},
+		"shall match the compute units limit before the branches limit": {
+			err:       "[HTTP Code: 422][Error Code: ] branch endpoint exceeds the limit of 4 CU",
+			wantLimit: "compute size",
+			wantOK:    true,
+		},
+		"shall match the storage limit before the branches limit": {
+			err:       "[HTTP Code: 422][Error Code: ] branch logical size limit exceeded",
+			wantLimit: "storage",
+			wantOK:    true,
+		},
+		"shall match the storage limit before the projects limit given the storage keyword": {
+			err:       "[HTTP Code: 422][Error Code: ] project storage limit exceeded",
+			wantLimit: "storage",
+			wantOK:    true,
+		},
+		"shall match the branches limit given the current usage": {
+			err:       "[HTTP Code: 422][Error Code: ] branches limit exceeded, current usage: 10 branches",
+			wantLimit: "branches",
+			wantOK:    true,
+		},
+		"shall match the branches limit before the projects limit": {
+			err:       "[HTTP Code: 422][Error Code: ] project branches limit exceeded",
+			wantLimit: "branches",
+			wantOK:    true,
+		},
+		"shall not match the validation error": {
+			err: "[HTTP Code: 422][Error Code: ] invalid branch name",
+		},
+		"shall not match the limit error with another status code": {
+			err: "[HTTP Code: 400][Error Code: ] branches limit exceeded",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, _, ok := findAPILimit(tt.err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantLimit, got.name)
+		})
+	}
+}
+
+func Test_withLimitDiagnostics(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	r := withLimitDiagnostics(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"project_id": {Type: schema.TypeString, Required: true},
+		},
+		CreateContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+			return diag.FromErr(errors.New("[HTTP Code: 422][Error Code: ] branches limit exceeded"))
+		},
+	})
+
+	d := r.TestResourceData()
+	_ = d.Set("project_id", projectID)
+
+	// WHEN
+	diags := r.CreateContext(context.TODO(), d, client)
+
+	// THEN
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "The limit of branches of the account's plan is exceeded", diags[0].Summary)
+		assert.Equal(t,
+			"The API rejected the request: branches limit exceeded.\n"+
+				"Current usage: 1 branches in the project "+projectID+".\n"+
+				"Delete the unused branches, e.g. the preview branches of the merged pull requests, "+
+				"or upgrade the plan to raise the limit: https://neon.tech/docs/introduction/plans",
+			diags[0].Detail,
+		)
+	}
+}
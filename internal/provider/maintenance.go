@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// maintenanceDiagnostic returns the plan warning if the project's maintenance begins within the window from now.
+// It returns nil if no maintenance is scheduled, or the maintenance began already.
+func maintenanceDiagnostic(
+	typeName string, planned tftypes.Value, now time.Time, window time.Duration,
+) *tfprotov5.Diagnostic {
+	if typeName != "neon_project" || window <= 0 {
+		return nil
+	}
+
+	attrs, ok := objectAttributes(planned)
+	if !ok {
+		return nil
+	}
+
+	v, ok := attrs["maintenance_starts_at"]
+	if !ok || v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	var s string
+	if err := v.As(&s); err != nil || s == "" {
+		return nil
+	}
+	startsAt, err := time.Parse(time.RFC3339, s)
+	if err != nil || startsAt.Before(now) || startsAt.Sub(now) > window {
+		return nil
+	}
+
+	var name string
+	if el, ok := attrs["name"]; ok && el.IsKnown() && !el.IsNull() {
+		_ = el.As(&name)
+	}
+
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Upcoming maintenance of neon_project",
+		Detail: "The maintenance of the project " + name + " begins at " + s + ", in " +
+			startsAt.Sub(now).Round(time.Minute).String() + ".\nThe project's compute may be restarted " +
+			"during the maintenance, consider to apply the changes afterwards.",
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_maintenanceDiagnostic(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	projectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":                  tftypes.String,
+		"maintenance_starts_at": tftypes.String,
+	}}
+	project := func(startsAt interface{}) tftypes.Value {
+		return tftypes.NewValue(projectType, map[string]tftypes.Value{
+			"name":                  tftypes.NewValue(tftypes.String, "foo"),
+			"maintenance_starts_at": tftypes.NewValue(tftypes.String, startsAt),
+		})
+	}
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		typeName string
+		planned  tftypes.Value
+		window   time.Duration
+		want     bool
+	}{
+		{
+			name:     "maintenance within the window",
+			typeName: "neon_project",
+			planned:  project("2024-05-15T20:00:00Z"),
+			window:   24 * time.Hour,
+			want:     true,
+		},
+		{
+			name:     "maintenance after the window",
+			typeName: "neon_project",
+			planned:  project("2024-05-17T10:00:00Z"),
+			window:   24 * time.Hour,
+		},
+		{
+			name:     "maintenance began already",
+			typeName: "neon_project",
+			planned:  project("2024-05-15T09:00:00Z"),
+			window:   24 * time.Hour,
+		},
+		{
+			name:     "no maintenance scheduled",
+			typeName: "neon_project",
+			planned:  project(""),
+			window:   24 * time.Hour,
+		},
+		{
+			name:     "maintenance is unknown",
+			typeName: "neon_project",
+			planned:  project(tftypes.UnknownValue),
+			window:   24 * time.Hour,
+		},
+		{
+			name:     "warning is disabled",
+			typeName: "neon_project",
+			planned:  project("2024-05-15T20:00:00Z"),
+		},
+		{
+			name:     "other resource",
+			typeName: "neon_endpoint",
+			planned:  project("2024-05-15T20:00:00Z"),
+			window:   24 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maintenanceDiagnostic(tt.typeName, tt.planned, now, tt.window)
+			if !tt.want {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, got.Severity)
+				assert.Contains(t, got.Detail, "begins at 2024-05-15T20:00:00Z, in 10h0m0s")
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+)
+
+// mockAPIKey is the API key used by the provider in the mock mode if no key is configured.
+const mockAPIKey = "mock"
+
+// defaultMockStateFile defines the file the simulated resources are persisted to by default,
+// the path is relative to terraform's working directory.
+const defaultMockStateFile = ".terraform/neon-mock-api.json"
+
+func schemaMock() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Description: `Set the block to run the provider against the simulation of the Neon API instead of
+the Neon API, e.g. to test the modules using ` + "`terraform test`" + ` without the Neon account.
+The simulated resources get the realistic IDs, hosts and connection URIs, and the project is locked while
+its operations are running. The API key is not required.
+The simulated resources are persisted to the file ` + "`state_file`" + `, i.e. they outlive the provider's process
+which terraform restarts between plan, apply and refresh, and between the runs of ` + "`terraform test`" + `.
+**Note** that only the projects, branches, endpoints, roles and databases are simulated.`,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"operation_duration_ms": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      int(fakeapi.DefaultOperationDuration / time.Millisecond),
+					ValidateFunc: intValidationNotNegative,
+					Description: `Duration of the simulated operations in milliseconds, e.g. 0 to finish them instantly.
+The mutating requests are rejected with the status code 423 while the project's operations are running.`,
+				},
+				"state_file": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  defaultMockStateFile,
+					Description: `Path to the file the simulated resources are persisted to, relative to terraform's
+working directory. Set the empty string to keep them in the memory of the provider's process only.`,
+				},
+			},
+		},
+	}
+}
+
+// newMockAPI returns the simulated API given the provider's block mock, and false if the block is not set.
+func newMockAPI(v []interface{}) (*fakeapi.Server, bool) {
+	if len(v) == 0 {
+		return nil, false
+	}
+
+	o := fakeapi.New()
+	if m, ok := v[0].(map[string]interface{}); ok {
+		o.OperationDuration = time.Duration(m["operation_duration_ms"].(int)) * time.Millisecond
+		o.StateFile = m["state_file"].(string)
+	}
+	return o, true
+}
@@ -0,0 +1,123 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvider_mock(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	prov := New("test")
+	ctx := context.TODO()
+
+	// WHEN
+	diags := prov.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"api_key": "",
+		"mock": []interface{}{map[string]interface{}{
+			"operation_duration_ms": 0,
+			"state_file":            filepath.Join(t.TempDir(), "mock.json"),
+		}},
+	}))
+
+	// THEN
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	meta := prov.Meta().(*providerClient)
+
+	project, err := meta.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, project.Project.ID)
+	if assert.Len(t, project.Endpoints, 1) {
+		assert.NotEmpty(t, project.Endpoints[0].Host)
+	}
+
+	d := resourceBranch().TestResourceData()
+	_ = d.Set("project_id", project.Project.ID)
+	_ = d.Set("name", "dev")
+	if err := resourceBranchCreate(ctx, d, meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, project.Branch.Name, d.Get("parent_name"))
+
+	if err := resourceBranchRead(ctx, d, meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "dev", d.Get("name"))
+}
+
+func TestProvider_mockStateFile(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the provider's instances which share the state file,
+	// e.g. started by terraform one after another for plan, apply and refresh
+	ctx := context.TODO()
+	stateFile := filepath.Join(t.TempDir(), ".terraform", "neon-mock-api.json")
+	newMeta := func(t *testing.T) *providerClient {
+		t.Helper()
+
+		prov := New("test")
+		diags := prov.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"mock": []interface{}{map[string]interface{}{
+				"operation_duration_ms": 0,
+				"state_file":            stateFile,
+			}},
+		}))
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		return prov.Meta().(*providerClient)
+	}
+
+	// WHEN the resources are created by the first instance
+	first := newMeta(t)
+	project, err := first.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := resourceBranch().TestResourceData()
+	_ = d.Set("project_id", project.Project.ID)
+	_ = d.Set("name", "dev")
+	if err := resourceBranchCreate(ctx, d, first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN the second instance reads them
+	second := newMeta(t)
+	state := resourceBranch().TestResourceData()
+	state.SetId(d.Id())
+	_ = state.Set("project_id", project.Project.ID)
+	if err := resourceBranchRead(ctx, state, second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "dev", state.Get("name"))
+	assert.Equal(t, project.Branch.Name, state.Get("parent_name"))
+
+	// WHEN the branch is deleted by the second instance
+	if err := resourceBranchDelete(ctx, state, second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN the first instance does not find it
+	_, err = first.GetProjectBranch(project.Project.ID, d.Id())
+	assert.Error(t, err)
+}
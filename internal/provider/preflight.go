@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+type sdkPreflight interface {
+	ListProjects(*string, *int, *string, *string) (neon.ListProjectsRespObj, error)
+	GetOrganization(string) (neon.Organization, error)
+}
+
+// preflight verifies that the API key is valid, that it grants access to the projects,
+// and to the organization if it's defined. It's run upon the provider's configuration
+// to fail the plan before any resource is read, or changed.
+// Note that the write access cannot be verified without changing the resources.
+func preflight(client sdkPreflight, orgID string) error {
+	var org *string
+	if orgID != "" {
+		org = &orgID
+	}
+
+	if _, err := client.ListProjects(nil, pointer(1), nil, org); err != nil {
+		return preflightError("cannot list the projects", err)
+	}
+
+	if orgID != "" {
+		if _, err := client.GetOrganization(orgID); err != nil {
+			return preflightError("cannot access the organization "+orgID, err)
+		}
+	}
+
+	return nil
+}
+
+func preflightError(msg string, err error) error {
+	if e, ok := err.(neon.Error); ok {
+		switch e.HTTPCode {
+		case http.StatusUnauthorized:
+			return fmt.Errorf("preflight: %s: the API key is invalid, or revoked", msg)
+		case http.StatusForbidden, http.StatusNotFound:
+			return fmt.Errorf("preflight: %s: the API key lacks the permission: %w", msg, err)
+		}
+	}
+	return fmt.Errorf("preflight: %s: %w", msg, err)
+}
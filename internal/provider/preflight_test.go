@@ -0,0 +1,81 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_preflight(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name         string
+		orgID        string
+		projectsCode int
+		orgCode      int
+		wantErr      string
+	}{
+		{
+			name:         "valid key",
+			projectsCode: http.StatusOK,
+		},
+		{
+			name:         "valid key with the organization access",
+			orgID:        "org-foo",
+			projectsCode: http.StatusOK,
+			orgCode:      http.StatusOK,
+		},
+		{
+			name:         "invalid key",
+			projectsCode: http.StatusUnauthorized,
+			wantErr:      "preflight: cannot list the projects: the API key is invalid, or revoked",
+		},
+		{
+			name:         "no access to the organization",
+			orgID:        "org-foo",
+			projectsCode: http.StatusOK,
+			orgCode:      http.StatusForbidden,
+			wantErr:      "preflight: cannot access the organization org-foo: the API key lacks the permission",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			client, _ := neon.NewClient(neon.Config{
+				Key: "foo",
+				HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+					switch r.URL.Path {
+					case "/api/v2/projects":
+						assert.Equal(t, tt.orgID, r.URL.Query().Get("org_id"))
+						return newHTTPResponse(tt.projectsCode, `{"projects":[]}`), nil
+					case "/api/v2/organizations/org-foo":
+						return newHTTPResponse(tt.orgCode, `{"id":"org-foo"}`), nil
+					}
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+					return nil, nil
+				}),
+			})
+
+			// WHEN
+			err := preflight(client, tt.orgID)
+
+			// THEN
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
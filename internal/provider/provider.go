@@ -1,13 +1,22 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
+	"os/exec"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	neon "github.com/kislerdm/neon-sdk-go"
 	"github.com/kislerdm/terraform-provider-neon/internal/telemetry"
 )
@@ -19,6 +28,19 @@ func init() {
 	// Set descriptions to support markdown syntax, this will be used in document generation
 	// and the language server.
 	schema.DescriptionKind = schema.StringMarkdown
+
+	for name, r := range p.ResourcesMap {
+		withPreviewFeature(name, r)
+		withLimitDiagnostics(r)
+		withSanitizedDiagnostics(r)
+		withAPIDeprecations(name, r)
+		withStableReplacement(r)
+	}
+	for name, r := range p.DataSourcesMap {
+		withPreviewFeature(name, r)
+		withSanitizedDiagnostics(r)
+		withAPIDeprecations(name, r)
+	}
 }
 
 var p = &schema.Provider{
@@ -29,49 +51,331 @@ var p = &schema.Provider{
 			Description: "API access key. Default is read from the environment variable `NEON_API_KEY`.",
 			Default:     os.Getenv("NEON_API_KEY"),
 		},
+		"api_key_command": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Command to execute to obtain the API access key, e.g. ` + "`vault kv get -field=key secret/neon`" + `.
+The command is executed by the shell upon the provider initialisation, its output is used as the key.
+**Note** that it takes precedence over ` + "`api_key`" + `.`,
+		},
+		"correlation_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Identifier attached to every API call to trace the activity, e.g. the Terraform run ID.
+Default is read from the environment variable ` + "`NEON_CORRELATION_ID`" + `.`,
+			Default: os.Getenv("NEON_CORRELATION_ID"),
+		},
+		"correlation_id_header": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the HTTP header to attach `correlation_id` to.",
+			Default:     "X-Correlation-ID",
+		},
+		"tls_min_version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "Minimum TLS version to connect to the API with. Allowed values: `1.2`, `1.3`.",
+			Default:      "1.2",
+			ValidateFunc: validation.StringInSlice([]string{"1.2", "1.3"}, false),
+		},
+		"ca_bundle_file": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Path to the PEM-encoded CA certificates bundle to verify the API's TLS certificate with,
+e.g. when the TLS-inspecting proxy is used. The certificates are added to the system's pool.`,
+		},
+		"read_only": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+			Description: `Set to true to reject all API calls which mutate the resources.
+It's useful to run the drift detection using ` + "`terraform plan`" + ` with the guarantee that nothing changes.`,
+		},
+		"preflight": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+			Description: `Set to true to verify upon the provider's configuration that the API key is valid,
+and that it grants access to the projects, and to the organization ` + "`org_id`" + ` if it's set.
+It fails the plan early with the precise message instead of failing on the first resource's API call.
+**Note** that the write access cannot be verified without changing the resources.`,
+		},
+		"default_annotations": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Description: `Annotations set on every branch created by the provider, e.g. the owner, or the cost center.
+They are merged with the ` + "`annotations`" + ` of the resource ` + "`neon_branch`" + `, which take precedence.
+**Note** that the API supports no annotations of the projects.`,
+		},
+		"cost_estimate": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+			Description: `Set to true to estimate the monthly compute cost of the resources ` + "`neon_endpoint`" + ` and
+` + "`neon_project`" + ` upon plan given the autoscaling limits and the suspend timeout of the endpoints,
+and the monthly storage cost of the resource ` + "`neon_project`" + ` given the history retention and the quota.
+The estimate is reported as the plan's warning if the plan changes it.
+**Note** that the storage cost is not bound if the project has no quota of the logical size and the written data.`,
+		},
+		"maintenance_warning_hours": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      24,
+			ValidateFunc: intValidationNotNegative,
+			Description: `Number of hours ahead of the scheduled maintenance of the resource ` + "`neon_project`" + `
+to report the plan's warning about it. The value 0 disables the warning.`,
+		},
+		"cost_guardrails": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Description: `Limits of the compute size which fail the plan if exceeded.
+**Note** that the endpoints with the autoscaling limits unknown upon plan are not accounted.`,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"max_total_cu": {
+						Type:     schema.TypeFloat,
+						Optional: true,
+						Description: `Maximum sum of the maximum autoscaling limits of all endpoints managed by the configuration,
+including the projects' default endpoints. The value 0 means no limit.`,
+					},
+					"max_endpoint_cu": {
+						Type:        schema.TypeFloat,
+						Optional:    true,
+						Description: "Maximum autoscaling limit of every endpoint. The value 0 means no limit.",
+					},
+				},
+			},
+		},
+		"features": schemaFeatures(),
+		"mock":     schemaMock(),
+		"org_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Default organization ID of the projects created by the resource ` + "`neon_project`" + `.
+The project's attribute ` + "`org_id`" + ` takes precedence. Default is read from the environment variable ` +
+				"`NEON_ORG_ID`" + `.`,
+			Default: os.Getenv("NEON_ORG_ID"),
+		},
+		"base_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Base URL of the Neon API, e.g. ` + "`http://localhost:8080/api/v2`" + ` to run against the fake API server.
+Default is read from the environment variable ` + "`NEON_API_BASE_URL`" + `, or ` + "`" + telemetry.DefaultBaseURL + "`" + ` if not set.`,
+			Default: os.Getenv("NEON_API_BASE_URL"),
+		},
+		"console_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Description: `Base URL of the Neon console used to define the attributes ` + "`console_url`" + ` of the resources.
+Default is read from the environment variable ` + "`NEON_CONSOLE_URL`" + `, or derived from the API's host if not set,
+e.g. ` + "`" + defaultConsoleURL + "`" + ` for the base URL ` + "`" + telemetry.DefaultBaseURL + "`" + `.`,
+			Default: os.Getenv("NEON_CONSOLE_URL"),
+		},
 	},
 	ResourcesMap: map[string]*schema.Resource{
-		"neon_project":            resourceProject(),
-		"neon_branch":             resourceBranch(),
-		"neon_endpoint":           resourceEndpoint(),
-		"neon_role":               resourceRole(),
-		"neon_database":           resourceDatabase(),
-		"neon_project_permission": resourceProjectPermission(),
+		"neon_project":                  resourceProject(),
+		"neon_branch":                   resourceBranch(),
+		"neon_endpoint":                 resourceEndpoint(),
+		"neon_role":                     resourceRole(),
+		"neon_database":                 resourceDatabase(),
+		"neon_project_permission":       resourceProjectPermission(),
+		"neon_project_transfer":         resourceProjectTransfer(),
+		"neon_preview_environment":      resourcePreviewEnvironment(),
+		"neon_project_default_endpoint": resourceProjectDefaultEndpoint(),
+		"neon_branch_protection_rule":   resourceBranchProtectionRule(),
+		"neon_api_key":                  resourceAPIKey(),
 	},
 	DataSourcesMap: map[string]*schema.Resource{
-		"neon_project":              dataSourceProject(),
-		"neon_branches":             dataSourceBranches(),
-		"neon_branch_endpoints":     dataSourceBranchEndpoints(),
-		"neon_branch_roles":         dataSourceBranchRoles(),
-		"neon_branch_role_password": dataSourceBranchRolePassword(),
+		"neon_project":                   dataSourceProject(),
+		"neon_branches":                  dataSourceBranches(),
+		"neon_branch_endpoints":          dataSourceBranchEndpoints(),
+		"neon_default_endpoint":          dataSourceDefaultEndpoint(),
+		"neon_branch_roles":              dataSourceBranchRoles(),
+		"neon_branch_role_password":      dataSourceBranchRolePassword(),
+		"neon_latest_branch":             dataSourceLatestBranch(),
+		"neon_timetravel_connection_uri": dataSourceTimeTravelConnectionURI(),
+		"neon_connection_env":            dataSourceConnectionEnv(),
+		"neon_connection_matrix":         dataSourceConnectionMatrix(),
+		"neon_role":                      dataSourceRole(),
+		"neon_jwks":                      dataSourceJWKS(),
+		"neon_organization":              dataSourceOrganization(),
+		"neon_account":                   dataSourceAccount(),
+		"neon_idle_endpoints":            dataSourceIdleEndpoints(),
+		"neon_regions":                   dataSourceRegions(),
+		"neon_endpoint_health":           dataSourceEndpointHealth(),
+		"neon_operation":                 dataSourceOperation(),
 	},
 }
 
+// providerClient defines the provider's meta: the API client, and the provider's defaults.
+type providerClient struct {
+	*neon.Client
+	// orgID defines the default organization of the projects.
+	orgID string
+	// costEstimate defines if the compute and storage cost is estimated upon plan.
+	costEstimate bool
+	// costGuardrails defines the limits of the compute size which fail the plan.
+	costGuardrails costGuardrails
+	// maintenanceWarning defines how long ahead of the project's maintenance the plan warns about it.
+	maintenanceWarning time.Duration
+	// defaultAnnotations defines the annotations of the branches created by the provider.
+	defaultAnnotations map[string]interface{}
+	// features defines the preview capabilities of the API enabled in the provider's configuration.
+	features enabledFeatures
+	// plannedBranches defines the branches planned for creation to check the projects' branches limit.
+	plannedBranches plannedBranches
+	// deprecations defines the detector of the deprecations in the API responses.
+	deprecations *deprecationDetector
+	// consoleURL defines the base URL of the Neon console.
+	consoleURL string
+}
+
+// providerClientFromMeta returns the provider's client configured by the provider,
+// it fails instead of panicking if meta is of another type, e.g. in the unit tests.
+func providerClientFromMeta(meta interface{}) (*providerClient, error) {
+	c, ok := meta.(*providerClient)
+	if !ok || c == nil {
+		return nil, fmt.Errorf("unexpected type of the provider's client: %T", meta)
+	}
+	return c, nil
+}
+
+// newSDKClient initializes the API client. It's overridden in tests, e.g. to record and replay the API calls.
+var newSDKClient = neon.NewClient
+
 // New returns the provider.
 func New(version string) *schema.Provider {
 	var o = new(schema.Provider)
 	*o = *p
 	o.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (c interface{},
 		errs diag.Diagnostics) {
-		var err error
-		c, err = neon.NewClient(neon.Config{
-			Key:        d.Get("api_key").(string),
-			HTTPClient: telemetry.NewHTTPClient(Name, version, o.TerraformVersion),
+		key := d.Get("api_key").(string)
+		if v, ok := d.GetOk("api_key_command"); ok {
+			var err error
+			if key, err = apiKeyFromCommand(ctx, v.(string)); err != nil {
+				return nil, diag.FromErr(err)
+			}
+		}
+
+		httpClient := telemetry.NewHTTPClient(Name, version, o.TerraformVersion)
+		httpClient.CorrelationHeader = d.Get("correlation_id_header").(string)
+		httpClient.CorrelationID = d.Get("correlation_id").(string)
+		httpClient.ReadOnly = d.Get("read_only").(bool)
+		httpClient.BaseURL = d.Get("base_url").(string)
+
+		console, err := newConsoleURL(d.Get("console_url").(string), httpClient.BaseURL)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		tlsConfig, err := newTLSConfig(d.Get("tls_min_version").(string), d.Get("ca_bundle_file").(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		httpClient.SetTLSConfig(tlsConfig)
+
+		if srv, ok := newMockAPI(d.Get("mock").([]interface{})); ok {
+			httpClient.SetTransport(srv)
+			if key == "" {
+				key = mockAPIKey
+			}
+		}
+
+		deprecations := &deprecationDetector{HTTPClient: httpClient}
+		client, err := newSDKClient(neon.Config{
+			Key:        key,
+			HTTPClient: deprecations,
 		})
 		if err != nil {
-			errs = diag.FromErr(err)
+			return nil, diag.FromErr(err)
+		}
+
+		if d.Get("preflight").(bool) {
+			if err := preflight(client, d.Get("org_id").(string)); err != nil {
+				return nil, diag.FromErr(err)
+			}
 		}
-		return c, errs
+
+		return &providerClient{
+			Client:             client,
+			orgID:              d.Get("org_id").(string),
+			costEstimate:       d.Get("cost_estimate").(bool),
+			costGuardrails:     newCostGuardrails(d.Get("cost_guardrails").([]interface{})),
+			maintenanceWarning: time.Duration(d.Get("maintenance_warning_hours").(int)) * time.Hour,
+			defaultAnnotations: d.Get("default_annotations").(map[string]interface{}),
+			features:           newEnabledFeatures(d.Get("features").([]interface{})),
+			deprecations:       deprecations,
+			consoleURL:         console,
+		}, nil
 	}
 	return o
 }
 
+// apiKeyFromCommand executes the command using the shell and returns its output.
+func apiKeyFromCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("api_key_command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(string(out))
+	if key == "" {
+		return "", errors.New("api_key_command returned empty output")
+	}
+	return key, nil
+}
+
+const apiKeyCommandTimeout = 1 * time.Minute
+
+func newTLSConfig(minVersion, caBundleFile string) (*tls.Config, error) {
+	o := &tls.Config{MinVersion: tls.VersionTLS12}
+	if minVersion == "1.3" {
+		o.MinVersion = tls.VersionTLS13
+	}
+
+	if caBundleFile != "" {
+		pem, err := os.ReadFile(caBundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read ca_bundle_file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no valid certificates found in " + caBundleFile)
+		}
+		o.RootCAs = pool
+	}
+
+	return o, nil
+}
+
 // NewUnitTest returns the provider's factory for unit tests.
 func NewUnitTest() *schema.Provider {
 	var o = new(schema.Provider)
 	*o = *p
 	o.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		return neon.NewMockHTTPClient(), nil
+		client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: neon.NewMockHTTPClient()})
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return &providerClient{Client: client}, nil
 	}
 	return o
 }
@@ -0,0 +1,146 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func Test_apiKeyFromCommand(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands require the POSIX shell")
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "shall return the trimmed output",
+			command: "echo '  foo '",
+			want:    "foo",
+		},
+		{
+			name:    "shall fail if the command fails",
+			command: "echo bar >&2; exit 1",
+			wantErr: true,
+		},
+		{
+			name:    "shall fail if the output is empty",
+			command: "true",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apiKeyFromCommand(context.TODO(), tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("apiKeyFromCommand() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("apiKeyFromCommand() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newTLSConfig(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	caBundleFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caBundleFile, pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw},
+	), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("shall trust the server signed by the CA from the bundle", func(t *testing.T) {
+		// GIVEN
+		cfg, err := newTLSConfig("1.3", caBundleFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.MinVersion != tls.VersionTLS13 {
+			t.Errorf("unexpected TLS min version: %d", cfg.MinVersion)
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+
+		// WHEN
+		resp, err := client.Get(srv.URL)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+	})
+
+	t.Run("shall fail if the bundle is invalid", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.pem")
+		_ = os.WriteFile(invalid, []byte("foo"), 0o600)
+		if _, err := newTLSConfig("1.2", invalid); err == nil {
+			t.Errorf("error expected")
+		}
+	})
+
+	t.Run("shall fail if the bundle is missing", func(t *testing.T) {
+		if _, err := newTLSConfig("1.2", filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+			t.Errorf("error expected")
+		}
+	})
+}
+
+func Test_providerClientFromMeta(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	t.Run("shall return the diagnostics instead of panicking on the unexpected meta", func(t *testing.T) {
+		// GIVEN
+		d := dataSourceAccount().TestResourceData()
+
+		// WHEN
+		diags := dataSourceAccountRead(context.TODO(), d, httpClientStubFn(nil))
+
+		// THEN
+		if !diags.HasError() {
+			t.Errorf("the error is expected, got: %v", diags)
+		}
+	})
+
+	t.Run("shall configure the unit test provider's client", func(t *testing.T) {
+		// WHEN
+		meta, diags := NewUnitTest().ConfigureContextFunc(context.TODO(), nil)
+
+		// THEN
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if _, err := providerClientFromMeta(meta); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
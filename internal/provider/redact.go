@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const redacted = "REDACTED"
+
+var (
+	reURICredentials  = regexp.MustCompile(`(?i)(postgres(?:ql)?://[^:/@\s]+:)[^@\s]+@`)
+	reDSNPassword     = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|[^\s&]+)`)
+	reJSONPassword    = regexp.MustCompile(`(?i)("password"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	reJSONAPIKeyValue = regexp.MustCompile(`(?i)("key"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+)
+
+// redactSecrets strips the credentials from the input string, e.g. the password from the connection URI.
+func redactSecrets(s string) string {
+	s = reURICredentials.ReplaceAllString(s, "${1}"+redacted+"@")
+	s = reDSNPassword.ReplaceAllString(s, "${1}"+redacted)
+	s = reJSONPassword.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	s = reJSONAPIKeyValue.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	return s
+}
+
+func sanitizeDiagnostics(diags diag.Diagnostics) diag.Diagnostics {
+	for i := range diags {
+		diags[i].Summary = redactSecrets(diags[i].Summary)
+		diags[i].Detail = redactSecrets(diags[i].Detail)
+	}
+	return diags
+}
+
+func sanitizeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if s := redactSecrets(err.Error()); s != err.Error() {
+		return errors.New(s)
+	}
+	return err
+}
+
+// withSanitizedDiagnostics wraps the resource's callbacks to ensure that no credentials
+// leak to the CLI output, or to the logs through the error messages.
+func withSanitizedDiagnostics(r *schema.Resource) *schema.Resource {
+	type callback = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+	wrap := func(fn callback) callback {
+		if fn == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			return sanitizeDiagnostics(fn(ctx, d, meta))
+		}
+	}
+
+	r.CreateContext = wrap(r.CreateContext)
+	r.ReadContext = wrap(r.ReadContext)
+	r.UpdateContext = wrap(r.UpdateContext)
+	r.DeleteContext = wrap(r.DeleteContext)
+
+	if fn := r.CustomizeDiff; fn != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return sanitizeError(fn(ctx, d, meta))
+		}
+	}
+
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		fn := r.Importer.StateContext
+		r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) (
+			[]*schema.ResourceData, error,
+		) {
+			o, err := fn(ctx, d, meta)
+			return o, sanitizeError(err)
+		}
+	}
+
+	return r
+}
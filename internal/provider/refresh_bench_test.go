@@ -0,0 +1,202 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+)
+
+// The refresh benchmarks read the state of the large project, i.e. the project with benchProjectSize branches,
+// and one endpoint per branch, served by the fake API in-process. Besides the wall-time, the benchmarks report
+// the number of API calls per refresh of the whole state, e.g.
+//
+//	go test ./internal/provider -run '^$' -bench BenchmarkRefresh -benchmem
+//
+// The number of API calls is checked against the baselines by the benchmarks, and by TestRefreshBaseline which runs
+// with the unit tests, i.e. the refresh making more API calls fails the tests. Lower the baselines if the refresh
+// makes fewer API calls, the wall-time is not checked because it depends on the machine.
+
+const benchProjectSize = 500
+
+// The baselines of the API calls per refresh of the resource.
+const (
+	benchBaselineProjectCalls  = 5
+	benchBaselineBranchCalls   = 2
+	benchBaselineEndpointCalls = 1
+)
+
+// benchBaselineCalls defines the baseline of the API calls per refresh of the whole state.
+const benchBaselineCalls = benchBaselineProjectCalls +
+	benchProjectSize*(benchBaselineBranchCalls+benchBaselineEndpointCalls)
+
+// checkBaseline fails if the number of API calls per operation exceeds the baseline.
+func checkBaseline(tb testing.TB, calls float64, baseline int) {
+	tb.Helper()
+	if calls > float64(baseline) {
+		tb.Fatalf("%.1f API calls per refresh exceed the baseline of %d calls", calls, baseline)
+	}
+}
+
+// countingHTTPClient serves the requests by the handler, and counts the requests.
+type countingHTTPClient struct {
+	handler http.Handler
+	calls   atomic.Int64
+}
+
+func (c *countingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	w := httptest.NewRecorder()
+	c.handler.ServeHTTP(w, req)
+	return w.Result(), nil
+}
+
+type benchState struct {
+	// client defines the provider's meta, i.e. the client configured by the provider
+	client    *providerClient
+	http      *countingHTTPClient
+	projectID string
+	branches  []string
+	endpoints []string
+}
+
+func newBenchState(b testing.TB) benchState {
+	b.Helper()
+
+	srv := fakeapi.New()
+	// the operations finish instantly, i.e. the project is never locked
+	srv.OperationDuration = 0
+
+	httpClient := &countingHTTPClient{handler: srv}
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: httpClient})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	o := benchState{client: &providerClient{Client: client}, http: httpClient, projectID: project.Project.ID}
+	for i := 0; i < benchProjectSize; i++ {
+		resp, err := client.CreateProjectBranch(o.projectID, &neon.CreateProjectBranchReqObj{
+			BranchCreateRequest: neon.BranchCreateRequest{
+				Endpoints: &[]neon.BranchCreateRequestEndpointOptions{{Type: neon.EndpointTypeReadWrite}},
+			},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		o.branches = append(o.branches, resp.Branch.ID)
+		o.endpoints = append(o.endpoints, resp.Endpoints[0].ID)
+	}
+
+	return o
+}
+
+func (s benchState) resourceData(r *schema.Resource, id string) *schema.ResourceData {
+	d := r.TestResourceData()
+	d.SetId(id)
+	if _, ok := r.Schema["project_id"]; ok {
+		_ = d.Set("project_id", s.projectID)
+	}
+	return d
+}
+
+// refresh reads all resources of the state, and returns the number of API calls.
+func (s benchState) refresh(b testing.TB) int64 {
+	b.Helper()
+
+	ctx := context.Background()
+	start := s.http.calls.Load()
+
+	if diags := resourceProjectReadRetry(ctx, s.resourceData(resourceProject(), s.projectID), s.client); diags.HasError() {
+		b.Fatal(diags)
+	}
+	for _, id := range s.branches {
+		if diags := resourceBranchReadRetry(ctx, s.resourceData(resourceBranch(), id), s.client); diags.HasError() {
+			b.Fatal(diags)
+		}
+	}
+	for _, id := range s.endpoints {
+		if diags := resourceEndpointReadRetry(ctx, s.resourceData(resourceEndpoint(), id), s.client); diags.HasError() {
+			b.Fatal(diags)
+		}
+	}
+
+	return s.http.calls.Load() - start
+}
+
+func BenchmarkRefresh(b *testing.B) {
+	s := newBenchState(b)
+	b.ResetTimer()
+
+	var calls int64
+	for i := 0; i < b.N; i++ {
+		calls += s.refresh(b)
+	}
+
+	b.ReportMetric(float64(calls)/float64(b.N), "api-calls/op")
+	checkBaseline(b, float64(calls)/float64(b.N), benchBaselineCalls)
+}
+
+func BenchmarkRefreshBranch(b *testing.B) {
+	s := newBenchState(b)
+	ctx := context.Background()
+	b.ResetTimer()
+
+	start := s.http.calls.Load()
+	for i := 0; i < b.N; i++ {
+		d := s.resourceData(resourceBranch(), s.branches[i%len(s.branches)])
+		if diags := resourceBranchReadRetry(ctx, d, s.client); diags.HasError() {
+			b.Fatal(diags)
+		}
+	}
+
+	calls := float64(s.http.calls.Load()-start) / float64(b.N)
+	b.ReportMetric(calls, "api-calls/op")
+	checkBaseline(b, calls, benchBaselineBranchCalls)
+}
+
+func BenchmarkRefreshEndpoint(b *testing.B) {
+	s := newBenchState(b)
+	ctx := context.Background()
+	b.ResetTimer()
+
+	start := s.http.calls.Load()
+	for i := 0; i < b.N; i++ {
+		d := s.resourceData(resourceEndpoint(), s.endpoints[i%len(s.endpoints)])
+		if diags := resourceEndpointReadRetry(ctx, d, s.client); diags.HasError() {
+			b.Fatal(diags)
+		}
+	}
+
+	calls := float64(s.http.calls.Load()-start) / float64(b.N)
+	b.ReportMetric(calls, "api-calls/op")
+	checkBaseline(b, calls, benchBaselineEndpointCalls)
+}
+
+func TestRefreshBaseline(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	s := newBenchState(t)
+
+	// WHEN
+	calls := s.refresh(t)
+
+	// THEN
+	checkBaseline(t, float64(calls), benchBaselineCalls)
+}
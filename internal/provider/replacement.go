@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withStableReplacement wraps the resource's CustomizeDiff to ensure that only the attributes set in the
+// configuration force the replacement of the resource. The change of the computed value of the attribute
+// which is not set in the configuration, e.g. the value normalised, or recomputed by the API, is discarded.
+func withStableReplacement(r *schema.Resource) *schema.Resource {
+	var keys []string
+	for k, v := range r.Schema {
+		if v.ForceNew && v.Computed {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return r
+	}
+	sort.Strings(keys)
+
+	fn := r.CustomizeDiff
+	r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if fn != nil {
+			if err := fn(ctx, d, meta); err != nil {
+				return err
+			}
+		}
+		return clearComputedReplacements(ctx, d, keys)
+	}
+	return r
+}
+
+func clearComputedReplacements(ctx context.Context, d *schema.ResourceDiff, keys []string) error {
+	// nothing is replaced upon create
+	if d.Id() == "" {
+		return nil
+	}
+
+	cfg := d.GetRawConfig()
+	for _, k := range keys {
+		if !d.HasChange(k) || !isNullInRawConfig(cfg, k) {
+			continue
+		}
+		tflog.Debug(ctx, "discard the change of the computed attribute which forces replacement", map[string]interface{}{
+			"attribute": k,
+		})
+		// the prior value is restored rather than the diff cleared, because the values set by
+		// the resource's CustomizeDiff are diffed again after the callback
+		old, _ := d.GetChange(k)
+		if err := d.SetNew(k, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isNullInRawConfig returns true if the attribute is not set in the configuration. Unlike isDefinedInRawConfig,
+// the value unknown upon plan is treated as set, e.g. the ID of the resource which is replaced.
+func isNullInRawConfig(v cty.Value, key string) bool {
+	for _, k := range strings.Split(key, ".") {
+		if !v.IsKnown() {
+			return false
+		}
+		if v.IsNull() {
+			return true
+		}
+
+		switch t := v.Type(); {
+		case t.IsObjectType():
+			if !t.HasAttribute(k) {
+				return true
+			}
+			v = v.GetAttr(k)
+		case t.IsListType() || t.IsTupleType():
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= v.LengthInt() {
+				return true
+			}
+			v = v.AsValueSlice()[i]
+		default:
+			return true
+		}
+	}
+	return v.IsKnown() && v.IsNull()
+}
+
+// replacementDiagnostic returns the plan warning which lists the attributes forcing the replacement
+// of the existing resource. It returns nil if the resource is not replaced.
+func replacementDiagnostic(typeName string, prior tftypes.Value, paths []*tftypes.AttributePath) *tfprotov5.Diagnostic {
+	if prior.IsNull() {
+		return nil
+	}
+
+	var attrs []string
+	for _, p := range paths {
+		// the SDK marks the id whenever the resource is replaced
+		if s := attributePathString(p); s != "" && s != "id" {
+			attrs = append(attrs, s)
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	sort.Strings(attrs)
+
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Replacement of " + typeName,
+		Detail: "The resource is destroyed and re-created because of the change of the attribute(s): " +
+			strings.Join(attrs, ", ") + ".",
+	}
+}
+
+// attributePathString formats the path in the configuration's notation, e.g. branch.0.name.
+func attributePathString(p *tftypes.AttributePath) string {
+	var o []string
+	for _, step := range p.Steps() {
+		switch v := step.(type) {
+		case tftypes.AttributeName:
+			o = append(o, string(v))
+		case tftypes.ElementKeyInt:
+			o = append(o, strconv.FormatInt(int64(v), 10))
+		case tftypes.ElementKeyString:
+			o = append(o, string(v))
+		}
+	}
+	return strings.Join(o, ".")
+}
@@ -0,0 +1,169 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_withStableReplacement(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// the configured value is unknown upon plan, e.g. the attribute of the resource which is replaced
+	const unknownRegionID = "(known after apply)"
+
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+				"region_id": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+			},
+			// the region is recomputed, e.g. it's normalised by the API
+			CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+				if d.Id() != "" && d.Get("region_id").(string) == "aws-us-east-2" {
+					return d.SetNew("region_id", "aws-us-east-1")
+				}
+				return nil
+			},
+		}
+	}
+
+	plan := func(t *testing.T, r *schema.Resource, name, configRegionID string) *tfprotov5.PlanResourceChangeResponse {
+		t.Helper()
+
+		p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"neon_test": r}}
+		srv := schema.NewGRPCProviderServer(p)
+		ctx := context.TODO()
+
+		s, err := srv.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		typ := s.ResourceSchemas["neon_test"].ValueType()
+
+		value := func(id, name, regionID interface{}) *tfprotov5.DynamicValue {
+			v, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, map[string]tftypes.Value{
+				"id":        tftypes.NewValue(tftypes.String, id),
+				"name":      tftypes.NewValue(tftypes.String, name),
+				"region_id": tftypes.NewValue(tftypes.String, regionID),
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return &v
+		}
+
+		var cfgRegionID interface{}
+		proposedRegionID := interface{}("aws-us-east-2")
+		switch configRegionID {
+		case "":
+		case unknownRegionID:
+			cfgRegionID, proposedRegionID = tftypes.UnknownValue, tftypes.UnknownValue
+		default:
+			cfgRegionID, proposedRegionID = configRegionID, configRegionID
+		}
+
+		resp, err := srv.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+			TypeName:         "neon_test",
+			PriorState:       value("foo", "foo", "aws-us-east-2"),
+			ProposedNewState: value("foo", name, proposedRegionID),
+			Config:           value(nil, name, cfgRegionID),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics[0])
+		}
+		return resp
+	}
+
+	t.Run("shall replace the resource because of the computed value without the guard", func(t *testing.T) {
+		resp := plan(t, newResource(), "foo", "")
+		assert.NotEmpty(t, resp.RequiresReplace)
+	})
+
+	t.Run("shall not replace the resource because of the computed value", func(t *testing.T) {
+		resp := plan(t, withStableReplacement(newResource()), "foo", "")
+		assert.Empty(t, resp.RequiresReplace)
+	})
+
+	t.Run("shall replace the resource because of the value set in the configuration", func(t *testing.T) {
+		resp := plan(t, withStableReplacement(newResource()), "foo", "aws-eu-central-1")
+		assert.Equal(t, []string{"id", "region_id"}, replacedAttributes(resp.RequiresReplace))
+	})
+
+	t.Run("shall replace the resource because of the value unknown upon plan", func(t *testing.T) {
+		resp := plan(t, withStableReplacement(newResource()), "foo", unknownRegionID)
+		assert.Equal(t, []string{"id", "region_id"}, replacedAttributes(resp.RequiresReplace))
+	})
+
+	t.Run("shall replace the resource because of the required attribute", func(t *testing.T) {
+		resp := plan(t, withStableReplacement(newResource()), "bar", "")
+		assert.Equal(t, []string{"id", "name"}, replacedAttributes(resp.RequiresReplace))
+	})
+}
+
+func replacedAttributes(paths []*tftypes.AttributePath) []string {
+	var o []string
+	for _, p := range paths {
+		o = append(o, attributePathString(p))
+	}
+	sort.Strings(o)
+	return o
+}
+
+func Test_replacementDiagnostic(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}}
+	prior := tftypes.NewValue(typ, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "foo")})
+	paths := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("id"),
+		tftypes.NewAttributePath().WithAttributeName("region_id"),
+		tftypes.NewAttributePath().WithAttributeName("branch").WithElementKeyInt(0).WithAttributeName("name"),
+	}
+
+	t.Run("shall explain the replacement", func(t *testing.T) {
+		got := replacementDiagnostic("neon_project", prior, paths)
+		if assert.NotNil(t, got) {
+			assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, got.Severity)
+			assert.Equal(t, "Replacement of neon_project", got.Summary)
+			assert.Equal(t,
+				"The resource is destroyed and re-created because of the change of the attribute(s): "+
+					"branch.0.name, region_id.",
+				got.Detail,
+			)
+		}
+	})
+
+	t.Run("shall return nil upon create", func(t *testing.T) {
+		assert.Nil(t, replacementDiagnostic("neon_project", tftypes.NewValue(typ, nil), paths))
+	})
+
+	t.Run("shall return nil if only the id is marked", func(t *testing.T) {
+		assert.Nil(t, replacementDiagnostic("neon_project", prior, paths[:1]))
+	})
+}
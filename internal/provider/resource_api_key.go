@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func resourceAPIKey() *schema.Resource {
+	return &schema.Resource{
+		Description: `Neon API key. See details: https://neon.tech/docs/manage/api-keys
+
+The key can be rotated without replacement by changing the attribute ` + "`rotation_triggered_by`" + `:
+the new key is created and stored to the state first, the old key is revoked afterwards.
+Hence, there is no time window when no valid key exists. If the old key cannot be revoked, its ID is kept in
+the attribute ` + "`pending_revocation_ids`" + `, and the revocation is retried upon the next apply.`,
+		SchemaVersion: 1,
+		CreateContext: resourceAPIKeyCreateRetry,
+		ReadContext:   resourceAPIKeyReadRetry,
+		UpdateContext: resourceAPIKeyRotate,
+		DeleteContext: resourceAPIKeyDeleteRetry,
+		CustomizeDiff: resourceAPIKeyCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "API key ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "API key name.",
+			},
+			"rotation_triggered_by": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: `Arbitrary map of values which triggers the key rotation when changed.
+For example, it can be used with the resource ` + "`time_rotating`" + ` to rotate the key on schedule.`,
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "API key token.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the key creation.",
+			},
+			"pending_revocation_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the rotated keys which failed to be revoked. The revocation is retried upon apply.",
+			},
+		},
+	}
+}
+
+// resourceAPIKeyCustomizeDiff plans the new key upon rotation, i.e. the dependents are planned with the key
+// known after apply instead of the key which is revoked by the rotation.
+// It also plans the update if the revocation of the rotated keys is pending.
+func resourceAPIKeyCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if v, _ := d.Get("pending_revocation_ids").([]interface{}); len(v) > 0 {
+		if err := d.SetNewComputed("pending_revocation_ids"); err != nil {
+			return err
+		}
+	}
+	if !d.HasChange("rotation_triggered_by") {
+		return nil
+	}
+	for _, k := range []string{"id", "key", "created_at"} {
+		if err := d.SetNewComputed(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceAPIKeyCreateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return projectReadiness.Retry(resourceAPIKeyCreate, ctx, d, meta)
+}
+
+func resourceAPIKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "create API key")
+
+	resp, err := meta.(sdkAPIKey).CreateApiKey(
+		neon.ApiKeyCreateRequest{KeyName: d.Get("name").(string)},
+	)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(resp.ID, 10))
+	if err := d.Set("key", resp.Key); err != nil {
+		return err
+	}
+	if _, ok := d.GetOk("pending_revocation_ids"); !ok {
+		if err := d.Set("pending_revocation_ids", []string{}); err != nil {
+			return err
+		}
+	}
+	return d.Set("created_at", resp.CreatedAt.Format(time.RFC3339))
+}
+
+func resourceAPIKeyReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return projectReadiness.Retry(resourceAPIKeyRead, ctx, d, meta)
+}
+
+func resourceAPIKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "read API key")
+
+	resp, err := meta.(sdkAPIKey).ListApiKeys()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp {
+		if strconv.FormatInt(v.ID, 10) == d.Id() {
+			if err := d.Set("name", v.Name); err != nil {
+				return err
+			}
+			return d.Set("created_at", v.CreatedAt.Format(time.RFC3339))
+		}
+	}
+
+	tflog.Warn(ctx, "API key "+d.Id()+" not found, it will be removed from the state")
+	d.SetId("")
+	return nil
+}
+
+// resourceAPIKeyRotate creates the new key and revokes the old key afterwards.
+// The steps are retried independently to avoid creation of redundant keys.
+func resourceAPIKeyRotate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "rotate API key")
+
+	// the pending revocations are planned as unknown, hence they are read from the state
+	v, _ := d.GetChange("pending_revocation_ids")
+	var pending []string
+	for _, id := range v.([]interface{}) {
+		pending = append(pending, id.(string))
+	}
+
+	if d.HasChange("rotation_triggered_by") {
+		oldID := d.Id()
+		if diags := resourceAPIKeyCreateRetry(ctx, d, meta); diags.HasError() {
+			return diags
+		}
+		pending = append(pending, oldID)
+	}
+
+	return revokePendingAPIKeys(ctx, d, meta, pending)
+}
+
+// revokePendingAPIKeys revokes the rotated keys. The keys which fail to be revoked are kept in the state
+// to retry the revocation upon the next apply, i.e. the rotation doesn't fail because the new key is created.
+func revokePendingAPIKeys(
+	ctx context.Context, d *schema.ResourceData, meta interface{}, ids []string,
+) diag.Diagnostics {
+	var (
+		diags   diag.Diagnostics
+		pending = []string{}
+	)
+	for _, id := range ids {
+		v := projectReadiness.Retry(
+			func(ctx context.Context, _ *schema.ResourceData, meta interface{}) error {
+				// the key is revoked already
+				if err := revokeAPIKey(ctx, meta.(sdkAPIKey), id); err != nil && !isNotFound(err) {
+					return err
+				}
+				return nil
+			}, ctx, d, meta,
+		)
+		if !v.HasError() {
+			diags = append(diags, v...)
+			continue
+		}
+
+		pending = append(pending, id)
+		for _, e := range v {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "the API key " + id + " is not revoked, the revocation is retried upon the next apply",
+				Detail:   e.Summary,
+			})
+		}
+	}
+
+	if err := d.Set("pending_revocation_ids", pending); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+func resourceAPIKeyDeleteRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return projectReadiness.Retry(resourceAPIKeyDelete, ctx, d, meta)
+}
+
+func resourceAPIKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "delete API key")
+
+	for _, id := range d.Get("pending_revocation_ids").([]interface{}) {
+		if err := revokeAPIKey(ctx, meta.(sdkAPIKey), id.(string)); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	if err := revokeAPIKey(ctx, meta.(sdkAPIKey), d.Id()); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func revokeAPIKey(ctx context.Context, client sdkAPIKey, id string) error {
+	keyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	tflog.Debug(ctx, "revoke API key "+id)
+	_, err = client.RevokeApiKey(keyID)
+	return err
+}
+
+type sdkAPIKey interface {
+	CreateApiKey(neon.ApiKeyCreateRequest) (neon.ApiKeyCreateResponse, error)
+	ListApiKeys() ([]neon.ApiKeysListResponseItem, error)
+	RevokeApiKey(int64) (neon.ApiKeyRevokeResponse, error)
+}
@@ -0,0 +1,197 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_resourceAPIKeyRotate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	var calls []string
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			switch r.Method {
+			case http.MethodPost:
+				return newHTTPResponse(http.StatusOK,
+					`{"id":2,"key":"new-key","name":"bar","created_at":"2024-01-01T00:00:00Z"}`), nil
+			case http.MethodDelete:
+				return newHTTPResponse(http.StatusOK, `{"id":1,"name":"bar","revoked":true}`), nil
+			}
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return nil, nil
+		}),
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceAPIKey().Schema, map[string]interface{}{
+		"name":                  "bar",
+		"rotation_triggered_by": map[string]interface{}{"foo": "v2"},
+	})
+	d.SetId("1")
+
+	// WHEN
+	diags := resourceAPIKeyRotate(context.TODO(), d, client)
+
+	// THEN
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "2" {
+		t.Errorf("unexpected key ID: %s", d.Id())
+	}
+	if v := d.Get("key").(string); v != "new-key" {
+		t.Errorf("unexpected key: %s", v)
+	}
+	want := []string{"POST /api/v2/api_keys", "DELETE /api/v2/api_keys/1"}
+	if len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] {
+		t.Errorf("unexpected API calls. want: %v, got: %v", want, calls)
+	}
+}
+
+func Test_resourceAPIKeyCustomizeDiff(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                        "1",
+			"name":                      "bar",
+			"rotation_triggered_by.%":   "1",
+			"rotation_triggered_by.foo": "v1",
+			"key":                       "old-key",
+			"created_at":                "2024-01-01T00:00:00Z",
+			"pending_revocation_ids.#":  "0",
+		},
+	}
+
+	diff := func(t *testing.T, trigger string) *terraform.InstanceDiff {
+		t.Helper()
+
+		o, err := resourceAPIKey().Diff(context.TODO(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                  "bar",
+			"rotation_triggered_by": map[string]interface{}{"foo": trigger},
+		}), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	t.Run("shall plan the new key upon rotation", func(t *testing.T) {
+		got := diff(t, "v2")
+		if got == nil {
+			t.Fatal("the diff is expected")
+		}
+		if got.RequiresNew() {
+			t.Error("the key shall be rotated without replacement")
+		}
+		for _, k := range []string{"id", "key", "created_at"} {
+			if v, ok := got.Attributes[k]; !ok || !v.NewComputed {
+				t.Errorf("%s shall be known after apply, got: %v", k, v)
+			}
+		}
+	})
+
+	t.Run("shall keep the key without rotation", func(t *testing.T) {
+		if got := diff(t, "v1"); got != nil && len(got.Attributes) > 0 {
+			t.Errorf("no diff is expected, got: %v", got.Attributes)
+		}
+	})
+}
+
+func Test_resourceAPIKeyRotate_revocationFailure(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the API which fails to revoke the key
+	revocationFails := true
+	var revoked []string
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			switch r.Method {
+			case http.MethodPost:
+				return newHTTPResponse(http.StatusOK,
+					`{"id":2,"key":"new-key","name":"bar","created_at":"2024-01-01T00:00:00Z"}`), nil
+			case http.MethodDelete:
+				if revocationFails {
+					return newHTTPResponse(http.StatusBadRequest, `{"message":"revocation failed"}`), nil
+				}
+				revoked = append(revoked, r.URL.Path)
+				return newHTTPResponse(http.StatusOK, `{"id":1,"name":"bar","revoked":true}`), nil
+			}
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return nil, nil
+		}),
+	})
+
+	d := schema.TestResourceDataRaw(t, resourceAPIKey().Schema, map[string]interface{}{
+		"name":                  "bar",
+		"rotation_triggered_by": map[string]interface{}{"foo": "v2"},
+	})
+	d.SetId("1")
+
+	// WHEN the key is rotated
+	diags := resourceAPIKeyRotate(context.TODO(), d, client)
+
+	// THEN the new key is stored, and the old key is pending revocation
+	if diags.HasError() {
+		t.Fatalf("the rotation shall not fail, got: %v", diags)
+	}
+	if len(diags) == 0 {
+		t.Error("the warning is expected")
+	}
+	if d.Id() != "2" {
+		t.Errorf("unexpected key ID: %s", d.Id())
+	}
+	if got := d.Get("pending_revocation_ids").([]interface{}); len(got) != 1 || got[0] != "1" {
+		t.Fatalf("unexpected pending revocations: %v", got)
+	}
+
+	// WHEN the revocation is retried upon the next apply
+	state := d.State()
+	plan, err := resourceAPIKey().Diff(context.TODO(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                  "bar",
+		"rotation_triggered_by": map[string]interface{}{"foo": "v2"},
+	}), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan == nil || plan.Attributes["pending_revocation_ids.#"] == nil {
+		t.Fatalf("the update shall be planned to retry the revocation, got: %v", plan)
+	}
+	d = resourceAPIKey().Data(state)
+	revocationFails = false
+	diags = resourceAPIKeyRotate(context.TODO(), d, client)
+
+	// THEN the old key is revoked
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(revoked) != 1 || revoked[0] != "/api/v2/api_keys/1" {
+		t.Errorf("unexpected revocations: %v", revoked)
+	}
+	if got := d.Get("pending_revocation_ids").([]interface{}); len(got) != 0 {
+		t.Errorf("no pending revocation is expected, got: %v", got)
+	}
+	if d.Id() != "2" {
+		t.Errorf("the key shall not be rotated, got: %s", d.Id())
+	}
+}
@@ -3,12 +3,16 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	neon "github.com/kislerdm/neon-sdk-go"
 	"github.com/kislerdm/terraform-provider-neon/internal/types"
 )
@@ -24,7 +28,8 @@ func resourceBranch() *schema.Resource {
 		ReadContext:   resourceBranchReadRetry,
 		UpdateContext: resourceBranchUpdateRetry,
 		DeleteContext: resourceBranchDeleteRetry,
-		Schema: map[string]*schema.Schema{
+		CustomizeDiff: resourceBranchCustomizeDiff,
+		Schema: withConsumptionMetrics(map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -36,10 +41,12 @@ func resourceBranch() *schema.Resource {
 				Description: "Branch ID.",
 			},
 			"name": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Branch name.",
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				Description: `Branch name.
+**Note** that the branch is identified by its ID, hence the rename done outside terraform is detected as the diff
+of the name, which is restored upon the next apply if the name is defined in the configuration.`,
 			},
 			"parent_id": {
 				Type:        schema.TypeString,
@@ -47,6 +54,18 @@ func resourceBranch() *schema.Resource {
 				Computed:    true,
 				Description: "ID of the branch to check out.",
 			},
+			"parent_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"parent_id"},
+				Description: `Name of the branch to check out. It's resolved to the branch ID upon creation.
+The name is read from the parent branch if not set, e.g. to output the parent of the branch checked out by ID.
+**Note** that it's the alternative to ` + "`parent_id`" + `. The branch is not replaced if its parent is renamed
+outside terraform, because the parent is tracked by ID. The branch is replaced if the name is changed to the name of
+another branch.`,
+			},
 			"parent_lsn": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -71,14 +90,95 @@ See details: https://neon.tech/docs/reference/glossary/#lsn`,
 				Computed:    true,
 				Description: "Branch logical size in MB.",
 			},
+			"console_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Link to the branch's page in the Neon console.",
+			},
 			"protected": types.NewOptionalTristateBool(
 				`Set whether the branch is protected.`, false,
 			),
+			"skip_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to keep the branch and its data in Neon upon destroy.
+The branch will only be removed from the Terraform state.`,
+			},
+			"rotation": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"daily", "weekly", "monthly"}, false),
+				Description: `Period to refresh the branch's data from its parent, i.e. "daily", "weekly", or "monthly".
+The branch is reset from the parent branch upon the first apply after the period rolls over, e.g. on Monday
+if the rotation is weekly. The periods start at midnight UTC.
+**Note** that the branch's data is overwritten by the reset.`,
+			},
+			"last_reset_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `Timestamp when the branch's data was checked out from its parent last time,
+i.e. the time of the last reset, or the time of the branch creation.`,
+			},
+			"delete_dependents": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to delete the branch's endpoints before the branch upon destroy.
+The databases and roles of the branch are deleted together with the branch, they are reported in the logs.
+The destroy fails listing the child branches if any, because they must be deleted first.`,
+			},
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to adopt the existing branch with the same name to the state upon create
+instead of creating the new branch, e.g. to converge after the interrupted apply. The attributes of the adopted
+branch which differ from the configuration are reconciled by the next apply. The branch is not adopted, and the
+create fails if its parent differs from the configured ` + "`parent_id`" + `, or ` + "`parent_name`" + `,
+` + "`parent_lsn`" + `, or ` + "`parent_timestamp`" + `, because such branch would be replaced.
+**Note** that the adopted branch keeps its existing annotations, i.e. the ` + "`annotations`" + ` are not applied
+to it, because the API sets them upon create only. The ` + "`annotations_all`" + ` reflect the existing annotations.`,
+			},
+			"annotations": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: `Branch's annotations, e.g. the owner, or the cost center. The annotations are merged with the
+provider's ` + "`default_annotations`" + `, the values set here take precedence.
+**Note** that the annotations are set upon create only, their change forces the branch replacement.`,
+			},
+			"annotations_all": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Branch's annotations including the provider's `default_annotations`.",
+			},
+			"logical_size_timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: intValidationNotNegative,
+				Description: `Maximum duration in seconds to wait upon create for the branch's ` + "`logical_size`" + `
+to be computed, because it's not defined right after the branch creation. The branch is stored to the state
+regardless of its size when the timeout elapses. The value 0 means no wait.`,
+			},
+			"check_branches_limit": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to verify at plan time that the project's branches limit is not reached yet.
+The branches of the project planned for creation by the same plan are accounted together, e.g. the branches created
+with ` + "`count`" + `. The plan will fail listing the oldest branches which can be deleted otherwise.`,
+			},
 		},
+			"active_time_seconds", "compute_time_seconds", "written_data_bytes", "data_transfer_bytes",
+		),
 	}
 }
 
-func updateStateBranch(d *schema.ResourceData, v neon.Branch) error {
+func updateStateBranch(d *schema.ResourceData, v neon.Branch, console string) error {
 	if err := d.Set("name", v.Name); err != nil {
 		return err
 	}
@@ -98,20 +198,285 @@ func updateStateBranch(d *schema.ResourceData, v neon.Branch) error {
 			return err
 		}
 	}
+	if err := d.Set("console_url", consoleURL(console, "projects", v.ProjectID, "branches", v.ID)); err != nil {
+		return err
+	}
+	if err := d.Set("last_reset_at", branchLastResetAt(v)); err != nil {
+		return err
+	}
 	if _, ok := d.GetOk("protected"); ok || v.Protected {
 		if err := types.SetTristateBool(d, "protected", &v.Protected); err != nil {
 			return err
 		}
 	}
+	return setConsumptionMetrics(d, branchConsumptionMetrics(v))
+}
+
+func branchLastResetAt(v neon.Branch) string {
+	t := v.CreatedAt
+	if v.LastResetAt != nil {
+		t = *v.LastResetAt
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func branchConsumptionMetrics(v neon.Branch) map[string]int64 {
+	return map[string]int64{
+		"active_time_seconds":  v.ActiveTimeSeconds,
+		"compute_time_seconds": v.ComputeTimeSeconds,
+		"written_data_bytes":   v.WrittenDataBytes,
+		"data_transfer_bytes":  v.DataTransferBytes,
+	}
+}
+
+func resourceBranchCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffBranchParentTimestamp(ctx, d, meta); err != nil {
+		return err
+	}
+	if err := customizeDiffBranchRotation(ctx, d, meta); err != nil {
+		return err
+	}
+	if err := customizeDiffBranchParentName(ctx, d, meta); err != nil {
+		return err
+	}
+	return customizeDiffBranchesLimit(ctx, d, meta)
+}
+
+// customizeDiffBranchRotation plans the branch reset if the rotation period rolled over since the last reset.
+func customizeDiffBranchRotation(ctx context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	rotation, _ := d.Get("rotation").(string)
+	if rotation == "" {
+		return nil
+	}
+
+	if d.NewValueKnown("parent_id") && d.Get("parent_id").(string) == "" && d.Get("parent_name").(string) == "" {
+		return errors.New("rotation requires the parent branch, the root branch cannot be reset")
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if !branchRotationDue(rotation, d.Get("last_reset_at").(string), time.Now()) {
+		return nil
+	}
+
+	tflog.Debug(ctx, "the branch rotation is due", map[string]interface{}{
+		"branchID": d.Id(), "rotation": rotation, "lastResetAt": d.Get("last_reset_at"),
+	})
+	return d.SetNewComputed("last_reset_at")
+}
+
+// branchRotationDue defines if the branch last reset at the timestamp lastResetAt must be reset at the time now.
+func branchRotationDue(rotation, lastResetAt string, now time.Time) bool {
+	last, err := time.Parse(time.RFC3339, lastResetAt)
+	if err != nil {
+		return false
+	}
+	return last.Before(rotationPeriodStart(rotation, now))
+}
+
+// rotationPeriodStart returns the beginning of the rotation period which includes the time t.
+func rotationPeriodStart(rotation string, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch rotation {
+	case "weekly":
+		// the week starts on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "monthly":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// customizeDiffBranchParentName discards the change of the parent's name unless the name is resolved to another
+// branch than the current parent, e.g. if the parent was renamed outside terraform, because the parent is tracked
+// by its ID.
+func customizeDiffBranchParentName(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("parent_name") || !d.NewValueKnown("parent_name") {
+		return nil
+	}
+
+	old, name := d.GetChange("parent_name")
+	if name.(string) == "" {
+		return nil
+	}
+
+	parent, ok, err := findBranchByName(meta.(sdkBranch), d.Get("project_id").(string), name.(string))
+	if err != nil {
+		return err
+	}
+	if ok && parent.ID != d.Get("parent_id").(string) {
+		return nil
+	}
+
+	tflog.Debug(ctx, "the parent branch was renamed", map[string]interface{}{
+		"branchID": d.Id(), "parentID": d.Get("parent_id"), "from": name, "to": old,
+	})
+	return d.SetNew("parent_name", old)
+}
+
+func customizeDiffBranchParentTimestamp(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("parent_timestamp") || !d.NewValueKnown("parent_timestamp") || !d.NewValueKnown("project_id") {
+		return nil
+	}
+
+	v, ok := d.Get("parent_timestamp").(int)
+	if !ok || v <= 0 {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	retention, err := projectHistoryRetention(meta.(sdkBranch), projectID)
+	if err != nil {
+		return err
+	}
+
+	tflog.Debug(ctx, "validate parent_timestamp", map[string]interface{}{
+		"projectID": projectID, "historyRetention": retention.String(),
+	})
+	return validateParentTimestamp(time.Unix(int64(v), 0), time.Now(), retention)
+}
+
+// validateParentTimestamp fails if the timestamp is in the future, or beyond the project's history retention window.
+func validateParentTimestamp(ts, now time.Time, retention time.Duration) error {
+	if ts.After(now) {
+		return errors.New("parent_timestamp " + ts.UTC().Format(time.RFC3339) + " is in the future")
+	}
+	if oldest := now.Add(-retention); ts.Before(oldest) {
+		return errors.New(
+			"parent_timestamp " + ts.UTC().Format(time.RFC3339) + " is beyond the project's history retention window, " +
+				"the oldest permitted timestamp is " + oldest.UTC().Format(time.RFC3339),
+		)
+	}
 	return nil
 }
 
+// projectHistoryRetentionCache caches the projects' history retention to avoid redundant API calls upon plan.
+var projectHistoryRetentionCache sync.Map
+
+func projectHistoryRetention(client sdkBranch, projectID string) (time.Duration, error) {
+	if v, ok := projectHistoryRetentionCache.Load(projectID); ok {
+		return v.(time.Duration), nil
+	}
+
+	resp, err := client.GetProject(projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	o := time.Duration(resp.Project.HistoryRetentionSeconds) * time.Second
+	projectHistoryRetentionCache.Store(projectID, o)
+	return o, nil
+}
+
+func customizeDiffBranchesLimit(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.Get("check_branches_limit").(bool) || !d.NewValueKnown("project_id") {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	tflog.Debug(ctx, "check branches limit", map[string]interface{}{"projectID": projectID})
+
+	client := meta.(sdkBranch)
+	project, err := client.GetProject(projectID)
+	if err != nil {
+		return err
+	}
+
+	if project.Project.Owner == nil || project.Project.Owner.BranchesLimit == 0 {
+		tflog.Debug(ctx, "no branches limit defined", map[string]interface{}{"projectID": projectID})
+		return nil
+	}
+
+	resp, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return err
+	}
+
+	planned := 1
+	if c, ok := meta.(*providerClient); ok {
+		planned = c.plannedBranches.add(projectID)
+	}
+	return checkBranchesLimit(projectID, project.Project.Owner.BranchesLimit, resp.Branches, planned)
+}
+
+// plannedBranches accumulates the number of branches planned for creation by the provider's instance per project,
+// i.e. by single plan of the configuration, to check the branches limit against all branches of the plan.
+type plannedBranches struct {
+	mu        sync.Mutex
+	byProject map[string]int
+}
+
+// add accounts the new branch of the project, and returns the number of the project's planned branches.
+func (p *plannedBranches) add(projectID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.byProject == nil {
+		p.byProject = map[string]int{}
+	}
+	p.byProject[projectID]++
+	return p.byProject[projectID]
+}
+
+// checkBranchesLimit returns error listing the oldest branches which can be deleted
+// if the planned branches cannot be created in the project.
+func checkBranchesLimit(projectID string, limit int, branches []neon.Branch, planned int) error {
+	if len(branches)+planned <= limit {
+		return nil
+	}
+	if planned == 1 {
+		return errors.New(fmt.Sprintf("the project %s reached the limit of %d branches", projectID, limit) +
+			deletableBranches(branches))
+	}
+	return errors.New(fmt.Sprintf(
+		"the project %s has %d branches, the plan creates %d branches which exceed the limit of %d branches",
+		projectID, len(branches), planned, limit,
+	) + deletableBranches(branches))
+}
+
+// deletableBranches lists the oldest branches which are neither default, nor protected,
+// or returns the empty string if there are no such branches.
+func deletableBranches(branches []neon.Branch) string {
+	const maxCandidates = 5
+
+	var candidates []neon.Branch
+	for _, br := range branches {
+		if !br.Default && !br.Protected {
+			candidates = append(candidates, br)
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b neon.Branch) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+	o := ", the oldest branches which can be deleted:"
+	for _, br := range candidates {
+		o += fmt.Sprintf("\n- %s (%s), created at %s", br.Name, br.ID, br.CreatedAt.Format(time.RFC3339))
+	}
+	return o
+}
+
 func resourceBranchCreateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return projectReadiness.Retry(resourceBranchCreate, ctx, d, meta)
 }
 
 func resourceBranchReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceBranchRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceBranchRead, ctx, d, meta)
 }
 
 func resourceBranchUpdateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -137,12 +502,50 @@ func resourceBranchCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		},
 	}
 
-	if v, ok := d.GetOk("parent_timestamp"); ok && v.(int) > 0 {
-		t := time.Unix(int64(v.(int)), 0)
+	if v, ok := d.GetOk("parent_name"); ok {
+		parentID, err := findBranchIDByName(meta.(sdkBranch), d.Get("project_id").(string), v.(string))
+		if err != nil {
+			return err
+		}
+		cfg.Branch.ParentID = pointer(parentID)
+	}
+
+	if isDefinedInConfig(d, "parent_timestamp") {
+		t := time.Unix(int64(d.Get("parent_timestamp").(int)), 0)
 		cfg.Branch.ParentTimestamp = &t
 	}
 
-	resp, err := meta.(*neon.Client).CreateProjectBranch(
+	annotations := mergeAnnotations(meta, d.Get("annotations").(map[string]interface{}))
+	if len(annotations) > 0 {
+		cfg.AnnotationValue = &annotations
+	}
+
+	if name := d.Get("name").(string); name != "" && d.Get("adopt_existing").(bool) {
+		branch, ok, err := findBranchByName(meta.(sdkBranch), d.Get("project_id").(string), name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			var parentID string
+			if cfg.Branch.ParentID != nil {
+				parentID = *cfg.Branch.ParentID
+			}
+			if err := checkAdoptedBranchParent(d, branch, parentID); err != nil {
+				return err
+			}
+			tflog.Info(ctx, "adopt existing Branch", map[string]interface{}{"branchID": branch.ID, "name": name})
+			d.SetId(branch.ID)
+			if err := updateStateBranch(d, branch, consoleBaseURL(meta)); err != nil {
+				return err
+			}
+			if err := setBranchParentName(d, meta.(sdkBranch), branch); err != nil {
+				return err
+			}
+			return adoptBranchAnnotations(ctx, d, meta.(sdkBranch), annotations)
+		}
+	}
+
+	resp, err := meta.(sdkBranch).CreateProjectBranch(
 		d.Get("project_id").(string),
 		&cfg,
 	)
@@ -151,16 +554,31 @@ func resourceBranchCreate(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	d.SetId(resp.BranchResponse.Branch.ID)
-	if err := updateStateBranch(d, resp.BranchResponse.Branch); err != nil {
+
+	branch, err := waitBranchLogicalSize(
+		ctx, meta.(sdkBranch), resp.BranchResponse.Branch,
+		time.Duration(d.Get("logical_size_timeout_seconds").(int))*time.Second,
+	)
+	if err != nil {
+		return err
+	}
+	if err := updateStateBranch(d, branch, consoleBaseURL(meta)); err != nil {
+		return err
+	}
+	if err := setBranchParentName(d, meta.(sdkBranch), branch); err != nil {
 		return err
 	}
 
-	return nil
+	return d.Set("annotations_all", map[string]interface{}(annotations))
 }
 
 func resourceBranchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "update Branch")
 
+	if err := resetBranchOnRotation(ctx, d, meta.(sdkBranch), consoleBaseURL(meta)); err != nil {
+		return err
+	}
+
 	v, ok := d.GetOk("name")
 	if !ok || v.(string) == "" {
 		return nil
@@ -175,7 +593,7 @@ func resourceBranchUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		err  error
 	)
 	if d.HasChange("name") {
-		resp, err = meta.(*neon.Client).UpdateProjectBranch(d.Get("project_id").(string), d.Id(),
+		resp, err = meta.(sdkBranch).UpdateProjectBranch(d.Get("project_id").(string), d.Id(),
 			neon.BranchUpdateRequest{
 				Branch: neon.BranchUpdateRequestBranch{
 					Name: pointer(d.Get("name").(string)),
@@ -192,7 +610,7 @@ func resourceBranchUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		if status == nil {
 			status = pointer(false)
 		}
-		resp, err = meta.(*neon.Client).UpdateProjectBranch(d.Get("project_id").(string), d.Id(),
+		resp, err = meta.(sdkBranch).UpdateProjectBranch(d.Get("project_id").(string), d.Id(),
 			neon.BranchUpdateRequest{
 				Branch: neon.BranchUpdateRequestBranch{
 					Protected: status,
@@ -204,29 +622,233 @@ func resourceBranchUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 	}
 
-	return updateStateBranch(d, resp.Branch)
+	return updateStateBranch(d, resp.Branch, consoleBaseURL(meta))
+}
+
+// resetBranchOnRotation resets the branch from its parent if the rotation period rolled over since the last reset.
+func resetBranchOnRotation(ctx context.Context, d *schema.ResourceData, client sdkBranch, console string) error {
+	rotation, _ := d.Get("rotation").(string)
+	lastResetAt, _ := d.GetChange("last_reset_at")
+	if rotation == "" || !branchRotationDue(rotation, lastResetAt.(string), time.Now()) {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	tflog.Debug(ctx, "reset Branch from its parent", map[string]interface{}{
+		"projectID": projectID, "branchID": d.Id(), "rotation": rotation,
+	})
+
+	resp, err := client.RestoreProjectBranch(projectID, d.Id(), neon.BranchRestoreRequest{
+		SourceBranchID: d.Get("parent_id").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	return updateStateBranch(d, resp.Branch, console)
 }
 
 func resourceBranchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "read Branch")
 
-	resp, err := meta.(*neon.Client).GetProjectBranch(d.Get("project_id").(string), d.Id())
+	resp, err := meta.(sdkBranch).GetProjectBranch(d.Get("project_id").(string), d.Id())
+	if err != nil {
+		return err
+	}
+
+	if err := updateStateBranch(d, resp.Branch, consoleBaseURL(meta)); err != nil {
+		return err
+	}
+	if err := setBranchParentName(d, meta.(sdkBranch), resp.Branch); err != nil {
+		return err
+	}
+
+	return d.Set("annotations_all", annotationsToState(resp.Annotation.Value))
+}
+
+// checkAdoptedBranchParent fails if the parent of the branch to adopt differs from the configured parent,
+// because the branch would be replaced upon the next apply, i.e. the existing branch would be deleted.
+func checkAdoptedBranchParent(d *schema.ResourceData, branch neon.Branch, parentID string) error {
+	var got struct {
+		parentID, parentLsn string
+		parentTimestamp     int64
+	}
+	if branch.ParentID != nil {
+		got.parentID = *branch.ParentID
+	}
+	if branch.ParentLsn != nil {
+		got.parentLsn = *branch.ParentLsn
+	}
+	if branch.ParentTimestamp != nil {
+		got.parentTimestamp = branch.ParentTimestamp.Unix()
+	}
+
+	var attr string
+	var gotV, wantV interface{}
+	switch {
+	case parentID != "" && got.parentID != parentID:
+		attr, gotV, wantV = "parent_id", got.parentID, parentID
+	case isDefinedInConfig(d, "parent_lsn") && got.parentLsn != d.Get("parent_lsn").(string):
+		attr, gotV, wantV = "parent_lsn", got.parentLsn, d.Get("parent_lsn").(string)
+	case isDefinedInConfig(d, "parent_timestamp") && got.parentTimestamp != int64(d.Get("parent_timestamp").(int)):
+		attr, gotV, wantV = "parent_timestamp", got.parentTimestamp, d.Get("parent_timestamp").(int)
+	default:
+		return nil
+	}
+	return fmt.Errorf("cannot adopt the existing branch %s (%s): its %s %v differs from the configured %v, "+
+		"the branch would be replaced upon the next apply", branch.Name, branch.ID, attr, gotV, wantV)
+}
+
+// adoptBranchAnnotations sets the annotations of the adopted branch to the state. The branch keeps its existing
+// annotations because the API does not update them, the difference with the configured annotations is logged.
+func adoptBranchAnnotations(
+	ctx context.Context, d *schema.ResourceData, client sdkBranch, annotations neon.AnnotationValueData,
+) error {
+	resp, err := client.GetProjectBranch(d.Get("project_id").(string), d.Id())
 	if err != nil {
 		return err
 	}
 
-	return updateStateBranch(d, resp.Branch)
+	got := annotationsToState(resp.Annotation.Value)
+	want := annotationsToState(annotations)
+	for k, v := range want {
+		if got[k] != v {
+			tflog.Warn(ctx, "adopted Branch keeps its existing annotations", map[string]interface{}{
+				"branchID": d.Id(), "annotation": k,
+			})
+			break
+		}
+	}
+
+	return d.Set("annotations_all", got)
+}
+
+// setBranchParentName reads the name of the branch's parent. The root branch has no parent.
+func setBranchParentName(d *schema.ResourceData, client sdkBranch, branch neon.Branch) error {
+	if branch.ParentID == nil || *branch.ParentID == "" {
+		return d.Set("parent_name", "")
+	}
+
+	resp, err := client.GetProjectBranch(d.Get("project_id").(string), *branch.ParentID)
+	if err != nil {
+		return err
+	}
+	return d.Set("parent_name", resp.Branch.Name)
 }
 
 func resourceBranchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "delete Branch")
 
-	if _, err := meta.(*neon.Client).DeleteProjectBranch(d.Get("project_id").(string), d.Id()); err != nil {
+	if d.Get("skip_delete").(bool) {
+		tflog.Info(ctx, "skip Branch deletion, remove it from the state only",
+			map[string]interface{}{"branchID": d.Id()})
+		d.SetId("")
+		return nil
+	}
+
+	client := meta.(sdkBranch)
+	projectID := d.Get("project_id").(string)
+
+	branch, err := client.GetProjectBranch(projectID, d.Id())
+	if err != nil {
+		return err
+	}
+	if branch.Branch.Default {
+		return errDefaultBranchDeletion(projectID, d.Id())
+	}
+
+	if d.Get("delete_dependents").(bool) {
+		if err := deleteBranchDependents(ctx, meta, projectID, d.Id()); err != nil {
+			return err
+		}
+	}
+
+	if _, err := client.DeleteProjectBranch(projectID, d.Id()); err != nil {
+		return err
+	}
+
+	if err := waitDeleted(ctx, func() error {
+		_, err := client.GetProjectBranch(projectID, d.Id())
+		return err
+	}); err != nil {
 		return err
 	}
 
 	d.SetId("")
-	return updateStateBranch(d, neon.Branch{})
+	return updateStateBranch(d, neon.Branch{}, consoleBaseURL(meta))
+}
+
+// deleteBranchDependents deletes the branch's endpoints, and reports the databases and roles
+// which are deleted together with the branch. It fails if the branch has children.
+func deleteBranchDependents(ctx context.Context, meta interface{}, projectID, branchID string) error {
+	client := meta.(sdkBranch)
+	branches, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return err
+	}
+	var children []string
+	for _, br := range branches.Branches {
+		if br.ParentID != nil && *br.ParentID == branchID {
+			children = append(children, br.Name+" ("+br.ID+")")
+		}
+	}
+	if len(children) > 0 {
+		return errors.New("the branch " + branchID + " cannot be deleted because it has the child branches: " +
+			strings.Join(children, ", "))
+	}
+
+	endpoints, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints.Endpoints {
+		tflog.Info(ctx, "delete Endpoint of the Branch", map[string]interface{}{
+			"branchID": branchID, "endpointID": endpoint.ID,
+		})
+		// the locked project is retried by the retry of the branch deletion,
+		// the endpoints deleted by the previous attempt are not listed again
+		if _, err := client.DeleteProjectEndpoint(projectID, endpoint.ID); err != nil {
+			return err
+		}
+		if err := waitDeleted(ctx, func() error {
+			_, err := client.GetProjectEndpoint(projectID, endpoint.ID)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	databases, err := client.ListProjectBranchDatabases(projectID, branchID)
+	if err != nil {
+		return err
+	}
+	for _, db := range databases.Databases {
+		tflog.Info(ctx, "the Database is deleted together with the Branch", map[string]interface{}{
+			"branchID": branchID, "database": db.Name, "owner": db.OwnerName,
+		})
+	}
+
+	roles, err := client.ListProjectBranchRoles(projectID, branchID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles.Roles {
+		if role.Protected == nil || !*role.Protected {
+			tflog.Info(ctx, "the Role is deleted together with the Branch", map[string]interface{}{
+				"branchID": branchID, "role": role.Name,
+			})
+		}
+	}
+
+	return nil
+}
+
+func errDefaultBranchDeletion(projectID, branchID string) error {
+	return errors.New(
+		"the branch " + branchID + " is the default branch of the project " + projectID + " and cannot be deleted. " +
+			"Set another branch as the default branch first, " +
+			"see details: https://api-docs.neon.tech/reference/setdefaultprojectbranch",
+	)
 }
 
 func resourceBranchImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
@@ -238,13 +860,13 @@ func resourceBranchImport(ctx context.Context, d *schema.ResourceData, meta inte
 		return nil, errors.New("branch ID " + d.Id() + " is not valid")
 	}
 
-	resp, err := meta.(*neon.Client).ListProjects(nil, nil, nil, nil)
+	resp, err := meta.(sdkBranch).ListProjects(nil, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, project := range resp.Projects {
-		r, err := meta.(*neon.Client).ListProjectBranches(project.ID, nil)
+		r, err := meta.(sdkBranch).ListProjectBranches(project.ID, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -253,9 +875,26 @@ func resourceBranchImport(ctx context.Context, d *schema.ResourceData, meta inte
 				if err := d.Set("project_id", project.ID); err != nil {
 					return nil, err
 				}
+				if err := d.Set("skip_delete", false); err != nil {
+					return nil, err
+				}
+				if err := d.Set("check_branches_limit", false); err != nil {
+					return nil, err
+				}
+				if err := d.Set("adopt_existing", false); err != nil {
+					return nil, err
+				}
+				if err := d.Set("logical_size_timeout_seconds", 0); err != nil {
+					return nil, err
+				}
 				if err := resourceBranchRead(ctx, d, meta); err != nil {
 					return nil, err
 				}
+				if err := d.Set(
+					"annotations", ownAnnotations(meta, d.Get("annotations_all").(map[string]interface{})),
+				); err != nil {
+					return nil, err
+				}
 				return []*schema.ResourceData{d}, nil
 			}
 		}
@@ -264,7 +903,133 @@ func resourceBranchImport(ctx context.Context, d *schema.ResourceData, meta inte
 	return nil, errors.New("no branch " + d.Id() + " found")
 }
 
+// findBranchIDByName returns ID of the project's branch with the given name.
+func findBranchIDByName(client sdkBranchLister, projectID, name string) (string, error) {
+	resp, err := client.ListProjectBranches(projectID, &name)
+	if err != nil {
+		return "", err
+	}
+
+	// the search is done by the partial match
+	for _, br := range resp.Branches {
+		if br.Name == name {
+			return br.ID, nil
+		}
+	}
+
+	return "", errors.New("no branch " + name + " found in the project " + projectID)
+}
+
+// findBranchByName returns the branch with the exact name, and false if no such branch exists.
+func findBranchByName(client sdkBranchLister, projectID, name string) (neon.Branch, bool, error) {
+	resp, err := client.ListProjectBranches(projectID, &name)
+	if err != nil {
+		return neon.Branch{}, false, err
+	}
+
+	// the search is done by the partial match
+	for _, br := range resp.Branches {
+		if br.Name == name {
+			return br, true, nil
+		}
+	}
+
+	return neon.Branch{}, false, nil
+}
+
 func isValidBranchID(s string) bool {
 	const prefix = "br-"
 	return strings.HasPrefix(s, prefix) && len(strings.TrimPrefix(s, prefix)) > 0
 }
+
+type sdkBranchLister interface {
+	ListProjectBranches(string, *string) (neon.ListProjectBranchesRespObj, error)
+}
+
+type sdkBranch interface {
+	sdkBranchLister
+	ListProjects(*string, *int, *string, *string) (neon.ListProjectsRespObj, error)
+	GetProject(string) (neon.ProjectResponse, error)
+	CreateProjectBranch(string, *neon.CreateProjectBranchReqObj) (neon.CreatedBranch, error)
+	GetProjectBranch(string, string) (neon.GetProjectBranchRespObj, error)
+	UpdateProjectBranch(string, string, neon.BranchUpdateRequest) (neon.BranchOperations, error)
+	DeleteProjectBranch(string, string) (neon.BranchOperations, error)
+	RestoreProjectBranch(string, string, neon.BranchRestoreRequest) (neon.BranchOperations, error)
+	ListProjectBranchEndpoints(string, string) (neon.EndpointsResponse, error)
+	ListProjectBranchDatabases(string, string) (neon.DatabasesResponse, error)
+	ListProjectBranchRoles(string, string) (neon.RolesResponse, error)
+	GetProjectEndpoint(string, string) (neon.EndpointResponse, error)
+	DeleteProjectEndpoint(string, string) (neon.EndpointOperations, error)
+}
+
+var branchLogicalSize = delay{
+	delay: 2 * time.Second,
+}
+
+// waitBranchLogicalSize polls the branch until its logical size is computed, or the timeout elapses.
+func waitBranchLogicalSize(
+	ctx context.Context, client sdkBranch, branch neon.Branch, timeout time.Duration,
+) (neon.Branch, error) {
+	if timeout <= 0 || branch.LogicalSize != nil {
+		return branch, nil
+	}
+
+	tflog.Debug(ctx, "wait for Branch logical size", map[string]interface{}{"branchID": branch.ID})
+	done, err := poll(ctx, branchLogicalSize.delay, timeout, func() (bool, error) {
+		resp, err := client.GetProjectBranch(branch.ProjectID, branch.ID)
+		if err != nil {
+			return false, err
+		}
+		branch = resp.Branch
+		return branch.LogicalSize != nil, nil
+	})
+	if err != nil || done {
+		return branch, err
+	}
+
+	tflog.Warn(ctx, "Branch logical size is not computed after the timeout", map[string]interface{}{
+		"branchID": branch.ID, "timeout": timeout.String(),
+	})
+	return branch, nil
+}
+
+// mergeAnnotations returns the branch's annotations merged with the provider's default annotations.
+func mergeAnnotations(meta interface{}, annotations map[string]interface{}) neon.AnnotationValueData {
+	o := neon.AnnotationValueData{}
+	if c, ok := meta.(*providerClient); ok {
+		for k, v := range c.defaultAnnotations {
+			o[k] = v
+		}
+	}
+	for k, v := range annotations {
+		o[k] = v
+	}
+	return o
+}
+
+// ownAnnotations returns the branch's annotations which are not inherited from the provider's default annotations.
+func ownAnnotations(meta interface{}, annotations map[string]interface{}) map[string]interface{} {
+	var defaults map[string]interface{}
+	if c, ok := meta.(*providerClient); ok {
+		defaults = c.defaultAnnotations
+	}
+	o := map[string]interface{}{}
+	for k, v := range annotations {
+		if dv, ok := defaults[k]; !ok || dv != v {
+			o[k] = v
+		}
+	}
+	return o
+}
+
+func annotationsToState(v neon.AnnotationValueData) map[string]interface{} {
+	o := make(map[string]interface{}, len(v))
+	for k, v := range v {
+		if s, ok := v.(string); ok {
+			o[k] = s
+		} else {
+			o[k] = fmt.Sprint(v)
+		}
+	}
+	return o
+}
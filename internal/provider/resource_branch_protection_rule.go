@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func resourceBranchProtectionRule() *schema.Resource {
+	return &schema.Resource{
+		Description: `Protection of the project's branches which names match the pattern, e.g. "release/*".
+The pattern is evaluated against the project's branches upon every apply, hence the branches created outside
+terraform are protected by the next apply. See details: https://neon.tech/docs/guides/protected-branches
+**Note** that the branches remain protected upon destroy.`,
+		SchemaVersion: 1,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceBranchProtectionRuleImport,
+		},
+		CreateContext: resourceBranchProtectionRuleCreateRetry,
+		ReadContext:   resourceBranchProtectionRuleReadRetry,
+		UpdateContext: resourceBranchProtectionRuleUpdateRetry,
+		DeleteContext: resourceBranchProtectionRuleDelete,
+		CustomizeDiff: resourceBranchProtectionRuleCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Project ID.",
+			},
+			"pattern": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateBranchNamePattern,
+				Description: `Pattern of the branches names, e.g. "release/*". The syntax of the shell file name
+pattern is supported: "*" matches any sequence of characters except "/", "?" matches any single character except
+"/", and "[...]" matches the characters range. The branches matching the previous pattern remain protected upon change.`,
+			},
+			"protected_branch_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the protected branches which match the pattern.",
+			},
+			"unprotected_branch_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: `IDs of the branches which match the pattern, but are not protected yet,
+e.g. the branches created outside terraform. They are protected by the next apply.`,
+			},
+		},
+	}
+}
+
+type sdkBranchProtectionRule interface {
+	sdkBranchLister
+	UpdateProjectBranch(string, string, neon.BranchUpdateRequest) (neon.BranchOperations, error)
+}
+
+func validateBranchNamePattern(i interface{}, _ string) ([]string, []error) {
+	v := i.(string)
+	if strings.TrimSpace(v) == "" {
+		return nil, []error{errors.New("pattern must not be empty")}
+	}
+	if _, err := path.Match(v, ""); err != nil {
+		return nil, []error{errors.New("pattern " + v + " is not valid: " + err.Error())}
+	}
+	return nil, nil
+}
+
+// matchBranchProtection returns IDs of the protected, and the unprotected branches which names match the pattern.
+func matchBranchProtection(branches []neon.Branch, pattern string) (protected, unprotected []string) {
+	protected, unprotected = []string{}, []string{}
+	for _, br := range branches {
+		if ok, _ := path.Match(pattern, br.Name); !ok {
+			continue
+		}
+		if br.Protected {
+			protected = append(protected, br.ID)
+		} else {
+			unprotected = append(unprotected, br.ID)
+		}
+	}
+	return protected, unprotected
+}
+
+func updateStateBranchProtectionRule(d *schema.ResourceData, protected, unprotected []string) error {
+	if err := d.Set("protected_branch_ids", protected); err != nil {
+		return err
+	}
+	return d.Set("unprotected_branch_ids", unprotected)
+}
+
+// resourceBranchProtectionRuleCustomizeDiff plans the update to protect the matching branches which are
+// not protected yet.
+func resourceBranchProtectionRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("pattern") {
+		if err := d.SetNewComputed("protected_branch_ids"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("unprotected_branch_ids")
+	}
+	if v, ok := d.Get("unprotected_branch_ids").([]interface{}); ok && len(v) > 0 {
+		tflog.Info(ctx, "protect the branches matching the pattern", map[string]interface{}{"branchIDs": v})
+		if err := d.SetNewComputed("protected_branch_ids"); err != nil {
+			return err
+		}
+		return d.SetNew("unprotected_branch_ids", []string{})
+	}
+	return nil
+}
+
+func resourceBranchProtectionRuleCreateRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.Retry(resourceBranchProtectionRuleApply, ctx, d, meta)
+}
+
+func resourceBranchProtectionRuleUpdateRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.Retry(resourceBranchProtectionRuleApply, ctx, d, meta)
+}
+
+// resourceBranchProtectionRuleApply protects the branches which match the pattern. It's retried as a whole
+// because the project is locked after every branch's update, the protected branches are skipped by the retry.
+func resourceBranchProtectionRuleApply(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "apply Branch Protection Rule")
+
+	client := meta.(sdkBranchProtectionRule)
+	projectID := d.Get("project_id").(string)
+	pattern := d.Get("pattern").(string)
+
+	resp, err := client.ListProjectBranches(projectID, nil)
+	if err != nil {
+		return err
+	}
+
+	protected, unprotected := matchBranchProtection(resp.Branches, pattern)
+	for len(unprotected) > 0 {
+		branchID := unprotected[0]
+		tflog.Debug(ctx, "protect Branch", map[string]interface{}{"branchID": branchID, "pattern": pattern})
+		if _, err := client.UpdateProjectBranch(projectID, branchID, neon.BranchUpdateRequest{
+			Branch: neon.BranchUpdateRequestBranch{Protected: pointer(true)},
+		}); err != nil {
+			return err
+		}
+		protected, unprotected = append(protected, branchID), unprotected[1:]
+	}
+
+	// the ID follows the pattern to import the rule
+	d.SetId(projectID + "/" + pattern)
+	return updateStateBranchProtectionRule(d, protected, unprotected)
+}
+
+func resourceBranchProtectionRuleReadRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.RetryRead(resourceBranchProtectionRuleRead, ctx, d, meta)
+}
+
+func resourceBranchProtectionRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "read Branch Protection Rule")
+
+	resp, err := meta.(sdkBranchProtectionRule).ListProjectBranches(d.Get("project_id").(string), nil)
+	if err != nil {
+		return err
+	}
+
+	protected, unprotected := matchBranchProtection(resp.Branches, d.Get("pattern").(string))
+	return updateStateBranchProtectionRule(d, protected, unprotected)
+}
+
+func resourceBranchProtectionRuleDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.Info(ctx, "Branch Protection Rule is removed from the state only, the branches remain protected",
+		map[string]interface{}{"id": d.Id()})
+	d.SetId("")
+	return nil
+}
+
+func resourceBranchProtectionRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
+	[]*schema.ResourceData, error,
+) {
+	projectID, pattern, ok := strings.Cut(d.Id(), "/")
+	if !ok || projectID == "" || pattern == "" {
+		return nil, errors.New("ID of this resource type shall follow the template: {{.ProjectID}}/{{.Pattern}}")
+	}
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	if err := d.Set("pattern", pattern); err != nil {
+		return nil, err
+	}
+	if err := resourceBranchProtectionRuleRead(ctx, d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
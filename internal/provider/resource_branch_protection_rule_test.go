@@ -0,0 +1,139 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resourceBranchProtectionRule(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	newBranch := func(name string) string {
+		t.Helper()
+		resp, err := client.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+			BranchCreateRequest: neon.BranchCreateRequest{
+				Branch: &neon.BranchCreateRequestBranch{Name: pointer(name)},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Branch.ID
+	}
+	isProtected := func(branchID string) bool {
+		t.Helper()
+		resp, err := client.GetProjectBranch(projectID, branchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Branch.Protected
+	}
+
+	release1 := newBranch("release/1.0")
+	feature := newBranch("feature/foo")
+
+	d := resourceBranchProtectionRule().TestResourceData()
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("pattern", "release/*")
+
+	ctx := context.TODO()
+
+	// WHEN
+	if diags := resourceBranchProtectionRuleCreateRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Equal(t, projectID+"/release/*", d.Id())
+	assert.True(t, isProtected(release1))
+	assert.False(t, isProtected(feature))
+	assert.Equal(t, []interface{}{release1}, d.Get("protected_branch_ids"))
+	assert.Empty(t, d.Get("unprotected_branch_ids"))
+
+	// GIVEN the branch created outside terraform
+	release2 := newBranch("release/2.0")
+
+	// WHEN
+	if diags := resourceBranchProtectionRuleReadRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Equal(t, []interface{}{release2}, d.Get("unprotected_branch_ids"))
+
+	// WHEN
+	if diags := resourceBranchProtectionRuleUpdateRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.True(t, isProtected(release2))
+	assert.ElementsMatch(t, []interface{}{release1, release2}, d.Get("protected_branch_ids"))
+	assert.Empty(t, d.Get("unprotected_branch_ids"))
+}
+
+func Test_validateBranchNamePattern(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	for pattern, wantErr := range map[string]bool{
+		"release/*":  false,
+		"hotfix-?":   false,
+		"v[0-9]*":    false,
+		"":           true,
+		"release/[":  true,
+		"release/\\": true,
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			_, errs := validateBranchNamePattern(pattern, "pattern")
+			assert.Equal(t, wantErr, len(errs) > 0)
+		})
+	}
+}
+
+func FuzzValidateBranchNamePattern(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"release/*", "release/v1"}, {"hotfix-?", "hotfix-1"}, {"v[0-9]*", "v1"}, {"", ""}, {"release/[", "release/"},
+		{"release/\\", "release/"}, {"[a-", "a"}, {"[]a]", "]"}, {"*?", ""},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, pattern, name string) {
+		_, errs := validateBranchNamePattern(pattern, "pattern")
+		if len(errs) > 0 {
+			return
+		}
+		// the accepted pattern matches any branch name without the error
+		if _, err := path.Match(pattern, name); err != nil {
+			t.Errorf("malformed pattern %q is accepted: %v", pattern, err)
+		}
+		matchBranchProtection([]neon.Branch{{ID: "br-foo", Name: name}}, pattern)
+	})
+}
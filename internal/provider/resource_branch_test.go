@@ -4,8 +4,20 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
 )
 
 func Test_isValidBranchID(t *testing.T) {
@@ -58,3 +70,879 @@ func Test_isValidBranchID(t *testing.T) {
 		})
 	}
 }
+
+func Test_resourceBranchDelete_skipDelete(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	d := resourceBranch().TestResourceData()
+	d.SetId("br-foo")
+	if err := d.Set("project_id", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set("skip_delete", true); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	// the nil client guarantees that no API call is made
+	err := resourceBranchDelete(context.TODO(), d, nil)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("the branch shall be removed from the state, got ID: %s", d.Id())
+	}
+}
+
+func Test_checkBranchesLimit(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	branches := []neon.Branch{
+		{ID: "br-main", Name: "main", Default: true, CreatedAt: now.Add(-4 * time.Hour)},
+		{ID: "br-prod", Name: "prod", Protected: true, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "br-new", Name: "new", CreatedAt: now},
+		{ID: "br-old", Name: "old", CreatedAt: now.Add(-2 * time.Hour)},
+	}
+
+	t.Run("shall pass if the limit is not reached", func(t *testing.T) {
+		if err := checkBranchesLimit("foo", 5, branches, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("shall list the oldest deletable branches if the limit is reached", func(t *testing.T) {
+		err := checkBranchesLimit("foo", 4, branches, 1)
+		if err == nil {
+			t.Fatal("error is expected")
+		}
+
+		const want = `the project foo reached the limit of 4 branches, the oldest branches which can be deleted:
+- old (br-old), created at 2024-09-30T22:00:00Z
+- new (br-new), created at 2024-10-01T00:00:00Z`
+		if err.Error() != want {
+			t.Errorf("unexpected error message. want:\n%s\ngot:\n%s", want, err.Error())
+		}
+	})
+
+	t.Run("shall fail if all branches of the plan exceed the limit", func(t *testing.T) {
+		if err := checkBranchesLimit("foo", 6, branches, 2); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err := checkBranchesLimit("foo", 6, branches, 3)
+		if err == nil || !strings.Contains(err.Error(),
+			"the project foo has 4 branches, the plan creates 3 branches which exceed the limit of 6 branches") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func Test_plannedBranches(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	var p plannedBranches
+	for i, want := range []int{1, 2, 3} {
+		if got := p.add("foo"); got != want {
+			t.Errorf("unexpected number of planned branches upon the call %d: %d", i, got)
+		}
+	}
+	if got := p.add("bar"); got != 1 {
+		t.Errorf("the branches shall be accounted per project, got: %d", got)
+	}
+}
+
+func Test_findBranchIDByName(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Query().Get("search") != "dev" {
+				t.Fatalf("unexpected search query: %s", r.URL.RawQuery)
+			}
+			return newHTTPResponse(http.StatusOK,
+				`{"branches":[{"id":"br-dev-1","name":"dev-1"},{"id":"br-dev","name":"dev"}]}`), nil
+		}),
+	})
+
+	t.Run("shall find the branch by its exact name", func(t *testing.T) {
+		got, err := findBranchIDByName(client, "bar", "dev")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "br-dev" {
+			t.Errorf("unexpected branch ID: %s", got)
+		}
+	})
+
+	t.Run("shall return error if no branch found", func(t *testing.T) {
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				return newHTTPResponse(http.StatusOK, `{"branches":[{"id":"br-dev-1","name":"dev-1"}]}`), nil
+			}),
+		})
+		if _, err := findBranchIDByName(client, "bar", "dev"); err == nil {
+			t.Fatal("error is expected")
+		}
+	})
+}
+
+func Test_resourceBranch_renamedOutsideTerraform(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	const branchID = "br-foo-123"
+
+	t.Run("shall read the name defined outside terraform", func(t *testing.T) {
+		// GIVEN
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodGet {
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				return newHTTPResponse(http.StatusOK,
+					`{"branch":{"id":"`+branchID+`","project_id":"bar","name":"renamed"}}`), nil
+			}),
+		})
+
+		d := resourceBranch().TestResourceData()
+		d.SetId(branchID)
+		_ = d.Set("project_id", "bar")
+		_ = d.Set("name", "original")
+
+		// WHEN
+		err := resourceBranchRead(context.TODO(), d, client)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := d.Get("name").(string); v != "renamed" {
+			t.Errorf("unexpected name: %s", v)
+		}
+		if d.Id() != branchID {
+			t.Errorf("the branch ID is expected to remain unchanged, got: %s", d.Id())
+		}
+	})
+
+	t.Run("shall restore the name defined in the configuration", func(t *testing.T) {
+		// GIVEN
+		var reqPath string
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodPatch {
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				reqPath = r.URL.Path
+				return newHTTPResponse(http.StatusOK,
+					`{"branch":{"id":"`+branchID+`","project_id":"bar","name":"original"}}`), nil
+			}),
+		})
+
+		d := schema.TestResourceDataRaw(t, resourceBranch().Schema, map[string]interface{}{
+			"project_id": "bar",
+			"name":       "original",
+		})
+		d.SetId(branchID)
+
+		// WHEN
+		err := resourceBranchUpdate(context.TODO(), d, client)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reqPath != "/api/v2/projects/bar/branches/"+branchID {
+			t.Errorf("unexpected request path: %s", reqPath)
+		}
+		if v := d.Get("name").(string); v != "original" {
+			t.Errorf("unexpected name: %s", v)
+		}
+	})
+}
+
+func Test_resourceBranchDelete_defaultBranch(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodGet {
+				t.Fatalf("the default branch deletion shall not be requested: %s %s", r.Method, r.URL.Path)
+			}
+			return newHTTPResponse(http.StatusOK,
+				`{"branch":{"id":"br-foo","project_id":"bar","name":"main","default":true}}`), nil
+		}),
+	})
+
+	d := resourceBranch().TestResourceData()
+	d.SetId("br-foo")
+	_ = d.Set("project_id", "bar")
+
+	// WHEN
+	err := resourceBranchDelete(context.TODO(), d, client)
+
+	// THEN
+	if err == nil || err.Error() != errDefaultBranchDeletion("bar", "br-foo").Error() {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if d.Id() != "br-foo" {
+		t.Errorf("the branch shall remain in the state, got ID: %s", d.Id())
+	}
+}
+
+func Test_resourceBranchDelete_deleteDependents(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+
+	var deletions []string
+	var cntLocked int
+	client, err := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodDelete {
+				if cntLocked > 0 && strings.Contains(r.URL.Path, "/endpoints/") {
+					cntLocked--
+					return newHTTPResponse(http.StatusLocked, `{"message":"project is locked"}`), nil
+				}
+				deletions = append(deletions, r.URL.Path)
+			}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, r)
+			return w.Result(), nil
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	newBranch := func(parentID string) neon.CreatedBranch {
+		resp, err := client.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+			BranchCreateRequest: neon.BranchCreateRequest{
+				Branch:    &neon.BranchCreateRequestBranch{ParentID: pointer(parentID)},
+				Endpoints: &[]neon.BranchCreateRequestEndpointOptions{{Type: neon.EndpointTypeReadWrite}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	newResourceData := func(branchID string) *schema.ResourceData {
+		d := resourceBranch().TestResourceData()
+		d.SetId(branchID)
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("delete_dependents", true)
+		return d
+	}
+
+	t.Run("shall fail if the branch has children", func(t *testing.T) {
+		// GIVEN
+		parent := newBranch(project.Branch.ID)
+		newBranch(parent.Branch.ID)
+		deletions = nil
+
+		// WHEN
+		err := resourceBranchDelete(context.TODO(), newResourceData(parent.Branch.ID), client)
+
+		// THEN
+		if err == nil || !strings.Contains(err.Error(), "child branches") {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(deletions) > 0 {
+			t.Errorf("no deletion shall be requested, got: %v", deletions)
+		}
+	})
+
+	t.Run("shall delete the endpoints before the branch", func(t *testing.T) {
+		// GIVEN
+		branch := newBranch(project.Branch.ID)
+		deletions = nil
+
+		// WHEN
+		err := resourceBranchDelete(context.TODO(), newResourceData(branch.Branch.ID), client)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{
+			"/api/v2/projects/" + projectID + "/endpoints/" + branch.Endpoints[0].ID,
+			"/api/v2/projects/" + projectID + "/branches/" + branch.Branch.ID,
+		}
+		if !reflect.DeepEqual(want, deletions) {
+			t.Errorf("unexpected deletions, want: %v, got: %v", want, deletions)
+		}
+	})
+
+	t.Run("shall retry the deletion if the project is locked", func(t *testing.T) {
+		// GIVEN
+		defaultDelay := projectReadiness.delay
+		projectReadiness.delay = 0
+		t.Cleanup(func() { projectReadiness.delay = defaultDelay })
+
+		branch := newBranch(project.Branch.ID)
+		deletions = nil
+		cntLocked = 1
+
+		// WHEN
+		diags := resourceBranchDeleteRetry(context.TODO(), newResourceData(branch.Branch.ID), client)
+
+		// THEN
+		if diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		want := []string{
+			"/api/v2/projects/" + projectID + "/endpoints/" + branch.Endpoints[0].ID,
+			"/api/v2/projects/" + projectID + "/branches/" + branch.Branch.ID,
+		}
+		if !reflect.DeepEqual(want, deletions) {
+			t.Errorf("unexpected deletions, want: %v, got: %v", want, deletions)
+		}
+	})
+}
+
+func Test_resourceBranchCreate_adoptExisting(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	existing, err := client.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+		AnnotationCreateValueRequest: neon.AnnotationCreateValueRequest{
+			AnnotationValue: &neon.AnnotationValueData{"owner": "foo"},
+		},
+		BranchCreateRequest: neon.BranchCreateRequest{
+			Branch: &neon.BranchCreateRequestBranch{Name: pointer("ci-42")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newResourceData := func(name string) *schema.ResourceData {
+		d := resourceBranch().TestResourceData()
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("name", name)
+		_ = d.Set("adopt_existing", true)
+		return d
+	}
+
+	t.Run("shall adopt the existing branch", func(t *testing.T) {
+		// GIVEN
+		d := newResourceData("ci-42")
+		_ = d.Set("annotations", map[string]interface{}{"owner": "bar"})
+
+		// WHEN
+		if err := resourceBranchCreate(context.TODO(), d, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if d.Id() != existing.Branch.ID {
+			t.Errorf("the existing branch %s shall be adopted, got: %s", existing.Branch.ID, d.Id())
+		}
+		// the adopted branch keeps its annotations
+		if got := d.Get("annotations_all").(map[string]interface{}); len(got) != 1 || got["owner"] != "foo" {
+			t.Errorf("unexpected annotations_all of the adopted branch: %v", got)
+		}
+		resp, err := client.ListProjectBranches(projectID, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Branches) != 2 {
+			t.Errorf("no branch shall be created, got: %d branches", len(resp.Branches))
+		}
+	})
+
+	t.Run("shall create the branch if it does not exist", func(t *testing.T) {
+		// GIVEN
+		d := newResourceData("ci-4")
+
+		// WHEN
+		if err := resourceBranchCreate(context.TODO(), d, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if d.Id() == "" || d.Id() == existing.Branch.ID {
+			t.Errorf("the new branch shall be created, got: %s", d.Id())
+		}
+	})
+
+	t.Run("shall fail to adopt the existing branch with the different parent", func(t *testing.T) {
+		// GIVEN
+		child, err := client.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+			BranchCreateRequest: neon.BranchCreateRequest{
+				Branch: &neon.BranchCreateRequestBranch{Name: pointer("ci-43"), ParentID: &existing.Branch.ID},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := newResourceData("ci-43")
+		_ = d.Set("parent_id", *existing.Branch.ParentID)
+
+		// WHEN
+		err = resourceBranchCreate(context.TODO(), d, client)
+
+		// THEN
+		if err == nil || !strings.Contains(err.Error(), "parent_id") {
+			t.Errorf("the error on the different parent_id is expected, got: %v", err)
+		}
+		if d.Id() != "" {
+			t.Errorf("the branch shall not be adopted, got: %s", d.Id())
+		}
+
+		// WHEN the configured parent matches
+		d = newResourceData("ci-43")
+		_ = d.Set("parent_id", existing.Branch.ID)
+		if err := resourceBranchCreate(context.TODO(), d, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if d.Id() != child.Branch.ID {
+			t.Errorf("the existing branch %s shall be adopted, got: %s", child.Branch.ID, d.Id())
+		}
+	})
+}
+
+func Test_validateParentTimestamp(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		ts      time.Time
+		wantErr bool
+	}{
+		{
+			name: "within the retention window",
+			ts:   now.Add(-1 * time.Hour),
+		},
+		{
+			name: "the oldest permitted timestamp",
+			ts:   now.Add(-retention),
+		},
+		{
+			name:    "in the future",
+			ts:      now.Add(1 * time.Minute),
+			wantErr: true,
+		},
+		{
+			name:    "beyond the retention window",
+			ts:      now.Add(-retention - time.Second),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateParentTimestamp(tt.ts, now, retention); (err != nil) != tt.wantErr {
+				t.Errorf("validateParentTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_projectHistoryRetention(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	var cnt int
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			cnt++
+			return newHTTPResponse(http.StatusOK,
+				`{"project":{"id":"retention-cache-test","history_retention_seconds":3600}}`), nil
+		}),
+	})
+	t.Cleanup(func() { projectHistoryRetentionCache.Delete("retention-cache-test") })
+
+	for i := 0; i < 2; i++ {
+		// WHEN
+		got, err := projectHistoryRetention(client, "retention-cache-test")
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != time.Hour {
+			t.Errorf("unexpected retention: %v", got)
+		}
+	}
+	if cnt != 1 {
+		t.Errorf("the project shall be requested once, got: %d", cnt)
+	}
+}
+
+func Test_branchRotationDue(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// Wednesday
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		rotation    string
+		lastResetAt string
+		want        bool
+	}{
+		{
+			name:        "daily: reset today",
+			rotation:    "daily",
+			lastResetAt: "2024-05-15T00:00:00Z",
+		},
+		{
+			name:        "daily: reset yesterday",
+			rotation:    "daily",
+			lastResetAt: "2024-05-14T23:59:59Z",
+			want:        true,
+		},
+		{
+			name:        "weekly: reset on Monday",
+			rotation:    "weekly",
+			lastResetAt: "2024-05-13T08:00:00Z",
+		},
+		{
+			name:        "weekly: reset on Sunday",
+			rotation:    "weekly",
+			lastResetAt: "2024-05-12T23:00:00Z",
+			want:        true,
+		},
+		{
+			name:        "monthly: reset this month",
+			rotation:    "monthly",
+			lastResetAt: "2024-05-01T00:00:00Z",
+		},
+		{
+			name:        "monthly: reset last month",
+			rotation:    "monthly",
+			lastResetAt: "2024-04-30T12:00:00Z",
+			want:        true,
+		},
+		{
+			name:        "unknown last reset",
+			rotation:    "daily",
+			lastResetAt: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := branchRotationDue(tt.rotation, tt.lastResetAt, now); got != tt.want {
+				t.Errorf("branchRotationDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resourceBranchCreate_annotations(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &providerClient{
+		Client:             client,
+		defaultAnnotations: map[string]interface{}{"owner": "platform", "cost_center": "42"},
+	}
+
+	d := resourceBranch().TestResourceData()
+	_ = d.Set("project_id", project.Project.ID)
+	_ = d.Set("name", "ci-42")
+	_ = d.Set("annotations", map[string]interface{}{"owner": "team-a"})
+
+	want := map[string]interface{}{"owner": "team-a", "cost_center": "42"}
+
+	// WHEN
+	if err := resourceBranchCreate(context.TODO(), d, meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	if got := d.Get("annotations_all"); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected annotations upon create. want: %v, got: %v", want, got)
+	}
+
+	// WHEN
+	_ = d.Set("annotations_all", nil)
+	if err := resourceBranchRead(context.TODO(), d, meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	if got := d.Get("annotations_all"); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected annotations upon read. want: %v, got: %v", want, got)
+	}
+
+	// the provider's default annotations are not imported as the branch's own annotations
+	own := ownAnnotations(meta, d.Get("annotations_all").(map[string]interface{}))
+	if want := map[string]interface{}{"owner": "team-a"}; !reflect.DeepEqual(own, want) {
+		t.Errorf("unexpected own annotations. want: %v, got: %v", want, own)
+	}
+}
+
+func Test_waitBranchLogicalSize(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := branchLogicalSize.delay
+	branchLogicalSize.delay = 0
+	t.Cleanup(func() { branchLogicalSize.delay = defaultDelay })
+
+	created := neon.Branch{ID: "br-foo", ProjectID: "bar"}
+
+	newClient := func(cntGet *int, emptyCnt int) *neon.Client {
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodGet {
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				*cntGet++
+				if emptyCnt < 0 || *cntGet <= emptyCnt {
+					return newHTTPResponse(http.StatusOK, `{"branch":{"id":"br-foo","project_id":"bar"}}`), nil
+				}
+				return newHTTPResponse(http.StatusOK,
+					`{"branch":{"id":"br-foo","project_id":"bar","logical_size":30146560}}`), nil
+			}),
+		})
+		return client
+	}
+
+	t.Run("shall wait until the logical size is computed", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, 1)
+
+		// WHEN
+		got, err := waitBranchLogicalSize(context.TODO(), client, created, time.Minute)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet != 2 {
+			t.Errorf("the branch shall be polled until its size is computed, got: %d calls", cntGet)
+		}
+		if got.LogicalSize == nil || *got.LogicalSize != 30146560 {
+			t.Errorf("unexpected logical size: %v", got.LogicalSize)
+		}
+	})
+
+	t.Run("shall return the branch without the size when the timeout elapses", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		got, err := waitBranchLogicalSize(context.TODO(), client, created, 10*time.Millisecond)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.LogicalSize != nil {
+			t.Errorf("unexpected logical size: %v", *got.LogicalSize)
+		}
+	})
+
+	t.Run("shall stop waiting once the context is done", func(t *testing.T) {
+		// GIVEN
+		branchLogicalSize.delay = time.Hour
+		t.Cleanup(func() { branchLogicalSize.delay = 0 })
+
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		// WHEN
+		_, err := waitBranchLogicalSize(ctx, client, created, time.Minute)
+
+		// THEN
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("the context error is expected, got: %v", err)
+		}
+	})
+
+	t.Run("shall not wait by default", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		if _, err := waitBranchLogicalSize(context.TODO(), client, created, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if cntGet != 0 {
+			t.Errorf("the branch shall not be polled, got: %d calls", cntGet)
+		}
+	})
+}
+
+func Test_resourceBranch_parentName(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID, parent := project.Project.ID, project.Branch
+
+	d := resourceBranch().TestResourceData()
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("name", "dev")
+	_ = d.Set("parent_id", parent.ID)
+
+	// WHEN
+	if err := resourceBranchCreate(context.TODO(), d, client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	if v := d.Get("parent_name").(string); v != parent.Name {
+		t.Errorf("unexpected parent name upon create. want: %s, got: %s", parent.Name, v)
+	}
+
+	// WHEN the parent is renamed outside terraform
+	if _, err := client.UpdateProjectBranch(projectID, parent.ID, neon.BranchUpdateRequest{
+		Branch: neon.BranchUpdateRequestBranch{Name: pointer("renamed")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := resourceBranchRead(context.TODO(), d, client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN
+	if v := d.Get("parent_name").(string); v != "renamed" {
+		t.Errorf("unexpected parent name upon read. want: renamed, got: %s", v)
+	}
+
+	diff := func(t *testing.T, parentName string) *terraform.InstanceDiff {
+		t.Helper()
+
+		r := resourceBranch()
+		state := &terraform.InstanceState{ID: d.Id(), Attributes: map[string]string{
+			"id":          d.Id(),
+			"project_id":  projectID,
+			"name":        "dev",
+			"parent_id":   parent.ID,
+			"parent_name": "renamed",
+		}}
+		o, err := r.Diff(context.TODO(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"project_id":  projectID,
+			"name":        "dev",
+			"parent_name": parentName,
+		}), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	t.Run("shall not replace the branch if the parent was renamed", func(t *testing.T) {
+		if got := diff(t, parent.Name); got != nil && got.RequiresNew() {
+			t.Errorf("the branch shall not be replaced, got: %v", got)
+		}
+	})
+
+	t.Run("shall replace the branch if the name is resolved to another branch", func(t *testing.T) {
+		// GIVEN
+		if _, err := client.CreateProjectBranch(projectID, &neon.CreateProjectBranchReqObj{
+			BranchCreateRequest: neon.BranchCreateRequest{
+				Branch: &neon.BranchCreateRequestBranch{Name: pointer("staging")},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		// WHEN
+		got := diff(t, "staging")
+
+		// THEN
+		if got == nil || !got.RequiresNew() {
+			t.Errorf("the branch shall be replaced, got: %v", got)
+		}
+	})
+}
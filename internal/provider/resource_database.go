@@ -21,6 +21,7 @@ func resourceDatabase() *schema.Resource {
 		ReadContext:   resourceDatabaseReadRetry,
 		UpdateContext: resourceDatabaseUpdateRetry,
 		DeleteContext: resourceDatabaseDeleteRetry,
+		CustomizeDiff: customizeDiffBranchName,
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
@@ -29,15 +30,25 @@ func resourceDatabase() *schema.Resource {
 				Description: "Project ID.",
 			},
 			"branch_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Branch ID.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"branch_id", "branch_name"},
+				Description:  "Branch ID.",
+			},
+			"branch_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"branch_id", "branch_name"},
+				Description: `Branch name. It's resolved to the branch ID upon plan, i.e. the resource is only replaced
+if the name refers to another branch than ` + "`branch_id`" + ` in the state.
+**Note** that it's the alternative to ` + "`branch_id`" + `.`,
 			},
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "Database name.",
+				Description: "Database name. The database is renamed in place, i.e. its data is retained.",
 			},
 			"owner_name": {
 				Type:        schema.TypeString,
@@ -63,12 +74,17 @@ func resourceDatabaseCreateRetry(ctx context.Context, d *schema.ResourceData, me
 func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "created Database")
 
+	branchID, err := branchIDFromConfig(d, meta.(sdkDatabase))
+	if err != nil {
+		return err
+	}
+
 	r := complexID{
 		ProjectID: d.Get("project_id").(string),
-		BranchID:  d.Get("branch_id").(string),
+		BranchID:  branchID,
 		Name:      d.Get("name").(string),
 	}
-	resp, err := meta.(*neon.Client).CreateProjectBranchDatabase(
+	resp, err := meta.(sdkDatabase).CreateProjectBranchDatabase(
 		r.ProjectID, r.BranchID, neon.DatabaseCreateRequest{
 			Database: neon.DatabaseCreateRequestDatabase{
 				Name:      r.Name,
@@ -86,13 +102,13 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, meta in
 }
 
 func resourceDatabaseReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceDatabaseRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceDatabaseRead, ctx, d, meta)
 }
 
 func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "read Database")
 
-	resp, err := meta.(*neon.Client).GetProjectBranchDatabase(
+	resp, err := meta.(sdkDatabase).GetProjectBranchDatabase(
 		d.Get("project_id").(string), d.Get("branch_id").(string), d.Get("name").(string),
 	)
 	if err != nil {
@@ -109,12 +125,17 @@ func resourceDatabaseUpdateRetry(ctx context.Context, d *schema.ResourceData, me
 func resourceDatabaseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "update Database")
 
+	// the branch name is only stored in the state, the database is replaced if the branch changes
+	if !d.HasChange("name") {
+		return nil
+	}
+
 	r, err := parseComplexID(d.Id())
 	if err != nil {
 		panic(err)
 	}
 
-	resp, err := meta.(*neon.Client).UpdateProjectBranchDatabase(
+	resp, err := meta.(sdkDatabase).UpdateProjectBranchDatabase(
 		r.ProjectID, r.BranchID, r.Name,
 		neon.DatabaseUpdateRequest{
 			Database: neon.DatabaseUpdateRequestDatabase{
@@ -138,7 +159,7 @@ func resourceDatabaseDeleteRetry(ctx context.Context, d *schema.ResourceData, me
 
 func resourceDatabaseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "delete Database")
-	if _, err := meta.(*neon.Client).DeleteProjectBranchDatabase(
+	if _, err := meta.(sdkDatabase).DeleteProjectBranchDatabase(
 		d.Get("project_id").(string),
 		d.Get("branch_id").(string),
 		d.Get("name").(string),
@@ -166,3 +187,11 @@ func resourceDatabaseImport(ctx context.Context, d *schema.ResourceData, meta in
 
 	return []*schema.ResourceData{d}, nil
 }
+
+type sdkDatabase interface {
+	sdkBranchLister
+	CreateProjectBranchDatabase(string, string, neon.DatabaseCreateRequest) (neon.DatabaseOperations, error)
+	GetProjectBranchDatabase(string, string, string) (neon.DatabaseResponse, error)
+	UpdateProjectBranchDatabase(string, string, string, neon.DatabaseUpdateRequest) (neon.DatabaseOperations, error)
+	DeleteProjectBranchDatabase(string, string, string) (neon.DatabaseOperations, error)
+}
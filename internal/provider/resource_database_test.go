@@ -0,0 +1,167 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceDatabaseCRUD(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the branch referenced by name
+	meta := &stubDatabase{Branches: []neon.Branch{{ID: "br-foo", Name: "dev"}}}
+	d := schema.TestResourceDataRaw(t, resourceDatabase().Schema, map[string]interface{}{
+		"project_id":  "foo",
+		"branch_name": "dev",
+		"name":        "bar",
+		"owner_name":  "qux",
+	})
+
+	// WHEN the database is created
+	assert.NoError(t, resourceDatabaseCreate(context.TODO(), d, meta))
+
+	// THEN the branch name is resolved to the ID
+	assert.Equal(t, "foo/br-foo/bar", d.Id())
+	assert.Equal(t, "br-foo", d.Get("branch_id"))
+	assert.Equal(t, neon.Database{BranchID: "br-foo", Name: "bar", OwnerName: "qux"}, meta.Databases["bar"])
+
+	// WHEN the owner changed outside of terraform
+	meta.Databases["bar"] = neon.Database{BranchID: "br-foo", Name: "bar", OwnerName: "quux"}
+
+	// THEN the drift is read
+	assert.NoError(t, resourceDatabaseRead(context.TODO(), d, meta))
+	assert.Equal(t, "quux", d.Get("owner_name"))
+
+	// WHEN the database is deleted
+	assert.NoError(t, resourceDatabaseDelete(context.TODO(), d, meta))
+
+	// THEN it's removed
+	assert.Empty(t, d.Id())
+	assert.Empty(t, meta.Databases)
+}
+
+func TestResourceDatabaseUpdate_rename(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the database
+	meta := &stubDatabase{
+		Databases: map[string]neon.Database{"bar": {BranchID: "br-foo", Name: "bar", OwnerName: "qux"}},
+	}
+	d := schema.TestResourceDataRaw(t, resourceDatabase().Schema, map[string]interface{}{
+		"project_id": "foo",
+		"branch_id":  "br-foo",
+		"name":       "baz",
+		"owner_name": "qux",
+	})
+	d.SetId("foo/br-foo/bar")
+
+	// THEN the rename is planned as the in-place update
+	assert.False(t, resourceDatabase().Schema["name"].ForceNew)
+
+	// WHEN the database is renamed
+	assert.NoError(t, resourceDatabaseUpdate(context.TODO(), d, meta))
+
+	// THEN the database is updated, not re-created
+	assert.Equal(t, "foo/br-foo/baz", d.Id())
+	assert.Equal(t, map[string]neon.Database{"baz": {BranchID: "br-foo", Name: "baz", OwnerName: "qux"}}, meta.Databases)
+	assert.NoError(t, resourceDatabaseRead(context.TODO(), d, meta))
+}
+
+func TestResourceDatabaseCreate_branchNotFound(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name string
+		meta *stubDatabase
+	}{
+		{
+			name: "no branch with the given name",
+			meta: &stubDatabase{Branches: []neon.Branch{{ID: "br-foo", Name: "main"}}},
+		},
+		{
+			name: "API error",
+			meta: &stubDatabase{err: errors.New("foo")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceDatabase().Schema, map[string]interface{}{
+				"project_id":  "foo",
+				"branch_name": "dev",
+				"name":        "bar",
+				"owner_name":  "qux",
+			})
+
+			assert.Error(t, resourceDatabaseCreate(context.TODO(), d, tt.meta))
+			assert.Empty(t, d.Id())
+			assert.Empty(t, tt.meta.Databases)
+		})
+	}
+}
+
+func TestResourceDatabaseCustomizeDiff_branchName(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the database on the branch br-foo
+	meta := &stubDatabase{Branches: []neon.Branch{{ID: "br-foo", Name: "dev"}, {ID: "br-bar", Name: "staging"}}}
+	newState := func(branchName string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "foo/br-foo/bar",
+			Attributes: map[string]string{
+				"id":          "foo/br-foo/bar",
+				"project_id":  "foo",
+				"branch_id":   "br-foo",
+				"branch_name": branchName,
+				"name":        "bar",
+				"owner_name":  "qux",
+			},
+		}
+	}
+	diff := func(t *testing.T, state *terraform.InstanceState, branch map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+		cfg := map[string]interface{}{"project_id": "foo", "name": "bar", "owner_name": "qux"}
+		for k, v := range branch {
+			cfg[k] = v
+		}
+		o, err := resourceDatabase().Diff(context.TODO(), state, terraform.NewResourceConfigRaw(cfg), meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	t.Run("shall not replace the database when the branch ID is switched to the name of the same branch", func(t *testing.T) {
+		got := diff(t, newState(""), map[string]interface{}{"branch_name": "dev"})
+		assert.False(t, got.RequiresNew())
+	})
+
+	t.Run("shall not replace the database when the branch name is switched to the ID of the same branch", func(t *testing.T) {
+		got := diff(t, newState("dev"), map[string]interface{}{"branch_id": "br-foo"})
+		assert.False(t, got.RequiresNew())
+	})
+
+	t.Run("shall replace the database when the name refers to another branch", func(t *testing.T) {
+		got := diff(t, newState(""), map[string]interface{}{"branch_name": "staging"})
+		assert.True(t, got.RequiresNew())
+		assert.True(t, got.Attributes["branch_id"].RequiresNew)
+	})
+}
@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"errors"
+	"net/http"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -23,6 +25,7 @@ func resourceEndpoint() *schema.Resource {
 		ReadContext:   resourceEndpointReadRetry,
 		UpdateContext: resourceEndpointUpdateRetry,
 		DeleteContext: resourceEndpointDeleteRetry,
+		CustomizeDiff: resourceEndpointCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -58,34 +61,55 @@ func resourceEndpoint() *schema.Resource {
 				Computed:    true,
 				Description: "Endpoint URI.",
 			},
-			"region_id": schemaRegionID,
+			"console_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `Link to the Neon console page of the branch to which the endpoint belongs,
+the page lists the branch's computes.`,
+			},
+			"region_id":               schemaRegionID,
+			"skip_region_validation":  schemaSkipRegionValidation,
+			"ignore_external_changes": schemaIgnoreExternalChanges("autoscaling", "suspend_timeout", "pooler"),
 			"autoscaling_limit_min_cu": {
-				Type:         schema.TypeFloat,
-				ValidateFunc: validateAutoscallingLimit,
-				Optional:     true,
-				Computed:     true,
+				Type:             schema.TypeFloat,
+				ValidateFunc:     validateAutoscallingLimit,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("autoscaling"),
 			},
 			"autoscaling_limit_max_cu": {
-				Type:         schema.TypeFloat,
-				ValidateFunc: validateAutoscallingLimit,
-				Optional:     true,
-				Computed:     true,
+				Type:             schema.TypeFloat,
+				ValidateFunc:     validateAutoscallingLimit,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("autoscaling"),
+			},
+			"compute_units": {
+				Type:          schema.TypeFloat,
+				ValidateFunc:  validateAutoscallingLimit,
+				Optional:      true,
+				ConflictsWith: []string{"autoscaling_limit_min_cu", "autoscaling_limit_max_cu"},
+				Description: `Size of the fixed-size compute, i.e. the shorthand to set both ` +
+					"`autoscaling_limit_min_cu` and `autoscaling_limit_max_cu`" + ` to the same value.`,
 			},
 			"pg_settings": {
-				Type:     schema.TypeMap,
-				Optional: true,
+				Type:             schema.TypeMap,
+				Optional:         true,
+				DiffSuppressFunc: suppressPgSettingsDiff,
 			},
 			"pooler_enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("pooler"),
 				Description: `Activate connection pooling.
 See details: https://neon.tech/docs/connect/connection-pooling`,
 			},
 			"pooler_mode": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("pooler"),
 				Description: `Mode of connections pooling.
 See details: https://neon.tech/docs/connect/connection-pooling`,
 			},
@@ -99,6 +123,21 @@ See details: https://neon.tech/docs/connect/connection-pooling`,
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"creation_source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Source of the endpoint creation, e.g. console.",
+			},
+			"compute_release_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Release version of the compute.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the endpoint creation, RFC3339.",
+			},
 			"compute_provisioner": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -122,25 +161,80 @@ Specify the k8s-neonvm provisioner to create a compute endpoint that supports Au
 				},
 			},
 			"suspend_timeout_seconds": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Computed: true,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("suspend_timeout"),
 				Description: `Duration of inactivity in seconds after which the compute endpoint is automatically suspended.
 The value 0 means use the global default.
 The value -1 means never suspend. The default value is 300 seconds (5 minutes).
 The maximum value is 604800 seconds (1 week)`,
 			},
+			"ensure_active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to start the endpoint, if it's suspended, upon create and update,
+and wait until the endpoint is active.
+**Note** that the endpoint is only started when terraform applies the change to the resource, i.e. the endpoint
+which got suspended after the apply is not started by the next apply without changes.`,
+			},
+			"drain_timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: intValidationNotNegative,
+				Description: `Maximum duration in seconds to wait for the endpoint to become idle before it's deleted, or disabled,
+e.g. to let the running migrations finish. The endpoint becomes idle once it's suspended after its connections
+dropped, see ` + "`suspend_timeout_seconds`" + `. The endpoint is deleted, or disabled when the timeout elapses
+regardless of its state. The value 0 means no wait.`,
+			},
+			"restart_triggered_by": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: `Arbitrary map of values which triggers the endpoint's restart when changed, e.g. to apply
+the settings which require restart, or to roll the compute onto the new release version.
+**Note** that the suspended endpoint is not restarted, it picks the changes up upon the next start.`,
+			},
+			"current_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Endpoint state, e.g. active, idle, or init.",
+			},
+			"pending_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State the endpoint transitions to. Empty if the endpoint is not in transition.",
+			},
+			"settle_timeout_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: intValidationNotNegative,
+				Description: `Maximum duration in seconds to wait upon read for the endpoint in transition, e.g. starting,
+to settle, i.e. to reach its pending state. The state is read regardless of the endpoint's state when
+the timeout elapses. The value 0 means no wait.`,
+			},
 		},
 	}
 }
 
-func updateStateEndpoint(d *schema.ResourceData, v neon.Endpoint) error {
+func updateStateEndpoint(d *schema.ResourceData, v neon.Endpoint, console string) error {
+	if v.BranchID != "" {
+		if err := d.Set("branch_id", v.BranchID); err != nil {
+			return err
+		}
+	}
 	if err := d.Set("type", v.Type); err != nil {
 		return err
 	}
 	if err := d.Set("host", v.Host); err != nil {
 		return err
 	}
+	if err := d.Set("console_url", consoleURL(console, "projects", v.ProjectID, "branches", v.BranchID)); err != nil {
+		return err
+	}
 	if err := d.Set("region_id", v.RegionID); err != nil {
 		return err
 	}
@@ -150,11 +244,24 @@ func updateStateEndpoint(d *schema.ResourceData, v neon.Endpoint) error {
 	if err := d.Set("autoscaling_limit_max_cu", float64(v.AutoscalingLimitMaxCu)); err != nil {
 		return err
 	}
-	if v.Settings.PgSettings != nil {
-		if err := d.Set("pg_settings", pgSettingsToMap(*v.Settings.PgSettings)); err != nil {
+	// the fixed size is reset to detect the autoscaling limits changed outside terraform
+	if _, ok := d.GetOk("compute_units"); ok {
+		var cu float64
+		if v.AutoscalingLimitMinCu == v.AutoscalingLimitMaxCu {
+			cu = float64(v.AutoscalingLimitMaxCu)
+		}
+		if err := d.Set("compute_units", cu); err != nil {
 			return err
 		}
 	}
+	// pg_settings are always set to detect the settings removed outside terraform
+	pgSettings := map[string]interface{}{}
+	if v.Settings.PgSettings != nil {
+		pgSettings = pgSettingsToMap(*v.Settings.PgSettings)
+	}
+	if err := d.Set("pg_settings", pgSettings); err != nil {
+		return err
+	}
 	if err := d.Set("pooler_enabled", v.PoolerEnabled); err != nil {
 		return err
 	}
@@ -170,9 +277,62 @@ func updateStateEndpoint(d *schema.ResourceData, v neon.Endpoint) error {
 	if err := d.Set("compute_provisioner", string(v.Provisioner)); err != nil {
 		return err
 	}
+	if err := d.Set("creation_source", v.CreationSource); err != nil {
+		return err
+	}
+	if err := d.Set("compute_release_version", v.ComputeReleaseVersion); err != nil {
+		return err
+	}
+	if !v.CreatedAt.IsZero() {
+		if err := d.Set("created_at", v.CreatedAt.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
 	if err := d.Set("suspend_timeout_seconds", int64(v.SuspendTimeoutSeconds)); err != nil {
 		return err
 	}
+	if err := d.Set("current_state", string(v.CurrentState)); err != nil {
+		return err
+	}
+	var pendingState string
+	if v.PendingState != nil {
+		pendingState = string(*v.PendingState)
+	}
+	if err := d.Set("pending_state", pendingState); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceEndpointCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffRegionID(ctx, d, meta.(sdkRegions)); err != nil {
+		return err
+	}
+	if err := customizeDiffComputeUnits(d); err != nil {
+		return err
+	}
+	return customizeDiffAutoscalingLimitMax(ctx, d, meta.(sdkAccountLimits), "autoscaling_limit_max_cu")
+}
+
+// customizeDiffComputeUnits plans both autoscaling limits given the size of the fixed-size compute.
+func customizeDiffComputeUnits(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("compute_units") {
+		return nil
+	}
+	cu, _ := d.Get("compute_units").(float64)
+	if cu == 0 {
+		return nil
+	}
+	if v, ok := d.Get("ignore_external_changes").(*schema.Set); ok && d.Id() != "" && v.Contains("autoscaling") {
+		return nil
+	}
+	for _, k := range []string{"autoscaling_limit_min_cu", "autoscaling_limit_max_cu"} {
+		if d.Get(k).(float64) != cu {
+			if err := d.SetNew(k, cu); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -184,22 +344,30 @@ func resourceEndpointCreate(ctx context.Context, d *schema.ResourceData, meta in
 	tflog.Trace(ctx, "created Endpoint")
 
 	cfg := neon.EndpointCreateRequestEndpoint{
-		BranchID:              d.Get("branch_id").(string),
-		Type:                  neon.EndpointType(d.Get("type").(string)),
-		RegionID:              pointer(d.Get("region_id").(string)),
-		PoolerEnabled:         pointer(d.Get("pooler_enabled").(bool)),
-		PoolerMode:            pointer(neon.EndpointPoolerMode(d.Get("pooler_mode").(string))),
-		Disabled:              pointer(d.Get("disabled").(bool)),
-		Provisioner:           pointer(neon.Provisioner(d.Get("compute_provisioner").(string))),
-		SuspendTimeoutSeconds: pointer(neon.SuspendTimeoutSeconds(d.Get("suspend_timeout_seconds").(int))),
+		BranchID:      d.Get("branch_id").(string),
+		Type:          neon.EndpointType(d.Get("type").(string)),
+		RegionID:      pointer(d.Get("region_id").(string)),
+		PoolerEnabled: pointer(d.Get("pooler_enabled").(bool)),
+		PoolerMode:    pointer(neon.EndpointPoolerMode(d.Get("pooler_mode").(string))),
+		Disabled:      pointer(d.Get("disabled").(bool)),
+		Provisioner:   pointer(neon.Provisioner(d.Get("compute_provisioner").(string))),
+	}
+
+	if isDefinedInConfig(d, "suspend_timeout_seconds") {
+		cfg.SuspendTimeoutSeconds = pointer(neon.SuspendTimeoutSeconds(d.Get("suspend_timeout_seconds").(int)))
 	}
 
-	if v, ok := d.GetOk("autoscaling_limit_min_cu"); ok {
-		cfg.AutoscalingLimitMinCu = pointer(neon.ComputeUnit(v.(float64)))
+	if isDefinedInConfig(d, "autoscaling_limit_min_cu") {
+		cfg.AutoscalingLimitMinCu = pointer(neon.ComputeUnit(d.Get("autoscaling_limit_min_cu").(float64)))
 	}
 
-	if v, ok := d.GetOk("autoscaling_limit_max_cu"); ok {
-		cfg.AutoscalingLimitMaxCu = pointer(neon.ComputeUnit(v.(float64)))
+	if isDefinedInConfig(d, "autoscaling_limit_max_cu") {
+		cfg.AutoscalingLimitMaxCu = pointer(neon.ComputeUnit(d.Get("autoscaling_limit_max_cu").(float64)))
+	}
+
+	if isDefinedInConfig(d, "compute_units") {
+		cu := neon.ComputeUnit(d.Get("compute_units").(float64))
+		cfg.AutoscalingLimitMinCu, cfg.AutoscalingLimitMaxCu = &cu, &cu
 	}
 
 	if v, ok := d.GetOk("pg_settings"); ok {
@@ -208,7 +376,7 @@ func resourceEndpointCreate(ctx context.Context, d *schema.ResourceData, meta in
 		}
 	}
 
-	resp, err := meta.(*neon.Client).CreateProjectEndpoint(
+	resp, err := meta.(sdkEndpoint).CreateProjectEndpoint(
 		d.Get("project_id").(string),
 		neon.EndpointCreateRequest{Endpoint: cfg},
 	)
@@ -218,17 +386,29 @@ func resourceEndpointCreate(ctx context.Context, d *schema.ResourceData, meta in
 
 	d.SetId(resp.Endpoint.ID)
 
-	return updateStateEndpoint(d, resp.EndpointResponse.Endpoint)
+	endpoint := resp.EndpointResponse.Endpoint
+	if d.HasChange("restart_triggered_by") {
+		if endpoint, err = restartEndpoint(ctx, meta.(sdkEndpoint), endpoint); err != nil {
+			return err
+		}
+	}
+	if d.Get("ensure_active").(bool) {
+		if endpoint, err = ensureEndpointActive(ctx, meta.(sdkEndpoint), endpoint); err != nil {
+			return err
+		}
+	}
+
+	return updateStateEndpoint(d, endpoint, consoleBaseURL(meta))
 }
 
 func resourceEndpointReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceEndpointRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceEndpointRead, ctx, d, meta)
 }
 
 func resourceEndpointRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "read Endpoint")
 
-	resp, err := meta.(*neon.Client).GetProjectEndpoint(
+	resp, err := meta.(sdkEndpoint).GetProjectEndpoint(
 		d.Get("project_id").(string),
 		d.Id(),
 	)
@@ -236,10 +416,23 @@ func resourceEndpointRead(ctx context.Context, d *schema.ResourceData, meta inte
 		return err
 	}
 
-	return updateStateEndpoint(d, resp.Endpoint)
+	endpoint, err := waitEndpointSettled(ctx, meta.(sdkEndpoint), resp.Endpoint, endpointSettleTimeout(d))
+	if err != nil {
+		return err
+	}
+
+	return updateStateEndpoint(d, endpoint, consoleBaseURL(meta))
 }
 
 func resourceEndpointUpdateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the drain is awaited once before the update, i.e. the retries of the update don't wait for it again
+	if d.HasChange("disabled") && d.Get("disabled").(bool) {
+		if err := waitEndpointIdle(
+			ctx, meta.(sdkEndpoint), d.Get("project_id").(string), d.Id(), endpointDrainTimeout(d),
+		); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return projectReadiness.Retry(resourceEndpointUpdate, ctx, d, meta)
 }
 
@@ -257,13 +450,18 @@ func resourceEndpointUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		SuspendTimeoutSeconds: pointer(neon.SuspendTimeoutSeconds(d.Get("suspend_timeout_seconds").(int))),
 	}
 
-	if v, ok := d.GetOk("pg_settings"); ok {
+	if d.HasChange("pg_settings") {
+		pgSettings := mapToPgSettings(d.Get("pg_settings").(map[string]interface{}))
+		if pgSettings == nil {
+			// resets the settings removed from the configuration
+			pgSettings = &neon.PgSettingsData{}
+		}
 		cfg.Settings = &neon.EndpointSettingsData{
-			PgSettings: mapToPgSettings(v.(map[string]interface{})),
+			PgSettings: pgSettings,
 		}
 	}
 
-	resp, err := meta.(*neon.Client).UpdateProjectEndpoint(
+	resp, err := meta.(sdkEndpoint).UpdateProjectEndpoint(
 		d.Get("project_id").(string),
 		d.Id(),
 		neon.EndpointUpdateRequest{Endpoint: cfg},
@@ -271,7 +469,156 @@ func resourceEndpointUpdate(ctx context.Context, d *schema.ResourceData, meta in
 	if err != nil {
 		return err
 	}
-	return updateStateEndpoint(d, resp.EndpointResponse.Endpoint)
+
+	endpoint := resp.EndpointResponse.Endpoint
+	if d.Get("ensure_active").(bool) {
+		if endpoint, err = ensureEndpointActive(ctx, meta.(sdkEndpoint), endpoint); err != nil {
+			return err
+		}
+	}
+
+	return updateStateEndpoint(d, endpoint, consoleBaseURL(meta))
+}
+
+var endpointActivation = delay{
+	delay:  1 * time.Second,
+	maxCnt: 120,
+}
+
+// ensureEndpointActive starts the endpoint if it's not active and waits until it's active.
+func ensureEndpointActive(ctx context.Context, client sdkEndpoint, endpoint neon.Endpoint) (neon.Endpoint, error) {
+	if endpoint.Disabled {
+		tflog.Warn(ctx, "disabled Endpoint cannot be activated", map[string]interface{}{"endpointID": endpoint.ID})
+		return endpoint, nil
+	}
+
+	for i := uint8(0); i < endpointActivation.maxCnt; i++ {
+		resp, err := client.GetProjectEndpoint(endpoint.ProjectID, endpoint.ID)
+		if err != nil {
+			return endpoint, err
+		}
+		endpoint = resp.Endpoint
+
+		if endpoint.CurrentState == neon.EndpointStateActive {
+			return endpoint, nil
+		}
+
+		if endpoint.PendingState == nil || *endpoint.PendingState != neon.EndpointStateActive {
+			tflog.Debug(ctx, "start Endpoint", map[string]interface{}{"endpointID": endpoint.ID})
+			if _, err := client.StartProjectEndpoint(endpoint.ProjectID, endpoint.ID); err != nil {
+				// the endpoint is locked while another operation is running
+				if e, ok := err.(neon.Error); !ok || e.HTTPCode != http.StatusLocked {
+					return endpoint, err
+				}
+			}
+		}
+
+		if err := sleep(ctx, endpointActivation.delay); err != nil {
+			return endpoint, err
+		}
+	}
+
+	return endpoint, errors.New("endpoint " + endpoint.ID + " is not active after " +
+		(time.Duration(endpointActivation.maxCnt) * endpointActivation.delay).String())
+}
+
+// restartEndpoint restarts the active endpoint. The endpoint which is not active is returned unchanged.
+func restartEndpoint(ctx context.Context, client sdkEndpoint, endpoint neon.Endpoint) (neon.Endpoint, error) {
+	if endpoint.Disabled || endpoint.CurrentState != neon.EndpointStateActive {
+		tflog.Info(ctx, "Endpoint is not active, the restart is skipped", map[string]interface{}{
+			"endpointID": endpoint.ID, "state": string(endpoint.CurrentState),
+		})
+		return endpoint, nil
+	}
+
+	tflog.Debug(ctx, "restart Endpoint", map[string]interface{}{"endpointID": endpoint.ID})
+	resp, err := client.RestartProjectEndpoint(endpoint.ProjectID, endpoint.ID)
+	if err != nil {
+		return endpoint, err
+	}
+	return resp.EndpointResponse.Endpoint, nil
+}
+
+var endpointDrain = delay{
+	delay: 5 * time.Second,
+}
+
+func endpointDrainTimeout(d *schema.ResourceData) time.Duration {
+	return time.Duration(d.Get("drain_timeout_seconds").(int)) * time.Second
+}
+
+// waitEndpointIdle waits up to the timeout until the endpoint is idle, i.e. it's suspended after its connections
+// dropped. It doesn't fail if the endpoint remains active after the timeout.
+func waitEndpointIdle(
+	ctx context.Context, client sdkEndpoint, projectID, endpointID string, timeout time.Duration,
+) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	idle := func() (bool, error) {
+		resp, err := client.GetProjectEndpoint(projectID, endpointID)
+		if err != nil {
+			return false, err
+		}
+		return resp.Endpoint.CurrentState != neon.EndpointStateActive, nil
+	}
+
+	done, err := idle()
+	if err != nil || done {
+		return err
+	}
+	tflog.Debug(ctx, "wait for Endpoint to drain", map[string]interface{}{"endpointID": endpointID})
+	if done, err = poll(ctx, endpointDrain.delay, timeout, idle); err != nil || done {
+		return err
+	}
+
+	tflog.Warn(ctx, "Endpoint is still active after the drain timeout", map[string]interface{}{
+		"endpointID": endpointID, "timeout": timeout.String(),
+	})
+	return nil
+}
+
+var endpointSettle = delay{
+	delay: 2 * time.Second,
+}
+
+func endpointSettleTimeout(d *schema.ResourceData) time.Duration {
+	return time.Duration(d.Get("settle_timeout_seconds").(int)) * time.Second
+}
+
+// endpointInTransition reports whether the endpoint is starting, or transitions to another state.
+func endpointInTransition(endpoint neon.Endpoint) bool {
+	return endpoint.CurrentState == neon.EndpointStateInit ||
+		(endpoint.PendingState != nil && *endpoint.PendingState != endpoint.CurrentState)
+}
+
+// waitEndpointSettled waits up to the timeout until the endpoint is not in transition, and returns its latest state.
+// It doesn't fail if the endpoint remains in transition after the timeout.
+func waitEndpointSettled(
+	ctx context.Context, client sdkEndpoint, endpoint neon.Endpoint, timeout time.Duration,
+) (neon.Endpoint, error) {
+	if timeout <= 0 || !endpointInTransition(endpoint) {
+		return endpoint, nil
+	}
+
+	tflog.Debug(ctx, "wait for Endpoint to settle", map[string]interface{}{"endpointID": endpoint.ID})
+	done, err := poll(ctx, endpointSettle.delay, timeout, func() (bool, error) {
+		resp, err := client.GetProjectEndpoint(endpoint.ProjectID, endpoint.ID)
+		if err != nil {
+			return false, err
+		}
+		endpoint = resp.Endpoint
+		return !endpointInTransition(endpoint), nil
+	})
+	if err != nil || done {
+		return endpoint, err
+	}
+
+	tflog.Warn(ctx, "Endpoint is still in transition after the settle timeout", map[string]interface{}{
+		"endpointID": endpoint.ID, "timeout": timeout.String(),
+	})
+	return endpoint, nil
 }
 
 func resourceEndpointImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
@@ -279,13 +626,13 @@ func resourceEndpointImport(ctx context.Context, d *schema.ResourceData, meta in
 ) {
 	tflog.Trace(ctx, "import Endpoint")
 
-	resp, err := meta.(*neon.Client).ListProjects(nil, nil, nil, nil)
+	resp, err := meta.(sdkEndpoint).ListProjects(nil, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, project := range resp.Projects {
-		r, err := meta.(*neon.Client).ListProjectEndpoints(project.ID)
+		r, err := meta.(sdkEndpoint).ListProjectEndpoints(project.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -295,6 +642,18 @@ func resourceEndpointImport(ctx context.Context, d *schema.ResourceData, meta in
 				if err := d.Set("project_id", project.ID); err != nil {
 					return nil, err
 				}
+				if err := d.Set("ensure_active", false); err != nil {
+					return nil, err
+				}
+				if err := d.Set("drain_timeout_seconds", 0); err != nil {
+					return nil, err
+				}
+				if err := d.Set("settle_timeout_seconds", 0); err != nil {
+					return nil, err
+				}
+				if err := d.Set("skip_region_validation", false); err != nil {
+					return nil, err
+				}
 				if err := resourceEndpointRead(ctx, d, meta); err != nil {
 					return nil, err
 				}
@@ -308,14 +667,42 @@ func resourceEndpointImport(ctx context.Context, d *schema.ResourceData, meta in
 }
 
 func resourceEndpointDeleteRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the drain is awaited once before the deletion, i.e. the retries of the deletion don't wait for it again
+	if err := waitEndpointIdle(
+		ctx, meta.(sdkEndpoint), d.Get("project_id").(string), d.Id(), endpointDrainTimeout(d),
+	); err != nil {
+		return diag.FromErr(err)
+	}
 	return projectReadiness.Retry(resourceEndpointDelete, ctx, d, meta)
 }
 
 func resourceEndpointDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "delete Endpoint")
-	if _, err := meta.(*neon.Client).DeleteProjectEndpoint(d.Get("project_id").(string), d.Id()); err != nil {
+	client := meta.(sdkEndpoint)
+	projectID := d.Get("project_id").(string)
+
+	if _, err := client.DeleteProjectEndpoint(projectID, d.Id()); err != nil {
 		return err
 	}
+
+	if err := waitDeleted(ctx, func() error {
+		_, err := client.GetProjectEndpoint(projectID, d.Id())
+		return err
+	}); err != nil {
+		return err
+	}
+
 	d.SetId("")
-	return updateStateEndpoint(d, neon.Endpoint{})
+	return updateStateEndpoint(d, neon.Endpoint{}, consoleBaseURL(meta))
+}
+
+type sdkEndpoint interface {
+	ListProjects(*string, *int, *string, *string) (neon.ListProjectsRespObj, error)
+	ListProjectEndpoints(string) (neon.EndpointsResponse, error)
+	CreateProjectEndpoint(string, neon.EndpointCreateRequest) (neon.EndpointOperations, error)
+	GetProjectEndpoint(string, string) (neon.EndpointResponse, error)
+	UpdateProjectEndpoint(string, string, neon.EndpointUpdateRequest) (neon.EndpointOperations, error)
+	StartProjectEndpoint(string, string) (neon.EndpointOperations, error)
+	RestartProjectEndpoint(string, string) (neon.EndpointOperations, error)
+	DeleteProjectEndpoint(string, string) (neon.EndpointOperations, error)
 }
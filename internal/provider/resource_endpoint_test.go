@@ -0,0 +1,483 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+type httpClientStubFn func(r *http.Request) (*http.Response, error)
+
+func (f httpClientStubFn) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func newHTTPResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func Test_ensureEndpointActive(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := endpointActivation.delay
+	endpointActivation.delay = 0
+	t.Cleanup(func() { endpointActivation.delay = defaultDelay })
+
+	t.Run("shall start the idle endpoint and wait until it's active", func(t *testing.T) {
+		// GIVEN
+		var cntGet, cntStart int
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				switch {
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/start"):
+					cntStart++
+					return newHTTPResponse(http.StatusOK, `{"endpoint":{"id":"ep-foo"}}`), nil
+				case r.Method == http.MethodGet:
+					cntGet++
+					switch cntGet {
+					case 1:
+						return newHTTPResponse(http.StatusOK,
+							`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"idle"}}`), nil
+					case 2:
+						return newHTTPResponse(http.StatusOK,
+							`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"idle","pending_state":"active"}}`), nil
+					}
+					return newHTTPResponse(http.StatusOK,
+						`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"active"}}`), nil
+				}
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				return nil, nil
+			}),
+		})
+
+		// WHEN
+		got, err := ensureEndpointActive(context.TODO(), client, neon.Endpoint{ID: "ep-foo", ProjectID: "bar"})
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.CurrentState != neon.EndpointStateActive {
+			t.Errorf("unexpected endpoint state: %s", got.CurrentState)
+		}
+		if cntStart != 1 {
+			t.Errorf("the endpoint shall be started once, got: %d", cntStart)
+		}
+	})
+
+	t.Run("shall skip disabled endpoint", func(t *testing.T) {
+		// WHEN
+		got, err := ensureEndpointActive(context.TODO(), nil, neon.Endpoint{ID: "ep-foo", Disabled: true})
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.CurrentState != "" {
+			t.Errorf("unexpected endpoint state: %s", got.CurrentState)
+		}
+	})
+
+	t.Run("shall stop waiting once the context is done", func(t *testing.T) {
+		// GIVEN
+		endpointActivation.delay = time.Hour
+		t.Cleanup(func() { endpointActivation.delay = 0 })
+
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method == http.MethodGet {
+					return newHTTPResponse(http.StatusOK,
+						`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"idle","pending_state":"active"}}`), nil
+				}
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				return nil, nil
+			}),
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		// WHEN
+		_, err := ensureEndpointActive(ctx, client, neon.Endpoint{ID: "ep-foo", ProjectID: "bar"})
+
+		// THEN
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("the context error is expected, got: %v", err)
+		}
+	})
+}
+
+func Test_waitEndpointIdle(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := endpointDrain.delay
+	endpointDrain.delay = 0
+	t.Cleanup(func() { endpointDrain.delay = defaultDelay })
+
+	newClient := func(cntGet *int, activeCnt int) *neon.Client {
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodGet {
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				*cntGet++
+				if activeCnt < 0 || *cntGet <= activeCnt {
+					return newHTTPResponse(http.StatusOK,
+						`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"active"}}`), nil
+				}
+				return newHTTPResponse(http.StatusOK,
+					`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"idle"}}`), nil
+			}),
+		})
+		return client
+	}
+
+	t.Run("shall wait until the endpoint is idle", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, 2)
+
+		// WHEN
+		err := waitEndpointIdle(context.TODO(), client, "bar", "ep-foo", time.Minute)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet != 3 {
+			t.Errorf("the endpoint shall be polled until it's idle, got: %d calls", cntGet)
+		}
+	})
+
+	t.Run("shall proceed when the timeout elapses", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		err := waitEndpointIdle(context.TODO(), client, "bar", "ep-foo", 10*time.Millisecond)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet == 0 {
+			t.Error("the endpoint shall be polled")
+		}
+	})
+
+	t.Run("shall stop waiting once the context is done", func(t *testing.T) {
+		// GIVEN
+		endpointDrain.delay = time.Hour
+		t.Cleanup(func() { endpointDrain.delay = 0 })
+
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		// WHEN
+		err := waitEndpointIdle(ctx, client, "bar", "ep-foo", time.Minute)
+
+		// THEN
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("the context error is expected, got: %v", err)
+		}
+	})
+
+	t.Run("shall not wait if the timeout is zero", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		err := waitEndpointIdle(context.TODO(), client, "bar", "ep-foo", 0)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet != 0 {
+			t.Errorf("the endpoint shall not be polled, got: %d calls", cntGet)
+		}
+	})
+}
+
+func Test_restartEndpoint(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name        string
+		endpoint    neon.Endpoint
+		wantRestart bool
+	}{
+		{
+			name:        "active endpoint",
+			endpoint:    neon.Endpoint{ID: "ep-foo", ProjectID: "bar", CurrentState: neon.EndpointStateActive},
+			wantRestart: true,
+		},
+		{
+			name:     "suspended endpoint",
+			endpoint: neon.Endpoint{ID: "ep-foo", ProjectID: "bar", CurrentState: neon.EndpointStateIdle},
+		},
+		{
+			name: "disabled endpoint",
+			endpoint: neon.Endpoint{
+				ID: "ep-foo", ProjectID: "bar", CurrentState: neon.EndpointStateActive, Disabled: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GIVEN
+			var cntRestart int
+			client, _ := neon.NewClient(neon.Config{
+				Key: "foo",
+				HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+					if r.Method != http.MethodPost || r.URL.Path != "/api/v2/projects/bar/endpoints/ep-foo/restart" {
+						t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+					}
+					cntRestart++
+					return newHTTPResponse(http.StatusOK,
+						`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"active"},"operations":[]}`), nil
+				}),
+			})
+
+			// WHEN
+			got, err := restartEndpoint(context.TODO(), client, tt.endpoint)
+
+			// THEN
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (cntRestart == 1) != tt.wantRestart {
+				t.Errorf("unexpected number of restarts: %d", cntRestart)
+			}
+			if got.ID != "ep-foo" {
+				t.Errorf("unexpected endpoint: %v", got.ID)
+			}
+		})
+	}
+}
+
+func Test_waitEndpointSettled(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := endpointSettle.delay
+	endpointSettle.delay = 0
+	t.Cleanup(func() { endpointSettle.delay = defaultDelay })
+
+	active := neon.EndpointStateActive
+	starting := neon.Endpoint{
+		ID: "ep-foo", ProjectID: "bar", CurrentState: neon.EndpointStateIdle, PendingState: &active,
+	}
+
+	newClient := func(cntGet *int, transitionCnt int) *neon.Client {
+		client, _ := neon.NewClient(neon.Config{
+			Key: "foo",
+			HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+				if r.Method != http.MethodGet {
+					t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				*cntGet++
+				if transitionCnt < 0 || *cntGet <= transitionCnt {
+					return newHTTPResponse(http.StatusOK,
+						`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"init","pending_state":"active"}}`,
+					), nil
+				}
+				return newHTTPResponse(http.StatusOK,
+					`{"endpoint":{"id":"ep-foo","project_id":"bar","current_state":"active"}}`), nil
+			}),
+		})
+		return client
+	}
+
+	t.Run("shall wait until the endpoint settles", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, 1)
+
+		// WHEN
+		got, err := waitEndpointSettled(context.TODO(), client, starting, time.Minute)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet != 2 {
+			t.Errorf("the endpoint shall be polled until it settles, got: %d calls", cntGet)
+		}
+		if got.CurrentState != neon.EndpointStateActive || got.PendingState != nil {
+			t.Errorf("unexpected endpoint state: %v", got.CurrentState)
+		}
+	})
+
+	t.Run("shall return the latest state when the timeout elapses", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		got, err := waitEndpointSettled(context.TODO(), client, starting, 10*time.Millisecond)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.CurrentState != neon.EndpointStateInit {
+			t.Errorf("unexpected endpoint state: %v", got.CurrentState)
+		}
+	})
+
+	t.Run("shall not wait if the endpoint is not in transition", func(t *testing.T) {
+		// GIVEN
+		var cntGet int
+		client := newClient(&cntGet, -1)
+
+		// WHEN
+		_, err := waitEndpointSettled(
+			context.TODO(), client, neon.Endpoint{ID: "ep-foo", CurrentState: neon.EndpointStateIdle}, time.Minute,
+		)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cntGet != 0 {
+			t.Errorf("the endpoint shall not be polled, got: %d calls", cntGet)
+		}
+	})
+}
+
+func Test_updateStateEndpoint(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	d := resourceEndpoint().TestResourceData()
+	_ = d.Set("branch_id", "br-foo")
+	_ = d.Set("pg_settings", map[string]interface{}{"max_connections": "100"})
+	_ = d.Set("autoscaling_limit_min_cu", 0.25)
+	_ = d.Set("suspend_timeout_seconds", 300)
+
+	// WHEN the settings were changed outside terraform
+	err := updateStateEndpoint(d, neon.Endpoint{
+		BranchID:              "br-bar",
+		AutoscalingLimitMinCu: 1,
+		AutoscalingLimitMaxCu: 2,
+		SuspendTimeoutSeconds: 600,
+	}, "")
+
+	// THEN
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := d.Get("branch_id").(string); v != "br-bar" {
+		t.Errorf("unexpected branch_id: %s", v)
+	}
+	if v := d.Get("pg_settings").(map[string]interface{}); len(v) != 0 {
+		t.Errorf("pg_settings removed outside terraform are expected to be empty, got: %v", v)
+	}
+	if v := d.Get("autoscaling_limit_min_cu").(float64); v != 1 {
+		t.Errorf("unexpected autoscaling_limit_min_cu: %v", v)
+	}
+	if v := d.Get("autoscaling_limit_max_cu").(float64); v != 2 {
+		t.Errorf("unexpected autoscaling_limit_max_cu: %v", v)
+	}
+	if v := d.Get("suspend_timeout_seconds").(int); v != 600 {
+		t.Errorf("unexpected suspend_timeout_seconds: %v", v)
+	}
+}
+
+func Test_customizeDiffComputeUnits(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	state := &terraform.InstanceState{
+		ID: "ep-cool-darkness-123456",
+		Attributes: map[string]string{
+			"id":                       "ep-cool-darkness-123456",
+			"project_id":               "shiny-wind-028834",
+			"branch_id":                "br-aged-salad-637688",
+			"autoscaling_limit_min_cu": "0.25",
+			"autoscaling_limit_max_cu": "2",
+		},
+	}
+
+	diff := func(t *testing.T, s *terraform.InstanceState, cfg map[string]interface{}) *terraform.InstanceDiff {
+		t.Helper()
+
+		r := resourceEndpoint()
+		r.CustomizeDiff = func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			return customizeDiffComputeUnits(d)
+		}
+
+		cfg["project_id"] = "shiny-wind-028834"
+		cfg["branch_id"] = "br-aged-salad-637688"
+		o, err := r.Diff(context.TODO(), s, terraform.NewResourceConfigRaw(cfg), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return o
+	}
+
+	t.Run("shall plan both autoscaling limits given the fixed size", func(t *testing.T) {
+		got := diff(t, state, map[string]interface{}{"compute_units": 1})
+		for _, k := range []string{"autoscaling_limit_min_cu", "autoscaling_limit_max_cu"} {
+			if v, ok := got.Attributes[k]; !ok || v.New != "1" {
+				t.Errorf("%s shall be planned to 1, got: %v", k, v)
+			}
+		}
+	})
+
+	t.Run("shall keep the autoscaling limits without the fixed size", func(t *testing.T) {
+		got := diff(t, state, map[string]interface{}{})
+		if got != nil {
+			for _, k := range []string{"autoscaling_limit_min_cu", "autoscaling_limit_max_cu"} {
+				if v, ok := got.Attributes[k]; ok {
+					t.Errorf("%s shall not be changed, got: %v", k, v)
+				}
+			}
+		}
+	})
+
+	t.Run("shall ignore the fixed size managed outside of terraform", func(t *testing.T) {
+		got := diff(t, state, map[string]interface{}{
+			"compute_units":           1,
+			"ignore_external_changes": []interface{}{"autoscaling"},
+		})
+		if _, ok := got.Attributes["autoscaling_limit_max_cu"]; ok {
+			t.Errorf("autoscaling_limit_max_cu shall not be changed")
+		}
+	})
+}
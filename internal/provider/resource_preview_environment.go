@@ -0,0 +1,389 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func resourcePreviewEnvironment() *schema.Resource {
+	return &schema.Resource{
+		Description: `Preview environment, i.e. the branch with the read-write endpoint, the role and the database
+owned by the role, provisioned in one shot. It's the shortcut for the preview database per pull request, e.g.
+` + "`neon_branch`, `neon_endpoint`, `neon_role` and `neon_database`" + ` combined.
+**Note** that all resources of the environment are deleted together with its branch.
+The environment is imported by the ID ` + "`{{.ProjectID}}/{{.BranchID}}`" + `, or
+` + "`{{.ProjectID}}/{{.BranchID}}/{{.RoleName}}/{{.DatabaseName}}`" + ` if the role or the database name is not "app".`,
+		SchemaVersion: 1,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePreviewEnvironmentImport,
+		},
+		CreateContext: resourcePreviewEnvironmentCreate,
+		ReadContext:   resourcePreviewEnvironmentReadRetry,
+		DeleteContext: resourcePreviewEnvironmentDeleteRetry,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Project ID.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Branch name, e.g. the name of the pull request's branch.",
+			},
+			"parent_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "ID of the branch to checkout. The project's default branch is used if not set.",
+			},
+			"role_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "app",
+				Description: "Role name.",
+			},
+			"database_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "app",
+				Description: "Database name.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Branch ID.",
+			},
+			"endpoint_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Read-write endpoint ID.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Read-write endpoint host.",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Role's password.",
+			},
+			"connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Connection URI to the database as the role.",
+			},
+			"connection_uri_pooler": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Connection URI to the database as the role using the connection pooler.",
+			},
+		},
+	}
+}
+
+type sdkPreviewEnvironment interface {
+	CreateProjectBranch(string, *neon.CreateProjectBranchReqObj) (neon.CreatedBranch, error)
+	GetProjectBranch(string, string) (neon.GetProjectBranchRespObj, error)
+	DeleteProjectBranch(string, string) (neon.BranchOperations, error)
+	GetProjectEndpoint(string, string) (neon.EndpointResponse, error)
+	ListProjectBranchEndpoints(string, string) (neon.EndpointsResponse, error)
+	CreateProjectBranchRole(string, string, neon.RoleCreateRequest) (neon.RoleOperations, error)
+	GetProjectBranchRolePassword(string, string, string) (neon.RolePasswordResponse, error)
+	CreateProjectBranchDatabase(string, string, neon.DatabaseCreateRequest) (neon.DatabaseOperations, error)
+	GetProjectBranchDatabase(string, string, string) (neon.DatabaseResponse, error)
+}
+
+func updateStatePreviewEnvironment(d *schema.ResourceData, branch neon.Branch, endpoint neon.Endpoint) error {
+	if err := d.Set("name", branch.Name); err != nil {
+		return err
+	}
+	if branch.ParentID != nil {
+		if err := d.Set("parent_id", *branch.ParentID); err != nil {
+			return err
+		}
+	}
+	if err := d.Set("branch_id", branch.ID); err != nil {
+		return err
+	}
+	if err := d.Set("endpoint_id", endpoint.ID); err != nil {
+		return err
+	}
+	if err := d.Set("host", endpoint.Host); err != nil {
+		return err
+	}
+
+	info := dbConnectionInfo{
+		userName: d.Get("role_name").(string),
+		pass:     d.Get("password").(string),
+		dbName:   d.Get("database_name").(string),
+		host:     endpoint.Host,
+	}
+	if err := d.Set("connection_uri", info.connectionURI()); err != nil {
+		return err
+	}
+	info.host = poolerHost(endpoint.Host)
+	return d.Set("connection_uri_pooler", info.connectionURI())
+}
+
+// resourcePreviewEnvironmentCreate provisions the environment step by step. Every step is retried separately
+// because the project remains locked while the previous step's operations run. The environment is stored
+// to the state as soon as the branch is created, hence it's marked as tainted and re-created if any of
+// the following steps fails.
+func resourcePreviewEnvironmentCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "created Preview Environment")
+
+	client := meta.(sdkPreviewEnvironment)
+	projectID := d.Get("project_id").(string)
+
+	var (
+		branch   neon.Branch
+		endpoint neon.Endpoint
+	)
+	if err := projectReadiness.retry(
+		func(context.Context, *schema.ResourceData, interface{}) error {
+			cfg := &neon.CreateProjectBranchReqObj{
+				BranchCreateRequest: neon.BranchCreateRequest{
+					Branch: &neon.BranchCreateRequestBranch{
+						Name: pointer(d.Get("name").(string)),
+					},
+					Endpoints: &[]neon.BranchCreateRequestEndpointOptions{
+						{Type: neon.EndpointTypeReadWrite},
+					},
+				},
+			}
+			if v, ok := d.GetOk("parent_id"); ok {
+				cfg.BranchCreateRequest.Branch.ParentID = pointer(v.(string))
+			}
+			if annotations := mergeAnnotations(meta, nil); len(annotations) > 0 {
+				cfg.AnnotationValue = &annotations
+			}
+
+			resp, err := client.CreateProjectBranch(projectID, cfg)
+			if err != nil {
+				return err
+			}
+			if len(resp.Endpoints) == 0 {
+				return errors.New("no endpoint was created for the branch " + resp.Branch.ID)
+			}
+			branch, endpoint = resp.Branch, resp.Endpoints[0]
+			return nil
+		}, ctx, d, meta,
+	); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(branch.ID)
+	// the branch and the endpoint are stored right away to keep track of them if any of the following steps fails
+	if err := updateStatePreviewEnvironment(d, branch, endpoint); err != nil {
+		return diag.FromErr(err)
+	}
+
+	roleName := d.Get("role_name").(string)
+	var password string
+	if err := projectReadiness.retry(
+		func(context.Context, *schema.ResourceData, interface{}) error {
+			resp, err := client.CreateProjectBranchRole(
+				projectID, branch.ID, neon.RoleCreateRequest{Role: neon.RoleCreateRequestRole{Name: roleName}},
+			)
+			if err != nil {
+				return err
+			}
+			if resp.Role.Password != nil {
+				password = *resp.Role.Password
+			}
+			return nil
+		}, ctx, d, meta,
+	); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if password == "" {
+		if err := projectReadiness.retry(
+			func(context.Context, *schema.ResourceData, interface{}) error {
+				resp, err := client.GetProjectBranchRolePassword(projectID, branch.ID, roleName)
+				if err != nil {
+					return err
+				}
+				password = resp.Password
+				return nil
+			}, ctx, d, meta,
+		); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("password", password); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := projectReadiness.retry(
+		func(context.Context, *schema.ResourceData, interface{}) error {
+			_, err := client.CreateProjectBranchDatabase(
+				projectID, branch.ID, neon.DatabaseCreateRequest{
+					Database: neon.DatabaseCreateRequestDatabase{
+						Name:      d.Get("database_name").(string),
+						OwnerName: roleName,
+					},
+				},
+			)
+			return err
+		}, ctx, d, meta,
+	); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(updateStatePreviewEnvironment(d, branch, endpoint))
+}
+
+func resourcePreviewEnvironmentReadRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.RetryRead(resourcePreviewEnvironmentRead, ctx, d, meta)
+}
+
+// resourcePreviewEnvironmentRead reads the environment. The missing endpoint and database do not fail the read,
+// because the environment is tracked by its branch, e.g. the tainted environment which creation was interrupted
+// is still deleted together with its branch.
+func resourcePreviewEnvironmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "read Preview Environment")
+
+	client := meta.(sdkPreviewEnvironment)
+	projectID := d.Get("project_id").(string)
+
+	branch, err := client.GetProjectBranch(projectID, d.Id())
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := previewEnvironmentEndpoint(client, projectID, d.Id(), d.Get("endpoint_id").(string))
+	switch {
+	case isNotFound(err):
+		tflog.Warn(ctx, "the endpoint of the Preview Environment is not found", map[string]interface{}{
+			"branchID": d.Id(), "endpointID": d.Get("endpoint_id"),
+		})
+	case err != nil:
+		return err
+	}
+
+	dbName := d.Get("database_name").(string)
+	if _, err := client.GetProjectBranchDatabase(projectID, d.Id(), dbName); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+		tflog.Warn(ctx, "the database of the Preview Environment is not found", map[string]interface{}{
+			"branchID": d.Id(), "database": dbName,
+		})
+	}
+
+	return updateStatePreviewEnvironment(d, branch.Branch, endpoint)
+}
+
+// previewEnvironmentEndpoint returns the environment's endpoint, or the branch's read-write endpoint
+// if the endpoint ID is unknown.
+func previewEnvironmentEndpoint(
+	client sdkPreviewEnvironment, projectID, branchID, endpointID string,
+) (neon.Endpoint, error) {
+	if endpointID != "" {
+		resp, err := client.GetProjectEndpoint(projectID, endpointID)
+		return resp.Endpoint, err
+	}
+
+	resp, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return neon.Endpoint{}, err
+	}
+	for _, v := range resp.Endpoints {
+		if v.Type == neon.EndpointTypeReadWrite {
+			return v, nil
+		}
+	}
+	return neon.Endpoint{}, nil
+}
+
+func resourcePreviewEnvironmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
+	[]*schema.ResourceData, error,
+) {
+	tflog.Trace(ctx, "import Preview Environment")
+
+	spl := strings.Split(d.Id(), "/")
+	switch len(spl) {
+	case 2:
+		spl = append(spl, "app", "app")
+	case 4:
+	default:
+		return nil, errors.New(
+			"ID of this resource type shall follow the template: {{.ProjectID}}/{{.BranchID}}, " +
+				"or {{.ProjectID}}/{{.BranchID}}/{{.RoleName}}/{{.DatabaseName}}",
+		)
+	}
+	projectID, branchID, roleName, dbName := spl[0], spl[1], spl[2], spl[3]
+
+	d.SetId(branchID)
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("role_name", roleName)
+	_ = d.Set("database_name", dbName)
+
+	client := meta.(sdkPreviewEnvironment)
+	if err := projectReadiness.retry(
+		func(context.Context, *schema.ResourceData, interface{}) error {
+			if _, err := client.GetProjectBranchDatabase(projectID, branchID, dbName); err != nil {
+				return err
+			}
+			resp, err := client.GetProjectBranchRolePassword(projectID, branchID, roleName)
+			if err != nil {
+				return err
+			}
+			return d.Set("password", resp.Password)
+		}, ctx, d, meta,
+	); err != nil {
+		return nil, err
+	}
+
+	if diags := resourcePreviewEnvironmentReadRetry(ctx, d, meta); diags.HasError() {
+		return nil, errors.New(diags[0].Summary)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcePreviewEnvironmentDeleteRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.Retry(resourcePreviewEnvironmentDelete, ctx, d, meta)
+}
+
+func resourcePreviewEnvironmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "delete Preview Environment")
+
+	client := meta.(sdkPreviewEnvironment)
+	projectID := d.Get("project_id").(string)
+
+	if _, err := client.DeleteProjectBranch(projectID, d.Id()); err != nil {
+		return err
+	}
+
+	if err := waitDeleted(ctx, func() error {
+		_, err := client.GetProjectBranch(projectID, d.Id())
+		return err
+	}); err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,251 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resourcePreviewEnvironment(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	d := resourcePreviewEnvironment().TestResourceData()
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("name", "pr-42")
+	_ = d.Set("role_name", "app")
+	_ = d.Set("database_name", "app")
+
+	ctx := context.TODO()
+
+	// WHEN
+	if diags := resourcePreviewEnvironmentCreate(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	branchID := d.Id()
+	assert.NotEmpty(t, branchID)
+	assert.Equal(t, branchID, d.Get("branch_id"))
+	assert.Equal(t, project.Branch.ID, d.Get("parent_id"))
+
+	host := d.Get("host").(string)
+	assert.NotEmpty(t, host)
+	assert.NotEmpty(t, d.Get("endpoint_id"))
+
+	password := d.Get("password").(string)
+	assert.NotEmpty(t, password)
+	assert.Equal(t, "postgres://app:"+password+"@"+host+"/app", d.Get("connection_uri"))
+	assert.Equal(t, "postgres://app:"+password+"@"+poolerHost(host)+"/app", d.Get("connection_uri_pooler"))
+
+	db, err := client.GetProjectBranchDatabase(projectID, branchID, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "app", db.Database.OwnerName)
+
+	// WHEN
+	if diags := resourcePreviewEnvironmentReadRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Equal(t, "pr-42", d.Get("name"))
+	assert.Equal(t, host, d.Get("host"))
+
+	// WHEN
+	if diags := resourcePreviewEnvironmentDeleteRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Empty(t, d.Id())
+	_, err = client.GetProjectBranch(projectID, branchID)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+	}
+}
+
+func Test_resourcePreviewEnvironmentCreate_failedStep(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	// the database cannot be created
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/databases") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"","message":"database cannot be created"}`))
+			return
+		}
+		srv.ServeHTTP(w, r)
+	})
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: handler}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	d := resourcePreviewEnvironment().TestResourceData()
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("name", "pr-42")
+	_ = d.Set("role_name", "app")
+	_ = d.Set("database_name", "app")
+
+	ctx := context.TODO()
+
+	// WHEN
+	diags := resourcePreviewEnvironmentCreate(ctx, d, client)
+
+	// THEN
+	assert.True(t, diags.HasError())
+	branchID := d.Id()
+	assert.NotEmpty(t, branchID)
+	assert.Equal(t, branchID, d.Get("branch_id"))
+	endpointID := d.Get("endpoint_id").(string)
+	assert.NotEmpty(t, endpointID)
+	assert.NotEmpty(t, d.Get("host"))
+
+	t.Run("shall read the environment without the database", func(t *testing.T) {
+		// WHEN
+		diags := resourcePreviewEnvironmentReadRetry(ctx, d, client)
+
+		// THEN
+		assert.False(t, diags.HasError(), diags)
+		assert.Equal(t, branchID, d.Id())
+		assert.Equal(t, endpointID, d.Get("endpoint_id"))
+	})
+
+	t.Run("shall read the branch's endpoint if the endpoint ID is missing", func(t *testing.T) {
+		// GIVEN
+		_ = d.Set("endpoint_id", "")
+
+		// WHEN
+		diags := resourcePreviewEnvironmentReadRetry(ctx, d, client)
+
+		// THEN
+		assert.False(t, diags.HasError(), diags)
+		assert.Equal(t, endpointID, d.Get("endpoint_id"))
+	})
+
+	t.Run("shall delete the branch of the tainted environment", func(t *testing.T) {
+		// WHEN
+		diags := resourcePreviewEnvironmentDeleteRetry(ctx, d, client)
+
+		// THEN
+		assert.False(t, diags.HasError(), diags)
+		_, err := client.GetProjectBranch(projectID, branchID)
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+		}
+	})
+}
+
+func Test_resourcePreviewEnvironmentImport(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+
+	created := resourcePreviewEnvironment().TestResourceData()
+	_ = created.Set("project_id", projectID)
+	_ = created.Set("name", "pr-42")
+	_ = created.Set("role_name", "ci")
+	_ = created.Set("database_name", "ci_db")
+
+	ctx := context.TODO()
+	if diags := resourcePreviewEnvironmentCreate(ctx, created, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	t.Run("shall import the environment by its branch, role and database", func(t *testing.T) {
+		// GIVEN
+		d := resourcePreviewEnvironment().TestResourceData()
+		d.SetId(projectID + "/" + created.Id() + "/ci/ci_db")
+
+		// WHEN
+		_, err := resourcePreviewEnvironmentImport(ctx, d, client)
+
+		// THEN
+		if assert.NoError(t, err) {
+			assert.Equal(t, created.Id(), d.Id())
+			for _, k := range []string{
+				"project_id", "name", "parent_id", "role_name", "database_name", "branch_id", "endpoint_id", "host",
+				"password", "connection_uri", "connection_uri_pooler",
+			} {
+				assert.Equal(t, created.Get(k), d.Get(k), k)
+			}
+		}
+	})
+
+	t.Run("shall fail if the database is not found", func(t *testing.T) {
+		// GIVEN
+		d := resourcePreviewEnvironment().TestResourceData()
+		d.SetId(projectID + "/" + created.Id())
+
+		// WHEN
+		_, err := resourcePreviewEnvironmentImport(ctx, d, client)
+
+		// THEN
+		if assert.Error(t, err) {
+			assert.Equal(t, http.StatusNotFound, err.(neon.Error).HTTPCode)
+		}
+	})
+
+	t.Run("shall fail given the malformed ID", func(t *testing.T) {
+		// GIVEN
+		d := resourcePreviewEnvironment().TestResourceData()
+		d.SetId(projectID + "/" + created.Id() + "/ci")
+
+		// WHEN
+		_, err := resourcePreviewEnvironmentImport(ctx, d, client)
+
+		// THEN
+		assert.ErrorContains(t, err, "{{.ProjectID}}/{{.BranchID}}")
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -37,17 +40,20 @@ API: https://api-docs.neon.tech/reference/createproject`,
 		ReadContext:   resourceProjectReadRetry,
 		UpdateContext: resourceProjectUpdateRetry,
 		DeleteContext: resourceProjectDeleteRetry,
-		Schema: map[string]*schema.Schema{
+		CustomizeDiff: resourceProjectCustomizeDiff,
+		Schema: withConsumptionMetrics(map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Project ID.",
 			},
 			"org_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "Identifier of the organisation to which this project belongs.",
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Description: `Identifier of the organisation to which this project belongs.
+The provider's default ` + "`org_id`" + ` is used if not set.`,
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -55,7 +61,9 @@ API: https://api-docs.neon.tech/reference/createproject`,
 				Computed:    true,
 				Description: "Project name.",
 			},
-			"region_id": schemaRegionID,
+			"region_id":               schemaRegionID,
+			"skip_region_validation":  schemaSkipRegionValidation,
+			"ignore_external_changes": schemaIgnoreExternalChanges("autoscaling", "suspend_timeout"),
 			"pg_version": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -82,6 +90,14 @@ API: https://api-docs.neon.tech/reference/createproject`,
 				ValidateFunc: intValidationNotNegative,
 				Description: `The number of seconds to retain the point-in-time restore (PITR) backup history for this project.
 Default: 1 day, see https://neon.tech/docs/reference/glossary#point-in-time-restore.`,
+			},
+			"allow_retention_shrink": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to permit the reduction of ` + "`history_retention_seconds`" + `.
+The plan fails if the retention window shrinks otherwise, because the history beyond the new window is discarded,
+i.e. the branches cannot be restored to the points in time before it.`,
 			},
 			"compute_provisioner": {
 				Type:     schema.TypeString,
@@ -108,6 +124,20 @@ Specify the k8s-neonvm provisioner to create a compute endpoint that supports Au
 			"quota":                     schemaQuota,
 			"default_endpoint_settings": schemaDefaultEndpointSettings,
 			"branch":                    schemaDefaultBranch,
+			"quota_usage_percent": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeFloat},
+				Description: `Consumption in percents of the quota per quota's attribute with non-zero value, e.g.
+` + "`written_data_bytes`" + `. The consumption is reset at the end of each billing period.
+**Note** that ` + "`logical_size_bytes`" + ` is not included because it's applied per branch.`,
+			},
+			"maintenance_starts_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `Timestamp when the project's maintenance begins, RFC3339. Empty if no maintenance is scheduled.
+The plan warns if the maintenance begins within the provider's ` + "`maintenance_warning_hours`" + `.`,
+			},
 			"allowed_ips": {
 				Type:     schema.TypeList,
 				MinItems: 1,
@@ -123,6 +153,26 @@ Note that the feature is available to the Neon Scale plans only.`,
 			"allowed_ips_protected_branches_only": types.NewOptionalTristateBool(
 				`Apply the allow-list to the protected branches only.
 Note that the feature is available to the Neon Scale plans only.`, false),
+			"enforce_default_branch_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to mark the default branch protected upon the project creation,
+and to keep it protected: the protection is restored upon the next apply if it was removed elsewhere.
+See details: https://neon.tech/docs/guides/protected-branches`,
+			},
+			"default_branch_protected": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the default branch is protected.",
+			},
+			"allow_open_internet": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `Set to true to permit the allow-list entries which open the access from any IP address,
+e.g. ` + "`0.0.0.0/0`" + `. The plan fails if such entry is found in ` + "`allowed_ips`" + ` otherwise.`,
+			},
 			"enable_logical_replication": types.NewOptionalTristateBool(
 				`Sets wal_level=logical for all compute endpoints in this project.
 All active endpoints will be suspended. Once enabled, logical replication cannot be disabled.
@@ -166,7 +216,27 @@ See details: https://neon.tech/docs/introduction/logical-replication
 				Computed:    true,
 				Description: "Default endpoint ID.",
 			},
+			"default_database_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `ID of the default database to import it as the resource ` + "`neon_database`" + `,
+i.e. {{.ProjectID}}/{{.BranchID}}/{{.Name}}.`,
+			},
+			"default_role_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: `ID of the default role to import it as the resource ` + "`neon_role`" + `,
+i.e. {{.ProjectID}}/{{.BranchID}}/{{.Name}}.`,
+			},
+			"console_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Link to the project's page in the Neon console.",
+			},
 		},
+			"active_time_seconds", "compute_time_seconds", "written_data_bytes", "data_transfer_bytes",
+			"data_storage_bytes_hour", "synthetic_storage_size",
+		),
 	}
 }
 
@@ -267,22 +337,25 @@ var schemaDefaultEndpointSettings = &schema.Schema{
 				Description: "Endpoint ID.",
 			},
 			"autoscaling_limit_min_cu": {
-				Type:         schema.TypeFloat,
-				ValidateFunc: validateAutoscallingLimit,
-				Optional:     true,
-				Computed:     true,
+				Type:             schema.TypeFloat,
+				ValidateFunc:     validateAutoscallingLimit,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("autoscaling"),
 			},
 			"autoscaling_limit_max_cu": {
-				Type:         schema.TypeFloat,
-				ValidateFunc: validateAutoscallingLimit,
-				Optional:     true,
-				Computed:     true,
+				Type:             schema.TypeFloat,
+				ValidateFunc:     validateAutoscallingLimit,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("autoscaling"),
 			},
 			"suspend_timeout_seconds": {
-				Type:         schema.TypeInt,
-				Optional:     true,
-				Computed:     true,
-				ValidateFunc: intValidationNotNegative,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressExternalChange("suspend_timeout"),
+				ValidateFunc:     intValidationNotNegative,
 				Description: `Duration of inactivity in seconds after which the compute endpoint is automatically suspended.
 The value 0 means use the global default.
 The value -1 means never suspend. The default value is 300 seconds (5 minutes).
@@ -308,6 +381,49 @@ func mapToDefaultEndpointsSettings(v map[string]interface{}) *neon.DefaultEndpoi
 	return &o
 }
 
+func defaultEndpointSettingsFromConfig(d *schema.ResourceData) *neon.DefaultEndpointSettings {
+	v, ok := d.GetOk("default_endpoint_settings")
+	if !ok || len(v.([]interface{})) == 0 || v.([]interface{})[0] == nil {
+		return nil
+	}
+
+	m, ok := v.([]interface{})[0].(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil
+	}
+
+	o := mapToDefaultEndpointsSettings(m)
+	// the value 0 is legitimate: it means use the global default
+	if isDefinedInConfig(d, "default_endpoint_settings.0.suspend_timeout_seconds") {
+		o.SuspendTimeoutSeconds = pointer(neon.SuspendTimeoutSeconds(m["suspend_timeout_seconds"].(int)))
+	}
+	return o
+}
+
+func defaultEndpointSettingsToList(v *neon.DefaultEndpointSettings, endpointID string) []interface{} {
+	o := map[string]interface{}{}
+	if endpointID != "" {
+		o["id"] = endpointID
+	}
+
+	if v != nil {
+		if v.AutoscalingLimitMinCu != nil {
+			o["autoscaling_limit_min_cu"] = float64(*v.AutoscalingLimitMinCu)
+		}
+		if v.AutoscalingLimitMaxCu != nil {
+			o["autoscaling_limit_max_cu"] = float64(*v.AutoscalingLimitMaxCu)
+		}
+		if v.SuspendTimeoutSeconds != nil {
+			o["suspend_timeout_seconds"] = int(*v.SuspendTimeoutSeconds)
+		}
+	}
+
+	if len(o) == 0 {
+		return nil
+	}
+	return []interface{}{o}
+}
+
 var schemaDefaultBranch = &schema.Schema{
 	Type:     schema.TypeList,
 	MaxItems: 1,
@@ -410,7 +526,9 @@ func findDefaultDatabase(databases []neon.Database, defaultBranchID string) neon
 				return a.CreatedAt.Compare(b.CreatedAt)
 			})
 
-			o = dbs[0]
+			if len(dbs) > 0 {
+				o = dbs[0]
+			}
 		}
 	}
 
@@ -434,7 +552,9 @@ func findDefaultEndpoint(endpoints []neon.Endpoint, defaultBranchID string) neon
 			return a.CreatedAt.Compare(b.CreatedAt)
 		})
 
-		o = eps[0]
+		if len(eps) > 0 {
+			o = eps[0]
+		}
 	}
 
 	return o
@@ -458,7 +578,7 @@ func (i dbConnectionInfo) connectionURI() string {
 func updateStateProject(
 	d *schema.ResourceData, r neon.Project,
 	defaultBranchID, defaultBranchName string,
-	dbConnectionInfo dbConnectionInfo,
+	dbConnectionInfo dbConnectionInfo, console string,
 ) error {
 	if r.OrgID != nil {
 		if err := d.Set("org_id", *r.OrgID); err != nil {
@@ -484,33 +604,12 @@ func updateStateProject(
 		return err
 	}
 
-	defaultEndpointSettings := map[string]interface{}{}
-	if v, ok := d.GetOk("default_endpoint_settings"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
-		if v, ok := v.([]interface{})[0].(map[string]interface{}); ok && len(v) > 0 {
-			defaultEndpointSettings = v
-		}
-	}
-
-	if r.DefaultEndpointSettings != nil {
-		if r.DefaultEndpointSettings.AutoscalingLimitMinCu != nil {
-			defaultEndpointSettings["autoscaling_limit_min_cu"] = float64(*r.DefaultEndpointSettings.AutoscalingLimitMinCu)
-		}
-		if r.DefaultEndpointSettings.AutoscalingLimitMaxCu != nil {
-			defaultEndpointSettings["autoscaling_limit_max_cu"] = float64(*r.DefaultEndpointSettings.AutoscalingLimitMaxCu)
-		}
-		if r.DefaultEndpointSettings.SuspendTimeoutSeconds != nil {
-			defaultEndpointSettings["suspend_timeout_seconds"] = float64(*r.DefaultEndpointSettings.SuspendTimeoutSeconds)
-		}
-		if err := d.Set("default_endpoint_settings", []interface{}{defaultEndpointSettings}); err != nil {
-			return err
-		}
-	}
-
-	if dbConnectionInfo.endpointID != "" {
-		defaultEndpointSettings["id"] = dbConnectionInfo.endpointID
-		if err := d.Set("default_endpoint_settings", []interface{}{defaultEndpointSettings}); err != nil {
-			return err
-		}
+	// the settings are defined by the API response only to detect the changes made outside terraform
+	if err := d.Set(
+		"default_endpoint_settings",
+		defaultEndpointSettingsToList(r.DefaultEndpointSettings, dbConnectionInfo.endpointID),
+	); err != nil {
+		return err
 	}
 
 	if err := d.Set(
@@ -526,16 +625,28 @@ func updateStateProject(
 		return err
 	}
 
+	if err := d.Set("quota_usage_percent", projectQuotaUsage(r)); err != nil {
+		return err
+	}
+
+	var maintenanceStartsAt string
+	if r.MaintenanceStartsAt != nil {
+		maintenanceStartsAt = r.MaintenanceStartsAt.UTC().Format(time.RFC3339)
+	}
+	if err := d.Set("maintenance_starts_at", maintenanceStartsAt); err != nil {
+		return err
+	}
+
 	if r.Settings != nil {
 		if r.Settings.Quota != nil {
 			if err := d.Set(
 				"quota", []interface{}{
 					map[string]interface{}{
-						"active_time_seconds":  int(*r.Settings.Quota.ActiveTimeSeconds),
-						"compute_time_seconds": int(*r.Settings.Quota.ComputeTimeSeconds),
-						"written_data_bytes":   int(*r.Settings.Quota.WrittenDataBytes),
-						"data_transfer_bytes":  int(*r.Settings.Quota.DataTransferBytes),
-						"logical_size_bytes":   int(*r.Settings.Quota.LogicalSizeBytes),
+						"active_time_seconds":  quotaValue(r.Settings.Quota.ActiveTimeSeconds),
+						"compute_time_seconds": quotaValue(r.Settings.Quota.ComputeTimeSeconds),
+						"written_data_bytes":   quotaValue(r.Settings.Quota.WrittenDataBytes),
+						"data_transfer_bytes":  quotaValue(r.Settings.Quota.DataTransferBytes),
+						"logical_size_bytes":   quotaValue(r.Settings.Quota.LogicalSizeBytes),
 					},
 				},
 			); err != nil {
@@ -548,7 +659,7 @@ func updateStateProject(
 			primaryBranchesOnly   *bool
 			protectedBranchesOnly *bool
 		)
-		if r.Settings.AllowedIps.Ips != nil {
+		if r.Settings.AllowedIps != nil && r.Settings.AllowedIps.Ips != nil {
 			allowedIPs = *r.Settings.AllowedIps.Ips
 			primaryBranchesOnly = r.Settings.AllowedIps.PrimaryBranchOnly
 			protectedBranchesOnly = r.Settings.AllowedIps.ProtectedBranchesOnly
@@ -577,6 +688,10 @@ func updateStateProject(
 		}
 	}
 
+	if err := setConsumptionMetrics(d, projectConsumptionMetrics(r)); err != nil {
+		return err
+	}
+
 	if err := d.Set("default_branch_id", defaultBranchID); err != nil {
 		return err
 	}
@@ -605,9 +720,74 @@ func updateStateProject(
 		return err
 	}
 
+	var defaultDatabaseID, defaultRoleID string
+	if defaultBranchID != "" && dbConnectionInfo.dbName != "" {
+		defaultDatabaseID = complexID{r.ID, defaultBranchID, dbConnectionInfo.dbName}.toString()
+	}
+	if defaultBranchID != "" && dbConnectionInfo.userName != "" {
+		defaultRoleID = complexID{r.ID, defaultBranchID, dbConnectionInfo.userName}.toString()
+	}
+	if err := d.Set("default_database_id", defaultDatabaseID); err != nil {
+		return err
+	}
+	if err := d.Set("default_role_id", defaultRoleID); err != nil {
+		return err
+	}
+
+	if err := d.Set("console_url", consoleURL(console, "projects", r.ID)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func projectConsumptionMetrics(r neon.Project) map[string]int64 {
+	var syntheticStorageSize int64
+	if r.SyntheticStorageSize != nil {
+		syntheticStorageSize = *r.SyntheticStorageSize
+	}
+	return map[string]int64{
+		"active_time_seconds":     r.ActiveTimeSeconds,
+		"compute_time_seconds":    r.ComputeTimeSeconds,
+		"written_data_bytes":      r.WrittenDataBytes,
+		"data_transfer_bytes":     r.DataTransferBytes,
+		"data_storage_bytes_hour": r.DataStorageBytesHour,
+		"synthetic_storage_size":  syntheticStorageSize,
+	}
+}
+
+// projectQuotaUsage returns the consumption in percents of the quota per consumption metric with the quota set.
+func projectQuotaUsage(r neon.Project) map[string]interface{} {
+	o := map[string]interface{}{}
+	if r.Settings == nil || r.Settings.Quota == nil {
+		return o
+	}
+
+	q := r.Settings.Quota
+	for k, v := range map[string]struct {
+		used  int64
+		quota *int64
+	}{
+		"active_time_seconds":  {r.ActiveTimeSeconds, q.ActiveTimeSeconds},
+		"compute_time_seconds": {r.ComputeTimeSeconds, q.ComputeTimeSeconds},
+		"written_data_bytes":   {r.WrittenDataBytes, q.WrittenDataBytes},
+		"data_transfer_bytes":  {r.DataTransferBytes, q.DataTransferBytes},
+	} {
+		if v.quota != nil && *v.quota > 0 {
+			o[k] = math.Round(float64(v.used)/float64(*v.quota)*10000) / 100
+		}
+	}
+	return o
+}
+
+// quotaValue returns the quota's value, or 0 if the quota is not set, i.e. unlimited.
+func quotaValue(v *int64) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
 func resourceProjectDeleteRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return projectReadiness.Retry(resourceProjectDelete, ctx, d, meta)
 }
@@ -616,16 +796,22 @@ func resourceProjectUpdateRetry(ctx context.Context, d *schema.ResourceData, met
 	return projectReadiness.Retry(resourceProjectUpdate, ctx, d, meta)
 }
 
-func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
-	tflog.Trace(ctx, "created Project")
-
-	var orgID *string
+// projectOrgID returns the project's organization ID, or the provider's default organization ID if not set.
+func projectOrgID(d *schema.ResourceData, meta interface{}) *string {
 	if v, ok := d.GetOk("org_id"); ok && v != "" {
-		orgID = pointer(v.(string))
+		return pointer(v.(string))
 	}
+	if c, ok := meta.(*providerClient); ok && c.orgID != "" {
+		return pointer(c.orgID)
+	}
+	return nil
+}
+
+func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "created Project")
 
 	projectDef := neon.ProjectCreateRequestProject{
-		OrgID:          orgID,
+		OrgID:          projectOrgID(d, meta),
 		Name:           pointer(d.Get("name").(string)),
 		Provisioner:    pointer(neon.Provisioner(d.Get("compute_provisioner").(string))),
 		RegionID:       pointer(d.Get("region_id").(string)),
@@ -640,11 +826,7 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta int
 		projectDef.PgVersion = pointer(neon.PgVersion(v.(int)))
 	}
 
-	if v, ok := d.GetOk("default_endpoint_settings"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
-		if v, ok := v.([]interface{})[0].(map[string]interface{}); ok && len(v) > 0 {
-			projectDef.DefaultEndpointSettings = mapToDefaultEndpointsSettings(v)
-		}
-	}
+	projectDef.DefaultEndpointSettings = defaultEndpointSettingsFromConfig(d)
 
 	if v, ok := d.GetOk("quota"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
 		if v, ok := v.([]interface{})[0].(map[string]interface{}); ok && len(v) > 0 {
@@ -709,11 +891,37 @@ func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, meta int
 		return err
 	}
 
-	return updateStateProject(d, resp.ProjectResponse.Project, branch.ID, branch.Name, info)
+	return updateStateProject(d, resp.ProjectResponse.Project, branch.ID, branch.Name, info, consoleBaseURL(meta))
 }
 
 func resourceProjectCreateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceProjectCreate, ctx, d, meta)
+	if diags := projectReadiness.Retry(resourceProjectCreate, ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	if !d.Get("enforce_default_branch_protection").(bool) {
+		return nil
+	}
+	// the step is retried separately from the project creation to avoid creation of redundant projects
+	if diags := projectReadiness.Retry(protectDefaultBranch, ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	return diag.FromErr(d.Set("default_branch_protected", true))
+}
+
+// protectDefaultBranch marks the project's default branch protected.
+func protectDefaultBranch(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	branchID := d.Get("default_branch_id").(string)
+	if branchID == "" {
+		return errors.New("default branch of the project " + d.Id() + " not found")
+	}
+
+	tflog.Debug(ctx, "protect default branch "+branchID)
+	_, err := meta.(sdkProject).UpdateProjectBranch(d.Id(), branchID, neon.BranchUpdateRequest{
+		Branch: neon.BranchUpdateRequestBranch{
+			Protected: pointer(true),
+		},
+	})
+	return err
 }
 
 func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
@@ -732,11 +940,7 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	if d.HasChange("default_endpoint_settings") {
-		if v, ok := d.GetOk("default_endpoint_settings"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
-			if v, ok := v.([]interface{})[0].(map[string]interface{}); ok && len(v) > 0 {
-				req.Project.DefaultEndpointSettings = mapToDefaultEndpointsSettings(v)
-			}
-		}
+		req.Project.DefaultEndpointSettings = defaultEndpointSettingsFromConfig(d)
 	}
 
 	if d.HasChange("quota") {
@@ -779,10 +983,20 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	req.Project.Settings.EnableLogicalReplication = types.GetTristateBool(d, "enable_logical_replication")
 
 	_, err := meta.(sdkProject).UpdateProject(d.Id(), req)
+	// the history retention may be changed even if the update fails
+	projectHistoryRetentionCache.Delete(d.Id())
 	if err != nil {
 		return err
 	}
 
+	// the protection is planned by CustomizeDiff, hence the state before the update is checked
+	if protected, _ := d.GetChange("default_branch_protected"); d.Get("enforce_default_branch_protection").(bool) &&
+		!protected.(bool) {
+		if err := protectDefaultBranch(ctx, d, meta); err != nil {
+			return err
+		}
+	}
+
 	return resourceProjectRead(ctx, d, meta)
 }
 
@@ -804,14 +1018,19 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, meta inter
 
 	var branchMain neon.Branch
 	for _, v := range branches.Branches {
-		if v.Primary != nil && *v.Primary {
+		if v.Default {
 			branchMain = v
 			break
 		}
 	}
 
 	if branchMain.ID == "" {
-		return updateStateProject(d, project, branchMain.ID, branchMain.Name, dbConnectionInfo{})
+		return updateStateProject(d, project, branchMain.ID, branchMain.Name, dbConnectionInfo{}, consoleBaseURL(meta))
+	}
+
+	// the drift leads to the update which restores the protection if it's enforced, see resourceProjectCustomizeDiff
+	if err := d.Set("default_branch_protected", branchMain.Protected); err != nil {
+		return err
 	}
 
 	endpoints, err := client.ListProjectBranchEndpoints(d.Id(), branchMain.ID)
@@ -829,11 +1048,11 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, meta inter
 		return err
 	}
 
-	return updateStateProject(d, project, branchMain.ID, branchMain.Name, info)
+	return updateStateProject(d, project, branchMain.ID, branchMain.Name, info, consoleBaseURL(meta))
 }
 
 func resourceProjectReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceProjectRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceProjectRead, ctx, d, meta)
 }
 
 func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
@@ -842,26 +1061,119 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, meta int
 	if _, err := meta.(sdkProject).DeleteProject(d.Id()); err != nil {
 		return err
 	}
+	projectHistoryRetentionCache.Delete(d.Id())
 
 	d.SetId("")
-	return updateStateProject(d, neon.Project{}, "", "", dbConnectionInfo{})
+	return updateStateProject(d, neon.Project{}, "", "", dbConnectionInfo{}, consoleBaseURL(meta))
 }
 
 func resourceProjectImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
 	[]*schema.ResourceData, error,
 ) {
+	if err := d.Set("allow_open_internet", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("enforce_default_branch_protection", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("skip_region_validation", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("allow_retention_shrink", false); err != nil {
+		return nil, err
+	}
 	if diags := resourceProjectReadRetry(ctx, d, meta); diags.HasError() {
 		return nil, errors.New(diags[0].Summary)
 	}
 	return []*schema.ResourceData{d}, nil
 }
 
+func resourceProjectCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	tflog.Trace(ctx, "customize Project diff")
+
+	if err := customizeDiffRegionID(ctx, d, meta.(sdkRegions)); err != nil {
+		return err
+	}
+
+	if err := customizeDiffAutoscalingLimitMax(
+		ctx, d, meta.(sdkAccountLimits), "default_endpoint_settings.0.autoscaling_limit_max_cu",
+	); err != nil {
+		return err
+	}
+
+	if d.Id() != "" && d.HasChange("history_retention_seconds") {
+		from, to := d.GetChange("history_retention_seconds")
+		if err := validateHistoryRetentionChange(
+			from.(int), to.(int), d.Get("allow_retention_shrink").(bool),
+		); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && d.Get("enforce_default_branch_protection").(bool) && !d.Get("default_branch_protected").(bool) {
+		tflog.Warn(ctx, "default branch of the project "+d.Id()+" is not protected, the protection is restored")
+		if err := d.SetNew("default_branch_protected", true); err != nil {
+			return err
+		}
+	}
+
+	var ips []string
+	for _, v := range d.Get("allowed_ips").([]interface{}) {
+		if ip, ok := v.(string); ok {
+			ips = append(ips, ip)
+		}
+	}
+	return validateAllowedIPs(ips, d.Get("allow_open_internet").(bool))
+}
+
+// validateHistoryRetentionChange fails if the history retention window shrinks, unless it's explicitly allowed.
+func validateHistoryRetentionChange(from, to int, allowShrink bool) error {
+	if allowShrink || to >= from {
+		return nil
+	}
+	return fmt.Errorf(
+		"history_retention_seconds is reduced from %d to %d, the restore points older than %d seconds "+
+			"are discarded, set allow_retention_shrink = true if it's intended", from, to, to,
+	)
+}
+
+// validateAllowedIPs fails if the allow-list contains the entry which permits access from any IP address,
+// unless it's explicitly allowed.
+func validateAllowedIPs(ips []string, allowOpenInternet bool) error {
+	if allowOpenInternet {
+		return nil
+	}
+	for _, ip := range ips {
+		if isOpenInternet(ip) {
+			return errors.New(
+				"allowed_ips entry " + ip + " permits access from any IP address, " +
+					"set allow_open_internet = true if it's intended",
+			)
+		}
+	}
+	return nil
+}
+
+func isOpenInternet(ip string) bool {
+	ip = strings.TrimSpace(ip)
+	switch ip {
+	case "0.0.0.0", "0.0.0.0-255.255.255.255", "::", "::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff":
+		return true
+	}
+	if _, ipNet, err := net.ParseCIDR(ip); err == nil {
+		ones, _ := ipNet.Mask.Size()
+		return ones == 0
+	}
+	return false
+}
+
 type sdkProject interface {
 	GetProjectBranchRolePassword(string, string, string) (neon.RolePasswordResponse, error)
 	CreateProject(neon.ProjectCreateRequest) (neon.CreatedProject, error)
 	UpdateProject(string, neon.ProjectUpdateRequest) (neon.UpdateProjectRespObj, error)
 	GetProject(string) (neon.ProjectResponse, error)
 	ListProjectBranches(string, *string) (neon.ListProjectBranchesRespObj, error)
+	UpdateProjectBranch(string, string, neon.BranchUpdateRequest) (neon.BranchOperations, error)
 	ListProjectBranchEndpoints(string, string) (neon.EndpointsResponse, error)
 	DeleteProject(string) (neon.ProjectResponse, error)
 	ListProjectBranchDatabases(string, string) (neon.DatabasesResponse, error)
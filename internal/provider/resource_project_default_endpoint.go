@@ -0,0 +1,353 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func resourceProjectDefaultEndpoint() *schema.Resource {
+	return &schema.Resource{
+		Description: `Project's default read-write Endpoint, i.e. the endpoint created together with the project.
+The endpoint is adopted upon create without the import, and its settings are managed in place.
+**Note** that the endpoint is only removed from the state upon destroy, it's deleted together with the project.
+**Note** that the resource shall not be used together with the attribute ` + "`default_endpoint_settings`" + `
+of the resource ` + "`neon_project`" + `, otherwise both manage the same endpoint's settings, and the plan never converges.`,
+		SchemaVersion: 1,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProjectDefaultEndpointImport,
+		},
+		CreateContext: resourceProjectDefaultEndpointCreateRetry,
+		ReadContext:   resourceProjectDefaultEndpointReadRetry,
+		UpdateContext: resourceProjectDefaultEndpointUpdateRetry,
+		DeleteContext: resourceProjectDefaultEndpointDelete,
+		CustomizeDiff: resourceProjectDefaultEndpointCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Project ID.",
+			},
+			"branch_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Branch ID. The project's default branch is used if not set.",
+			},
+			"autoscaling_limit_min_cu": {
+				Type:         schema.TypeFloat,
+				ValidateFunc: validateAutoscallingLimit,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Minimum number of Compute Units.",
+			},
+			"autoscaling_limit_max_cu": {
+				Type:         schema.TypeFloat,
+				ValidateFunc: validateAutoscallingLimit,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Maximum number of Compute Units.",
+			},
+			"suspend_timeout_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				Description: `Duration of inactivity in seconds after which the compute endpoint is automatically suspended.
+The value 0 means use the global default. The value -1 means never suspend.`,
+			},
+			"pooler_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				Description: `Activate connection pooling.
+See details: https://neon.tech/docs/connect/connection-pooling`,
+			},
+			"pooler_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				Description: `Mode of connections pooling.
+See details: https://neon.tech/docs/connect/connection-pooling`,
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Endpoint URI.",
+			},
+			"pooler_host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Host to connect to the endpoint using the connection pooler.",
+			},
+		},
+	}
+}
+
+type sdkProjectDefaultEndpoint interface {
+	sdkBranchLister
+	ListProjectBranchEndpoints(string, string) (neon.EndpointsResponse, error)
+	GetProjectEndpoint(string, string) (neon.EndpointResponse, error)
+	UpdateProjectEndpoint(string, string, neon.EndpointUpdateRequest) (neon.EndpointOperations, error)
+}
+
+func updateStateProjectDefaultEndpoint(d *schema.ResourceData, v neon.Endpoint) error {
+	if err := d.Set("branch_id", v.BranchID); err != nil {
+		return err
+	}
+	if err := d.Set("autoscaling_limit_min_cu", float64(v.AutoscalingLimitMinCu)); err != nil {
+		return err
+	}
+	if err := d.Set("autoscaling_limit_max_cu", float64(v.AutoscalingLimitMaxCu)); err != nil {
+		return err
+	}
+	if err := d.Set("suspend_timeout_seconds", int64(v.SuspendTimeoutSeconds)); err != nil {
+		return err
+	}
+	if err := d.Set("pooler_enabled", v.PoolerEnabled); err != nil {
+		return err
+	}
+	if err := d.Set("pooler_mode", string(v.PoolerMode)); err != nil {
+		return err
+	}
+	if err := d.Set("host", v.Host); err != nil {
+		return err
+	}
+	return d.Set("pooler_host", poolerHost(v.Host))
+}
+
+func resourceProjectDefaultEndpointCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return customizeDiffAutoscalingLimitMax(ctx, d, meta.(sdkAccountLimits), "autoscaling_limit_max_cu")
+}
+
+func resourceProjectDefaultEndpointCreateRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.Retry(resourceProjectDefaultEndpointCreate, ctx, d, meta)
+}
+
+// resourceProjectDefaultEndpointCreate adopts the branch's default read-write endpoint, and applies the settings
+// defined in the configuration.
+func resourceProjectDefaultEndpointCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "adopt Project Default Endpoint")
+
+	client := meta.(sdkProjectDefaultEndpoint)
+	projectID := d.Get("project_id").(string)
+
+	branchID := d.Get("branch_id").(string)
+	if branchID == "" {
+		resp, err := client.ListProjectBranches(projectID, nil)
+		if err != nil {
+			return err
+		}
+		for _, br := range resp.Branches {
+			if br.Default {
+				branchID = br.ID
+				break
+			}
+		}
+		if branchID == "" {
+			return errors.New("no default branch found in the project " + projectID)
+		}
+	}
+
+	resp, err := client.ListProjectBranchEndpoints(projectID, branchID)
+	if err != nil {
+		return err
+	}
+	endpoint, err := findBranchEndpoint(resp.Endpoints, branchID, "")
+	if err != nil {
+		return err
+	}
+
+	d.SetId(endpoint.ID)
+
+	var cfg neon.EndpointUpdateRequestEndpoint
+	var changed bool
+	if isDefinedInConfig(d, "autoscaling_limit_min_cu") {
+		cfg.AutoscalingLimitMinCu = pointer(neon.ComputeUnit(d.Get("autoscaling_limit_min_cu").(float64)))
+		changed = true
+	}
+	if isDefinedInConfig(d, "autoscaling_limit_max_cu") {
+		cfg.AutoscalingLimitMaxCu = pointer(neon.ComputeUnit(d.Get("autoscaling_limit_max_cu").(float64)))
+		changed = true
+	}
+	if isDefinedInConfig(d, "suspend_timeout_seconds") {
+		cfg.SuspendTimeoutSeconds = pointer(neon.SuspendTimeoutSeconds(d.Get("suspend_timeout_seconds").(int)))
+		changed = true
+	}
+	if isDefinedInConfig(d, "pooler_enabled") {
+		cfg.PoolerEnabled = pointer(d.Get("pooler_enabled").(bool))
+		changed = true
+	}
+	if isDefinedInConfig(d, "pooler_mode") {
+		cfg.PoolerMode = pointer(neon.EndpointPoolerMode(d.Get("pooler_mode").(string)))
+		changed = true
+	}
+
+	if changed {
+		resp, err := client.UpdateProjectEndpoint(projectID, endpoint.ID, neon.EndpointUpdateRequest{Endpoint: cfg})
+		if err != nil {
+			return err
+		}
+		endpoint = resp.EndpointResponse.Endpoint
+	}
+
+	return updateStateProjectDefaultEndpoint(d, endpoint)
+}
+
+func resourceProjectDefaultEndpointReadRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.RetryRead(resourceProjectDefaultEndpointRead, ctx, d, meta)
+}
+
+func resourceProjectDefaultEndpointRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "read Project Default Endpoint")
+
+	resp, err := meta.(sdkProjectDefaultEndpoint).GetProjectEndpoint(d.Get("project_id").(string), d.Id())
+	if err != nil {
+		return err
+	}
+
+	return updateStateProjectDefaultEndpoint(d, resp.Endpoint)
+}
+
+func resourceProjectDefaultEndpointUpdateRetry(
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	return projectReadiness.Retry(resourceProjectDefaultEndpointUpdate, ctx, d, meta)
+}
+
+func resourceProjectDefaultEndpointUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "update Project Default Endpoint")
+
+	resp, err := meta.(sdkProjectDefaultEndpoint).UpdateProjectEndpoint(
+		d.Get("project_id").(string),
+		d.Id(),
+		neon.EndpointUpdateRequest{
+			Endpoint: neon.EndpointUpdateRequestEndpoint{
+				AutoscalingLimitMinCu: pointer(neon.ComputeUnit(d.Get("autoscaling_limit_min_cu").(float64))),
+				AutoscalingLimitMaxCu: pointer(neon.ComputeUnit(d.Get("autoscaling_limit_max_cu").(float64))),
+				SuspendTimeoutSeconds: pointer(neon.SuspendTimeoutSeconds(d.Get("suspend_timeout_seconds").(int))),
+				PoolerEnabled:         pointer(d.Get("pooler_enabled").(bool)),
+				PoolerMode:            pointer(neon.EndpointPoolerMode(d.Get("pooler_mode").(string))),
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return updateStateProjectDefaultEndpoint(d, resp.EndpointResponse.Endpoint)
+}
+
+func resourceProjectDefaultEndpointDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.Info(ctx, "Project Default Endpoint is removed from the state only", map[string]interface{}{
+		"endpointID": d.Id(),
+	})
+	d.SetId("")
+	return nil
+}
+
+func resourceProjectDefaultEndpointImport(ctx context.Context, d *schema.ResourceData, meta interface{}) (
+	[]*schema.ResourceData, error,
+) {
+	tflog.Trace(ctx, "import Project Default Endpoint")
+
+	projectID, endpointID, ok := strings.Cut(d.Id(), "/")
+	if !ok || projectID == "" || endpointID == "" {
+		return nil, errors.New("ID of this resource type shall follow the template: {{.ProjectID}}/{{.EndpointID}}")
+	}
+
+	resp, err := meta.(sdkProjectDefaultEndpoint).GetProjectEndpoint(projectID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Endpoint.Type != neon.EndpointTypeReadWrite {
+		return nil, errors.New("endpoint " + endpointID + " is not the read-write endpoint")
+	}
+
+	d.SetId(endpointID)
+	if err := d.Set("project_id", projectID); err != nil {
+		return nil, err
+	}
+	if err := updateStateProjectDefaultEndpoint(d, resp.Endpoint); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// defaultEndpointOverlap tracks the projects whose default endpoint is managed by both, the attribute
+// default_endpoint_settings of the resource neon_project, and the resource neon_project_default_endpoint,
+// i.e. by single plan of the configuration.
+type defaultEndpointOverlap struct {
+	mu sync.Mutex
+	// byType defines the IDs of the projects per resource type which manages the project's default endpoint.
+	byType map[string]map[string]struct{}
+}
+
+// check records the project planned by the resource, and returns the warning if the project's default endpoint
+// is also managed by the other resource. The project is identified by the known ID only.
+func (o *defaultEndpointOverlap) check(typeName string, config, planned tftypes.Value) *tfprotov5.Diagnostic {
+	var projectID, other string
+	switch typeName {
+	case "neon_project":
+		attrs, ok := objectAttributes(config)
+		if !ok {
+			return nil
+		}
+		// the block which is not configured is the empty list
+		var settings []tftypes.Value
+		if v, ok := attrs["default_endpoint_settings"]; !ok || v.IsNull() ||
+			(v.IsKnown() && (v.As(&settings) != nil || len(settings) == 0)) {
+			return nil
+		}
+		projectID, other = plannedID(planned), "neon_project_default_endpoint"
+	case "neon_project_default_endpoint":
+		attrs, ok := objectAttributes(planned)
+		if !ok {
+			return nil
+		}
+		if v, ok := attrs["project_id"]; ok && v.IsKnown() && !v.IsNull() {
+			_ = v.As(&projectID)
+		}
+		other = "neon_project"
+	default:
+		return nil
+	}
+	if projectID == "" {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.byType == nil {
+		o.byType = map[string]map[string]struct{}{}
+	}
+	if o.byType[typeName] == nil {
+		o.byType[typeName] = map[string]struct{}{}
+	}
+	o.byType[typeName][projectID] = struct{}{}
+
+	if _, ok := o.byType[other][projectID]; !ok {
+		return nil
+	}
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Default endpoint of the project " + projectID + " is managed twice",
+		Detail: "The settings of the project's default endpoint are defined by both, the attribute " +
+			"default_endpoint_settings of neon_project, and the resource neon_project_default_endpoint. " +
+			"Remove one of them, otherwise the plan never converges.",
+	}
+}
@@ -0,0 +1,226 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resourceProjectDefaultEndpoint(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+	if len(project.Endpoints) == 0 {
+		t.Fatal("no default endpoint was created")
+	}
+	endpointID := project.Endpoints[0].ID
+
+	// only the attributes defined in the configuration are applied upon create
+	d := resourceProjectDefaultEndpoint().Data(&terraform.InstanceState{
+		RawConfig: cty.ObjectVal(map[string]cty.Value{
+			"project_id":              cty.StringVal(projectID),
+			"suspend_timeout_seconds": cty.NumberIntVal(600),
+		}),
+	})
+	_ = d.Set("project_id", projectID)
+	_ = d.Set("suspend_timeout_seconds", 600)
+
+	ctx := context.TODO()
+
+	// WHEN
+	if diags := resourceProjectDefaultEndpointCreateRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Equal(t, endpointID, d.Id())
+	assert.Equal(t, project.Branch.ID, d.Get("branch_id"))
+	assert.Equal(t, 600, d.Get("suspend_timeout_seconds"))
+	assert.Equal(t, project.Endpoints[0].Host, d.Get("host"))
+
+	resp, err := client.GetProjectEndpoint(projectID, endpointID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, neon.SuspendTimeoutSeconds(600), resp.Endpoint.SuspendTimeoutSeconds)
+
+	// WHEN
+	_ = d.Set("pooler_enabled", true)
+	if diags := resourceProjectDefaultEndpointUpdateRetry(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	resp, err = client.GetProjectEndpoint(projectID, endpointID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, resp.Endpoint.PoolerEnabled)
+	assert.Equal(t, neon.SuspendTimeoutSeconds(600), resp.Endpoint.SuspendTimeoutSeconds)
+
+	// WHEN
+	if diags := resourceProjectDefaultEndpointDelete(ctx, d, client); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// THEN
+	assert.Empty(t, d.Id())
+	_, err = client.GetProjectEndpoint(projectID, endpointID)
+	assert.NoError(t, err, "the endpoint must not be deleted")
+}
+
+func Test_resourceProjectDefaultEndpointImport(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "fake", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+	endpointID := project.Endpoints[0].ID
+
+	t.Run("shall import the endpoint given the project ID and the endpoint ID", func(t *testing.T) {
+		// GIVEN
+		d := resourceProjectDefaultEndpoint().TestResourceData()
+		d.SetId(projectID + "/" + endpointID)
+
+		// WHEN
+		got, err := resourceProjectDefaultEndpointImport(context.TODO(), d, client)
+
+		// THEN
+		if assert.NoError(t, err) && assert.Len(t, got, 1) {
+			assert.Equal(t, endpointID, got[0].Id())
+			assert.Equal(t, projectID, got[0].Get("project_id"))
+			assert.Equal(t, project.Branch.ID, got[0].Get("branch_id"))
+			assert.Equal(t, project.Endpoints[0].Host, got[0].Get("host"))
+		}
+	})
+
+	t.Run("shall fail given the malformed ID", func(t *testing.T) {
+		// GIVEN
+		d := resourceProjectDefaultEndpoint().TestResourceData()
+		d.SetId(endpointID)
+
+		// WHEN
+		_, err := resourceProjectDefaultEndpointImport(context.TODO(), d, client)
+
+		// THEN
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "{{.ProjectID}}/{{.EndpointID}}")
+		}
+	})
+}
+
+func Test_defaultEndpointOverlap_check(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	settingsType := tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"autoscaling_limit_max_cu": tftypes.Number,
+	}}}
+	projectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":                        tftypes.String,
+		"default_endpoint_settings": settingsType,
+	}}
+	newProject := func(id interface{}, settings bool) tftypes.Value {
+		// terraform defines the block which is not configured as the empty list
+		v := tftypes.NewValue(settingsType, []tftypes.Value{})
+		if settings {
+			v = tftypes.NewValue(settingsType, []tftypes.Value{
+				tftypes.NewValue(settingsType.ElementType, map[string]tftypes.Value{
+					"autoscaling_limit_max_cu": tftypes.NewValue(tftypes.Number, 2),
+				}),
+			})
+		}
+		return tftypes.NewValue(projectType, map[string]tftypes.Value{
+			"id":                        tftypes.NewValue(tftypes.String, id),
+			"default_endpoint_settings": v,
+		})
+	}
+
+	endpointType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"project_id": tftypes.String}}
+	newEndpoint := func(projectID interface{}) tftypes.Value {
+		return tftypes.NewValue(endpointType, map[string]tftypes.Value{
+			"project_id": tftypes.NewValue(tftypes.String, projectID),
+		})
+	}
+
+	t.Run("shall warn if the default endpoint is managed twice", func(t *testing.T) {
+		// GIVEN
+		var o defaultEndpointOverlap
+
+		// WHEN
+		project := o.check("neon_project", newProject(nil, true), newProject("foo", true))
+		endpoint := o.check("neon_project_default_endpoint", newEndpoint("foo"), newEndpoint("foo"))
+
+		// THEN
+		assert.Nil(t, project)
+		if assert.NotNil(t, endpoint) {
+			assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, endpoint.Severity)
+			assert.Contains(t, endpoint.Summary, "foo")
+		}
+	})
+
+	t.Run("shall not warn if the project's settings are not configured", func(t *testing.T) {
+		// GIVEN
+		var o defaultEndpointOverlap
+
+		// WHEN
+		endpoint := o.check("neon_project_default_endpoint", newEndpoint("foo"), newEndpoint("foo"))
+		// the settings are computed, i.e. they are planned, but not configured
+		project := o.check("neon_project", newProject(nil, false), newProject("foo", true))
+
+		// THEN
+		assert.Nil(t, endpoint)
+		assert.Nil(t, project)
+	})
+
+	t.Run("shall not warn about the different projects", func(t *testing.T) {
+		// GIVEN
+		var o defaultEndpointOverlap
+
+		// WHEN
+		project := o.check("neon_project", newProject(nil, true), newProject("foo", true))
+		endpoint := o.check("neon_project_default_endpoint", newEndpoint("bar"), newEndpoint("bar"))
+
+		// THEN
+		assert.Nil(t, project)
+		assert.Nil(t, endpoint)
+	})
+}
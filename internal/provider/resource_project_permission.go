@@ -116,7 +116,7 @@ func resourceProjectPermissionImport(ctx context.Context, d *schema.ResourceData
 }
 
 func resourceProjectPermissionReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceProjectPermissionRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceProjectPermissionRead, ctx, d, meta)
 }
 
 func resourceProjectPermissionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
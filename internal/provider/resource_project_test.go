@@ -6,15 +6,18 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/kislerdm/terraform-provider-neon/fakeapi"
 	"github.com/kislerdm/terraform-provider-neon/internal/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -598,3 +601,379 @@ func TestValidatePgVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateAllowedIPs(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name              string
+		ips               []string
+		allowOpenInternet bool
+		wantErr           bool
+	}{
+		{
+			name: "restricted entries",
+			ips:  []string{"192.168.1.15", "192.168.2.0/24", "10.0.0.1-10.0.0.10", "2001:db8::/32"},
+		},
+		{
+			name:    "open IPv4 CIDR",
+			ips:     []string{"192.168.1.15", "0.0.0.0/0"},
+			wantErr: true,
+		},
+		{
+			name:    "open IPv6 CIDR",
+			ips:     []string{"::/0"},
+			wantErr: true,
+		},
+		{
+			name:    "open IPv4 range",
+			ips:     []string{"0.0.0.0-255.255.255.255"},
+			wantErr: true,
+		},
+		{
+			name:              "open entry explicitly allowed",
+			ips:               []string{"0.0.0.0/0"},
+			allowOpenInternet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAllowedIPs(tt.ips, tt.allowOpenInternet); (err != nil) != tt.wantErr {
+				t.Errorf("validateAllowedIPs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateHistoryRetentionChange(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name        string
+		from, to    int
+		allowShrink bool
+		wantErr     bool
+	}{
+		{
+			name: "extended window",
+			from: 86400,
+			to:   604800,
+		},
+		{
+			name: "unchanged window",
+			from: 86400,
+			to:   86400,
+		},
+		{
+			name:    "shrunk window",
+			from:    604800,
+			to:      86400,
+			wantErr: true,
+		},
+		{
+			name:    "turned off retention",
+			from:    86400,
+			to:      0,
+			wantErr: true,
+		},
+		{
+			name:        "shrunk window explicitly allowed",
+			from:        604800,
+			to:          86400,
+			allowShrink: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateHistoryRetentionChange(tt.from, tt.to, tt.allowShrink); (err != nil) != tt.wantErr {
+				t.Errorf("validateHistoryRetentionChange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzValidateAllowedIPs(f *testing.F) {
+	for _, seed := range []string{
+		"192.168.1.15", "192.168.2.0/24", "10.0.0.1-10.0.0.10", "2001:db8::/32", "0.0.0.0/0", "::/0", "0.0.0.0",
+		"192.168.1.256", "192.168.2.0/33", "10.0.0.10-10.0.0.1", "-", "/", "", " 10.0.0.1 ", "1.2.3.4-",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, ip string) {
+		if err := validateAllowedIPs([]string{ip}, true); err != nil {
+			t.Errorf("entry %q is rejected although allow_open_internet is true: %v", ip, err)
+		}
+
+		err := validateAllowedIPs([]string{ip}, false)
+		if err != nil && err.Error() == "" {
+			t.Errorf("entry %q is rejected without the error message", ip)
+		}
+
+		v := strings.TrimSpace(ip)
+		_, ipNet, errCIDR := net.ParseCIDR(v)
+		switch {
+		case errCIDR == nil:
+			// the CIDR is accepted unless it covers any IP address
+			if ones, _ := ipNet.Mask.Size(); (err == nil) != (ones != 0) {
+				t.Errorf("unexpected validation of the CIDR %q: %v", ip, err)
+			}
+		case net.ParseIP(v) != nil:
+			// the IP address is rejected only if it's unspecified
+			if err != nil && !net.ParseIP(v).IsUnspecified() {
+				t.Errorf("the IP address %q is rejected: %v", ip, err)
+			}
+		case err != nil && !strings.Contains(v, "-"):
+			// only the well-formed entries which cover any IP address are rejected
+			t.Errorf("the entry %q which is not IP address, CIDR, or range of IP addresses is rejected", ip)
+		}
+	})
+}
+
+func Test_protectDefaultBranch(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	t.Run("shall request the default branch protection", func(t *testing.T) {
+		// GIVEN
+		meta := &sdkClientStub{}
+		d := resourceProject().TestResourceData()
+		d.SetId("foo")
+		_ = d.Set("default_branch_id", "br-bar")
+
+		// WHEN
+		err := protectDefaultBranch(context.TODO(), d, meta)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		req, ok := meta.req.(neon.BranchUpdateRequest)
+		if !ok || req.Branch.Protected == nil || !*req.Branch.Protected {
+			t.Errorf("unexpected request: %v", meta.req)
+		}
+	})
+
+	t.Run("shall fail if the default branch is unknown", func(t *testing.T) {
+		d := resourceProject().TestResourceData()
+		d.SetId("foo")
+		if err := protectDefaultBranch(context.TODO(), d, &sdkClientStub{}); err == nil {
+			t.Errorf("error expected")
+		}
+	})
+}
+
+func Test_resourceProjectRead_enforceDefaultBranchProtection(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN the project which default branch protection was removed elsewhere
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := resourceProject().TestResourceData()
+	d.SetId(project.Project.ID)
+	_ = d.Set("enforce_default_branch_protection", true)
+
+	// WHEN
+	if err := resourceProjectRead(context.TODO(), d, client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN the configured flag is kept, and the drift is reported by the computed attribute
+	if !d.Get("enforce_default_branch_protection").(bool) {
+		t.Error("the configured enforce_default_branch_protection shall be kept")
+	}
+	if d.Get("default_branch_id").(string) != project.Branch.ID {
+		t.Errorf("unexpected default branch: %v", d.Get("default_branch_id"))
+	}
+	if d.Get("default_branch_protected").(bool) {
+		t.Error("the default branch shall be reported unprotected")
+	}
+}
+
+func Test_resourceProject_historyRetentionCache(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	srv := fakeapi.New()
+	srv.OperationDuration = 0
+	client, err := neon.NewClient(neon.Config{Key: "foo", HTTPClient: &countingHTTPClient{handler: srv}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := client.CreateProject(neon.ProjectCreateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projectID := project.Project.ID
+	t.Cleanup(func() { projectHistoryRetentionCache.Delete(projectID) })
+
+	d := resourceProject().TestResourceData()
+	d.SetId(projectID)
+	_ = d.Set("history_retention_seconds", 3600)
+
+	t.Run("shall evict the cached history retention upon update", func(t *testing.T) {
+		// GIVEN
+		projectHistoryRetentionCache.Store(projectID, 24*time.Hour)
+
+		// WHEN
+		if err := resourceProjectUpdate(context.TODO(), d, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if _, ok := projectHistoryRetentionCache.Load(projectID); ok {
+			t.Error("the cached history retention shall be evicted")
+		}
+	})
+
+	t.Run("shall evict the cached history retention upon deletion", func(t *testing.T) {
+		// GIVEN
+		projectHistoryRetentionCache.Store(projectID, time.Hour)
+
+		// WHEN
+		if err := resourceProjectDelete(context.TODO(), d, client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// THEN
+		if _, ok := projectHistoryRetentionCache.Load(projectID); ok {
+			t.Error("the cached history retention shall be evicted")
+		}
+	})
+}
+
+func Test_defaultEndpointSettingsToList(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		name       string
+		v          *neon.DefaultEndpointSettings
+		endpointID string
+		want       []interface{}
+	}{
+		{
+			name: "no settings",
+			want: nil,
+		},
+		{
+			name:       "endpoint ID only",
+			endpointID: "ep-foo",
+			want:       []interface{}{map[string]interface{}{"id": "ep-foo"}},
+		},
+		{
+			name: "all settings",
+			v: &neon.DefaultEndpointSettings{
+				AutoscalingLimitMinCu: pointer(neon.ComputeUnit(0.5)),
+				AutoscalingLimitMaxCu: pointer(neon.ComputeUnit(2)),
+				SuspendTimeoutSeconds: pointer(neon.SuspendTimeoutSeconds(600)),
+			},
+			endpointID: "ep-foo",
+			want: []interface{}{
+				map[string]interface{}{
+					"id":                       "ep-foo",
+					"autoscaling_limit_min_cu": 0.5,
+					"autoscaling_limit_max_cu": float64(2),
+					"suspend_timeout_seconds":  600,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, defaultEndpointSettingsToList(tt.v, tt.endpointID))
+		})
+	}
+}
+
+func Test_projectOrgID(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := map[string]struct {
+		orgID string
+		meta  interface{}
+		want  *string
+	}{
+		"shall use the project's organization": {
+			orgID: "org-foo",
+			meta:  &providerClient{orgID: "org-bar"},
+			want:  pointer("org-foo"),
+		},
+		"shall use the provider's default organization": {
+			meta: &providerClient{orgID: "org-bar"},
+			want: pointer("org-bar"),
+		},
+		"shall use the personal account": {
+			meta: &providerClient{},
+		},
+		"shall use the personal account given the client without defaults": {
+			meta: &sdkClientStub{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := resourceProject().TestResourceData()
+			if tt.orgID != "" {
+				_ = d.Set("org_id", tt.orgID)
+			}
+			assert.Equal(t, tt.want, projectOrgID(d, tt.meta))
+		})
+	}
+}
+
+func Test_projectQuotaUsage(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	t.Run("shall return the usage of the quotas set", func(t *testing.T) {
+		// GIVEN
+		project := neon.Project{
+			ActiveTimeSeconds: 900,
+			WrittenDataBytes:  80,
+			DataTransferBytes: 10,
+			Settings: &neon.ProjectSettingsData{
+				Quota: &neon.ProjectQuota{
+					ActiveTimeSeconds: pointer(int64(3600)),
+					WrittenDataBytes:  pointer(int64(100)),
+					DataTransferBytes: pointer(int64(0)),
+				},
+			},
+		}
+
+		// WHEN
+		got := projectQuotaUsage(project)
+
+		// THEN
+		assert.Equal(t, map[string]interface{}{
+			"active_time_seconds": 25.,
+			"written_data_bytes":  80.,
+		}, got)
+	})
+
+	t.Run("shall return no usage if no quota is set", func(t *testing.T) {
+		assert.Empty(t, projectQuotaUsage(neon.Project{ActiveTimeSeconds: 900}))
+	})
+}
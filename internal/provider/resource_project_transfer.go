@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func resourceProjectTransfer() *schema.Resource {
+	return &schema.Resource{
+		SchemaVersion: 1,
+		Description: `Transfer of the Project from the personal account to the organization.
+
+The transfer is executed upon creation, the project is transferred again if it was moved to another
+account afterwards. The transfer cannot be reverted using the API, hence the destruction of the resource
+only removes it from the Terraform state.
+
+**Note** that the attribute ` + "`org_id`" + ` of the resource ` + "`neon_project`" + ` shall be set to the
+organization ID, or ignored using ` + "`lifecycle { ignore_changes = [org_id] }`" + ` to prevent the project
+from being recreated after the transfer.
+
+API: https://api-docs.neon.tech/reference/transferprojectsfromusertoorg`,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CreateContext: resourceProjectTransferCreateRetry,
+		ReadContext:   resourceProjectTransferReadRetry,
+		DeleteContext: resourceProjectTransferDelete,
+		Schema: map[string]*schema.Schema{
+			"project_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Project ID.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the organization to transfer the project to.",
+			},
+		},
+	}
+}
+
+type sdkProjectTransfer interface {
+	GetProject(string) (neon.ProjectResponse, error)
+	TransferProjectsFromUserToOrg(neon.TransferProjectsToOrganizationRequest) (neon.EmptyResponse, error)
+}
+
+func resourceProjectTransferCreateRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return projectReadiness.Retry(resourceProjectTransferCreate, ctx, d, meta)
+}
+
+func resourceProjectTransferCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	projectID := d.Get("project_id").(string)
+	orgID := d.Get("org_id").(string)
+
+	tflog.Trace(ctx, "transfer Project", map[string]interface{}{"projectID": projectID, "orgID": orgID})
+
+	if _, err := meta.(sdkProjectTransfer).TransferProjectsFromUserToOrg(
+		neon.TransferProjectsToOrganizationRequest{OrgID: orgID, ProjectIDs: []string{projectID}},
+	); err != nil {
+		return err
+	}
+
+	d.SetId(projectID)
+	return resourceProjectTransferRead(ctx, d, meta)
+}
+
+func resourceProjectTransferReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return projectReadiness.RetryRead(resourceProjectTransferRead, ctx, d, meta)
+}
+
+func resourceProjectTransferRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	tflog.Trace(ctx, "read Project transfer")
+
+	resp, err := meta.(sdkProjectTransfer).GetProject(d.Id())
+	if err != nil {
+		return err
+	}
+
+	if err := d.Set("project_id", resp.Project.ID); err != nil {
+		return err
+	}
+
+	// the project moved to another account leads to the diff, hence to the transfer
+	var orgID string
+	if resp.Project.OrgID != nil {
+		orgID = *resp.Project.OrgID
+	}
+	return d.Set("org_id", orgID)
+}
+
+func resourceProjectTransferDelete(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.Info(ctx, "the project transfer cannot be reverted, remove it from the state only",
+		map[string]interface{}{"projectID": d.Id()})
+	d.SetId("")
+	return nil
+}
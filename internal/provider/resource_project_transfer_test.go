@@ -0,0 +1,103 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resourceProjectTransferCreate(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	const (
+		projectID = "shiny-wind-028834"
+		orgID     = "org-foo-12345678"
+	)
+
+	t.Run("shall transfer the project to the organization", func(t *testing.T) {
+		// GIVEN
+		d := resourceProjectTransfer().TestResourceData()
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("org_id", orgID)
+
+		meta := &stubProjectTransfer{project: neon.Project{ID: projectID}}
+
+		// WHEN
+		err := resourceProjectTransferCreate(context.TODO(), d, meta)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, neon.TransferProjectsToOrganizationRequest{OrgID: orgID, ProjectIDs: []string{projectID}}, meta.req)
+		assert.Equal(t, projectID, d.Id())
+		assert.Equal(t, orgID, d.Get("org_id"))
+	})
+
+	t.Run("shall fail if the transfer fails", func(t *testing.T) {
+		// GIVEN
+		d := resourceProjectTransfer().TestResourceData()
+		_ = d.Set("project_id", projectID)
+		_ = d.Set("org_id", orgID)
+
+		meta := &stubProjectTransfer{err: errors.New("foo")}
+
+		// WHEN
+		err := resourceProjectTransferCreate(context.TODO(), d, meta)
+
+		// THEN
+		assert.Error(t, err)
+		assert.Empty(t, d.Id())
+	})
+}
+
+func Test_resourceProjectTransferRead(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	const projectID = "shiny-wind-028834"
+
+	tests := map[string]struct {
+		orgID *string
+		want  string
+	}{
+		"shall read the organization of the project": {
+			orgID: pointer("org-foo-12345678"),
+			want:  "org-foo-12345678",
+		},
+		"shall detect the project moved to the personal account": {
+			want: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// GIVEN
+			d := resourceProjectTransfer().TestResourceData()
+			d.SetId(projectID)
+			_ = d.Set("org_id", "org-foo-12345678")
+
+			meta := &stubProjectTransfer{project: neon.Project{ID: projectID, OrgID: tt.orgID}}
+
+			// WHEN
+			err := resourceProjectTransferRead(context.TODO(), d, meta)
+
+			// THEN
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, projectID, d.Get("project_id"))
+			assert.Equal(t, tt.want, d.Get("org_id"))
+		})
+	}
+}
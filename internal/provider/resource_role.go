@@ -21,7 +21,9 @@ See details: https://neon.tech/docs/manage/users/
 		},
 		CreateContext: resourceRoleCreateRetry,
 		ReadContext:   resourceRoleReadRetry,
+		UpdateContext: resourceRoleUpdate,
 		DeleteContext: resourceRoleDeleteRetry,
+		CustomizeDiff: customizeDiffBranchName,
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
@@ -30,10 +32,20 @@ See details: https://neon.tech/docs/manage/users/
 				Description: "Project ID.",
 			},
 			"branch_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Branch ID.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"branch_id", "branch_name"},
+				Description:  "Branch ID.",
+			},
+			"branch_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"branch_id", "branch_name"},
+				Description: `Branch name. It's resolved to the branch ID upon plan, i.e. the resource is only replaced
+if the name refers to another branch than ` + "`branch_id`" + ` in the state.
+**Note** that it's the alternative to ` + "`branch_id`" + `.`,
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -50,6 +62,9 @@ See details: https://neon.tech/docs/manage/users/
 			"protected": {
 				Type:     schema.TypeBool,
 				Computed: true,
+				Description: `Whether the role is the system role protected by Neon. The password of the protected role
+is not revealed, and the protected role is only removed from the state upon destroy, because such role
+cannot be deleted.`,
 			},
 		},
 	}
@@ -77,12 +92,17 @@ func resourceRoleCreateRetry(ctx context.Context, d *schema.ResourceData, meta i
 func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "created Role")
 
+	branchID, err := branchIDFromConfig(d, meta.(sdkRole))
+	if err != nil {
+		return err
+	}
+
 	r := complexID{
 		ProjectID: d.Get("project_id").(string),
-		BranchID:  d.Get("branch_id").(string),
+		BranchID:  branchID,
 		Name:      d.Get("name").(string),
 	}
-	resp, err := meta.(*neon.Client).CreateProjectBranchRole(
+	resp, err := meta.(sdkRole).CreateProjectBranchRole(
 		r.ProjectID, r.BranchID, neon.RoleCreateRequest{
 			Role: neon.RoleCreateRequestRole{
 				Name: r.Name,
@@ -97,7 +117,7 @@ func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	role := resp.Role
 	if role.Password == nil {
-		r, err := meta.(*neon.Client).GetProjectBranchRolePassword(r.ProjectID, r.ProjectID, role.Name)
+		r, err := meta.(sdkRole).GetProjectBranchRolePassword(r.ProjectID, r.BranchID, role.Name)
 		if err != nil {
 			return err
 		}
@@ -107,8 +127,14 @@ func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interf
 	return updateStateRole(d, role)
 }
 
+// resourceRoleUpdate only stores the branch name in the state, the role is replaced if the branch changes.
+func resourceRoleUpdate(ctx context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	tflog.Trace(ctx, "update Role")
+	return nil
+}
+
 func resourceRoleReadRetry(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	return projectReadiness.Retry(resourceRoleRead, ctx, d, meta)
+	return projectReadiness.RetryRead(resourceRoleRead, ctx, d, meta)
 }
 
 func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
@@ -118,14 +144,14 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interfac
 	branchID, _ := d.Get("branch_id").(string)
 	name, _ := d.Get("name").(string)
 
-	resp, err := meta.(*neon.Client).GetProjectBranchRole(projectID, branchID, name)
+	resp, err := meta.(sdkRole).GetProjectBranchRole(projectID, branchID, name)
 	if err != nil {
 		return err
 	}
 
 	role := resp.Role
-	if role.Password == nil {
-		r, err := meta.(*neon.Client).GetProjectBranchRolePassword(projectID, branchID, name)
+	if role.Password == nil && !isProtectedRole(role) {
+		r, err := meta.(sdkRole).GetProjectBranchRolePassword(projectID, branchID, name)
 		if err != nil {
 			return err
 		}
@@ -141,7 +167,10 @@ func resourceRoleDeleteRetry(ctx context.Context, d *schema.ResourceData, meta i
 
 func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	tflog.Trace(ctx, "delete Role")
-	if _, err := meta.(*neon.Client).DeleteProjectBranchRole(
+	if d.Get("protected").(bool) {
+		tflog.Warn(ctx, "protected Role cannot be deleted, it's removed from the state only",
+			map[string]interface{}{"roleID": d.Id()})
+	} else if _, err := meta.(sdkRole).DeleteProjectBranchRole(
 		d.Get("project_id").(string),
 		d.Get("branch_id").(string),
 		d.Get("name").(string),
@@ -174,3 +203,15 @@ func resourceRoleImport(ctx context.Context, d *schema.ResourceData, meta interf
 	}
 	return []*schema.ResourceData{d}, nil
 }
+
+func isProtectedRole(v neon.Role) bool {
+	return v.Protected != nil && *v.Protected
+}
+
+type sdkRole interface {
+	sdkBranchLister
+	CreateProjectBranchRole(string, string, neon.RoleCreateRequest) (neon.RoleOperations, error)
+	GetProjectBranchRole(string, string, string) (neon.RoleResponse, error)
+	GetProjectBranchRolePassword(string, string, string) (neon.RolePasswordResponse, error)
+	DeleteProjectBranchRole(string, string, string) (neon.RoleOperations, error)
+}
@@ -0,0 +1,98 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_resourceRoleCreate_password(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v2/projects/bar/branches/br-baz/roles":
+				return newHTTPResponse(http.StatusOK, `{"role":{"branch_id":"br-baz","name":"qux"}}`), nil
+			case r.Method == http.MethodGet &&
+				r.URL.Path == "/api/v2/projects/bar/branches/br-baz/roles/qux/reveal_password":
+				return newHTTPResponse(http.StatusOK, `{"password":"secret"}`), nil
+			}
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return nil, nil
+		}),
+	})
+
+	d := resourceRole().TestResourceData()
+	_ = d.Set("project_id", "bar")
+	_ = d.Set("branch_id", "br-baz")
+	_ = d.Set("name", "qux")
+
+	// WHEN
+	err := resourceRoleCreate(context.TODO(), d, client)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := d.Get("password").(string); v != "secret" {
+		t.Errorf("unexpected password: %s", v)
+	}
+}
+
+func Test_resourceRole_protected(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	client, _ := neon.NewClient(neon.Config{
+		Key: "foo",
+		HTTPClient: httpClientStubFn(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodGet && r.URL.Path == "/api/v2/projects/bar/branches/br-baz/roles/qux" {
+				return newHTTPResponse(http.StatusOK,
+					`{"role":{"branch_id":"br-baz","name":"qux","protected":true}}`), nil
+			}
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			return nil, nil
+		}),
+	})
+
+	d := resourceRole().TestResourceData()
+	d.SetId("bar/br-baz/qux")
+
+	// WHEN
+	_, err := resourceRoleImport(context.TODO(), d, client)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.Get("protected").(bool) {
+		t.Error("the role shall be protected")
+	}
+	if d.Get("project_id") != "bar" || d.Get("branch_id") != "br-baz" || d.Get("name") != "qux" {
+		t.Errorf("unexpected attributes: %v, %v, %v", d.Get("project_id"), d.Get("branch_id"), d.Get("name"))
+	}
+
+	// WHEN
+	err = resourceRoleDelete(context.TODO(), d, client)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("the role shall be removed from the state, got: %s", d.Id())
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -21,35 +22,195 @@ func (r *delay) Retry(
 	fn func(context.Context, *schema.ResourceData, interface{}) error,
 	ctx context.Context, d *schema.ResourceData, meta interface{},
 ) diag.Diagnostics {
-	var i uint8
-	var err error
+	report, err := r.retryWithReport(fn, ctx, d, meta)
+	deprecations := apiDeprecationDiagnostics(ctx, meta)
+	if err != nil {
+		return append(diag.FromErr(err), deprecations...)
+	}
+	return append(report.diagnostics(d.Id()), deprecations...)
+}
+
+// RetryRead retries the read function similarly to Retry, but it returns the warning instead of the error
+// if the project remains locked, e.g. because of the running operations. It prevents failure of the whole
+// refresh because of the project in transitional state, the resource's state remains unchanged in such case.
+func (r *delay) RetryRead(
+	fn func(context.Context, *schema.ResourceData, interface{}) error,
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) diag.Diagnostics {
+	report, err := r.retryWithReport(fn, ctx, d, meta)
+	deprecations := apiDeprecationDiagnostics(ctx, meta)
+	if e, ok := err.(neon.Error); ok && e.HTTPCode == http.StatusLocked {
+		return append(diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  "the project is locked, the state of " + d.Id() + " was not refreshed",
+				Detail:   e.Error(),
+			},
+		}, deprecations...)
+	}
+	if err != nil {
+		return append(diag.FromErr(err), deprecations...)
+	}
+	return append(report.diagnostics(d.Id()), deprecations...)
+}
+
+func (r *delay) retry(
+	fn func(context.Context, *schema.ResourceData, interface{}) error,
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) error {
+	_, err := r.retryWithReport(fn, ctx, d, meta)
+	return err
+}
+
+// retryReport summarizes the retries of the API call which eventually succeeded.
+type retryReport struct {
+	// transient defines the number of the transient errors, i.e. the rate limit, and the server errors.
+	// The locked project is not accounted because it's expected while the project's operations run.
+	transient int
+	delay     time.Duration
+	lastErr   error
+}
+
+// diagnostics returns the warning if the API call recovered from the transient errors.
+func (r retryReport) diagnostics(id string) diag.Diagnostics {
+	if r.transient == 0 {
+		return nil
+	}
+	subject := "the API call"
+	if id != "" {
+		subject += " for " + id
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  subject + " succeeded after " + strconv.Itoa(r.transient) + " transient errors",
+			Detail: "The API call was retried with the total delay of " + r.delay.String() +
+				". The last error: " + r.lastErr.Error(),
+		},
+	}
+}
+
+// retryWithReport retries the function unless the API appears unavailable, see circuitBreaker.
+func (r *delay) retryWithReport(
+	fn func(context.Context, *schema.ResourceData, interface{}) error,
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) (retryReport, error) {
+	breaker := apiCircuitBreaker(meta)
+	if err := breaker.check(); err != nil {
+		return retryReport{}, err
+	}
+
+	report, err := r.retryAttempts(fn, ctx, d, meta)
+	breaker.record(err)
+	return report, err
+}
+
+func (r *delay) retryAttempts(
+	fn func(context.Context, *schema.ResourceData, interface{}) error,
+	ctx context.Context, d *schema.ResourceData, meta interface{},
+) (retryReport, error) {
+	var (
+		i      uint8
+		err    error
+		report retryReport
+	)
 	for i < r.maxCnt {
 		tflog.Debug(ctx, "API call attempt "+strconv.Itoa(int(i)))
 
 		switch e := fn(ctx, d, meta).(type) {
 		case nil:
-			return nil
+			return report, nil
 		case neon.Error:
 			tflog.Debug(ctx, "API call error code: "+strconv.Itoa(e.HTTPCode))
 			switch e.HTTPCode {
 			case 200:
-				return nil
+				return report, nil
 			case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusLocked:
 				tflog.Debug(ctx, "API call delay "+strconv.FormatInt(r.delay.Milliseconds(), 10)+" ms.")
+				if e.HTTPCode != http.StatusLocked {
+					report.transient++
+					report.lastErr = e
+				}
+				report.delay += r.delay
 				err = e
 				i++
-				time.Sleep(r.delay)
+				if err := sleep(ctx, r.delay); err != nil {
+					return report, err
+				}
 			default:
-				return diag.FromErr(e)
+				return report, e
 			}
 		default:
-			return diag.FromErr(e)
+			return report, e
 		}
 	}
-	return diag.FromErr(err)
+	return report, err
 }
 
 var projectReadiness = delay{
 	delay:  1 * time.Second,
 	maxCnt: 120,
 }
+
+var deletionReadiness = delay{
+	delay:  1 * time.Second,
+	maxCnt: 120,
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(neon.Error)
+	return ok && e.HTTPCode == http.StatusNotFound
+}
+
+// waitDeleted polls the resource using the function get until it returns the "not found" error.
+func waitDeleted(ctx context.Context, get func() error) error {
+	for i := uint8(0); i < deletionReadiness.maxCnt; i++ {
+		switch e := get().(type) {
+		case nil:
+			tflog.Debug(ctx, "resource deletion is still in progress")
+		case neon.Error:
+			switch e.HTTPCode {
+			case http.StatusNotFound:
+				return nil
+			case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusLocked:
+				tflog.Debug(ctx, "API call error code: "+strconv.Itoa(e.HTTPCode))
+			default:
+				return e
+			}
+		default:
+			return e
+		}
+		if err := sleep(ctx, deletionReadiness.delay); err != nil {
+			return err
+		}
+	}
+	return errors.New("timeout waiting for the resource deletion")
+}
+
+// poll calls the function check after every interval until it reports done, or the timeout elapses.
+// It returns false if the timeout elapses, and the context's error if the context is done earlier.
+func poll(ctx context.Context, interval, timeout time.Duration, check func() (bool, error)) (bool, error) {
+	for start := time.Now(); time.Since(start) < timeout; {
+		if err := sleep(ctx, interval); err != nil {
+			return false, err
+		}
+		if done, err := check(); err != nil || done {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+// sleep pauses for the duration d, it returns the context's error if the context is done earlier,
+// e.g. if terraform is interrupted.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,295 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+)
+
+func Test_waitDeleted(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := deletionReadiness.delay
+	deletionReadiness.delay = 0
+	t.Cleanup(func() { deletionReadiness.delay = defaultDelay })
+
+	t.Run("shall wait until the resource is not found", func(t *testing.T) {
+		// GIVEN
+		var cnt int
+		get := func() error {
+			cnt++
+			switch cnt {
+			case 1:
+				return nil
+			case 2:
+				return neon.Error{HTTPCode: http.StatusLocked}
+			}
+			return neon.Error{HTTPCode: http.StatusNotFound}
+		}
+
+		// WHEN
+		err := waitDeleted(context.TODO(), get)
+
+		// THEN
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cnt != 3 {
+			t.Errorf("unexpected number of calls: %d", cnt)
+		}
+	})
+
+	t.Run("shall fail on unexpected error", func(t *testing.T) {
+		err := waitDeleted(context.TODO(), func() error { return errors.New("foo") })
+		if err == nil || err.Error() != "foo" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("shall fail if the resource is not deleted in time", func(t *testing.T) {
+		err := waitDeleted(context.TODO(), func() error { return nil })
+		if err == nil {
+			t.Errorf("error expected")
+		}
+	})
+}
+
+func Test_delay_RetryRead(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	r := delay{delay: 0, maxCnt: 3}
+	d := resourceBranch().TestResourceData()
+	d.SetId("br-foo")
+
+	t.Run("shall return warning if the project remains locked", func(t *testing.T) {
+		// GIVEN
+		var cnt int
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			cnt++
+			return neon.Error{HTTPCode: http.StatusLocked}
+		}
+
+		// WHEN
+		diags := r.RetryRead(fn, context.TODO(), d, nil)
+
+		// THEN
+		if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+			t.Errorf("unexpected diagnostics: %v", diags)
+		}
+		if cnt != 3 {
+			t.Errorf("unexpected number of attempts: %d", cnt)
+		}
+	})
+
+	t.Run("shall return nil if the project is unlocked in time", func(t *testing.T) {
+		var cnt int
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			cnt++
+			if cnt < 2 {
+				return neon.Error{HTTPCode: http.StatusLocked}
+			}
+			return nil
+		}
+		if diags := r.RetryRead(fn, context.TODO(), d, nil); diags != nil {
+			t.Errorf("unexpected diagnostics: %v", diags)
+		}
+	})
+
+	t.Run("shall return error otherwise", func(t *testing.T) {
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			return neon.Error{HTTPCode: http.StatusNotFound}
+		}
+		if diags := r.RetryRead(fn, context.TODO(), d, nil); !diags.HasError() {
+			t.Errorf("error expected, got: %v", diags)
+		}
+	})
+}
+
+func Test_delay_Retry_report(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	r := delay{delay: 0, maxCnt: 5}
+	d := resourceBranch().TestResourceData()
+	d.SetId("br-foo")
+
+	t.Run("shall warn if the call recovered from the transient errors", func(t *testing.T) {
+		// GIVEN
+		var cnt int
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			cnt++
+			switch cnt {
+			case 1:
+				return neon.Error{HTTPCode: http.StatusTooManyRequests}
+			case 2:
+				return neon.Error{HTTPCode: http.StatusLocked}
+			case 3:
+				return neon.Error{HTTPCode: http.StatusInternalServerError}
+			}
+			return nil
+		}
+
+		// WHEN
+		diags := r.Retry(fn, context.TODO(), d, nil)
+
+		// THEN
+		if diags.HasError() || len(diags) != 1 || diags[0].Severity != diag.Warning {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if want := "the API call for br-foo succeeded after 2 transient errors"; diags[0].Summary != want {
+			t.Errorf("unexpected summary: %s", diags[0].Summary)
+		}
+	})
+
+	t.Run("shall not warn if the project was locked only", func(t *testing.T) {
+		var cnt int
+		fn := func(context.Context, *schema.ResourceData, interface{}) error {
+			cnt++
+			if cnt < 3 {
+				return neon.Error{HTTPCode: http.StatusLocked}
+			}
+			return nil
+		}
+		if diags := r.Retry(fn, context.TODO(), d, nil); diags != nil {
+			t.Errorf("unexpected diagnostics: %v", diags)
+		}
+	})
+}
+
+func Test_delay_Retry_canceled(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	// GIVEN
+	r := delay{delay: time.Hour, maxCnt: 3}
+	ctx, cancel := context.WithCancel(context.TODO())
+	var cnt int
+	fn := func(context.Context, *schema.ResourceData, interface{}) error {
+		cnt++
+		cancel()
+		return neon.Error{HTTPCode: http.StatusLocked}
+	}
+
+	// WHEN
+	done := make(chan error, 1)
+	go func() { done <- r.retry(fn, ctx, resourceBranch().TestResourceData(), nil) }()
+
+	// THEN
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if cnt != 1 {
+			t.Errorf("unexpected number of calls: %d", cnt)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the retry shall stop once the context is canceled")
+	}
+}
+
+func Test_waitDeleted_canceled(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	defaultDelay := deletionReadiness.delay
+	deletionReadiness.delay = time.Hour
+	t.Cleanup(func() { deletionReadiness.delay = defaultDelay })
+
+	// GIVEN
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	// WHEN
+	err := waitDeleted(ctx, func() error { return nil })
+
+	// THEN
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func Test_poll(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	t.Run("shall poll until done", func(t *testing.T) {
+		// GIVEN
+		var cnt int
+		check := func() (bool, error) {
+			cnt++
+			return cnt == 3, nil
+		}
+
+		// WHEN
+		done, err := poll(context.TODO(), 0, time.Minute, check)
+
+		// THEN
+		if err != nil || !done {
+			t.Errorf("unexpected result: %v, %v", done, err)
+		}
+		if cnt != 3 {
+			t.Errorf("unexpected number of calls: %d", cnt)
+		}
+	})
+
+	t.Run("shall stop on the error", func(t *testing.T) {
+		// GIVEN
+		wantErr := errors.New("foo")
+
+		// WHEN
+		_, err := poll(context.TODO(), 0, time.Minute, func() (bool, error) { return false, wantErr })
+
+		// THEN
+		if !errors.Is(err, wantErr) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("shall return false when the timeout elapses", func(t *testing.T) {
+		// WHEN
+		done, err := poll(context.TODO(), time.Millisecond, 10*time.Millisecond, func() (bool, error) {
+			return false, nil
+		})
+
+		// THEN
+		if err != nil || done {
+			t.Errorf("unexpected result: %v, %v", done, err)
+		}
+	})
+
+	t.Run("shall stop once the context is done", func(t *testing.T) {
+		// GIVEN
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		// WHEN
+		_, err := poll(ctx, time.Hour, time.Minute, func() (bool, error) {
+			t.Fatal("the check shall not be called")
+			return false, nil
+		})
+
+		// THEN
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+//go:build !acceptance
+// +build !acceptance
+
+package provider
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	neon "github.com/kislerdm/neon-sdk-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// The golden tests map the API responses testdata/golden/<fixture>.json to the resource state,
+// and compare the state attributes with testdata/golden/<fixture>.golden.json.
+// Run the tests with the flag -update-golden to update the golden files, e.g. when the new attribute is added:
+//
+//	go test ./internal/provider -run Test_updateStateGolden -update-golden
+var updateGolden = flag.Bool("update-golden", false, "update the golden files of the state mapping tests")
+
+func assertGoldenState(t *testing.T, fixture string, d *schema.ResourceData) {
+	t.Helper()
+
+	got := d.State().Attributes
+	path := filepath.Join("testdata", "golden", fixture+".golden.json")
+
+	if *updateGolden {
+		b, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read the golden file, run the test with the flag -update-golden to create it: %v", err)
+	}
+	var want map[string]string
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, got)
+}
+
+func readGoldenFixture(t *testing.T, fixture string, v interface{}) {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("testdata", "golden", fixture+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_updateStateGolden(t *testing.T) {
+	if os.Getenv("TF_ACC") == "1" {
+		t.Skip("acceptance tests are running")
+	}
+
+	tests := []struct {
+		fixture  string
+		resource *schema.Resource
+		id       string
+		update   func(t *testing.T, d *schema.ResourceData, fixture string) error
+	}{
+		{
+			fixture:  "branch_full",
+			resource: resourceBranch(),
+			id:       "br-wispy-dew-591433",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Branch
+				readGoldenFixture(t, fixture, &v)
+				return updateStateBranch(d, v, "")
+			},
+		},
+		{
+			fixture:  "branch_root",
+			resource: resourceBranch(),
+			id:       "br-aged-salad-637688",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Branch
+				readGoldenFixture(t, fixture, &v)
+				return updateStateBranch(d, v, "")
+			},
+		},
+		{
+			fixture:  "endpoint_full",
+			resource: resourceEndpoint(),
+			id:       "ep-cool-darkness-123456",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Endpoint
+				readGoldenFixture(t, fixture, &v)
+				return updateStateEndpoint(d, v, "")
+			},
+		},
+		{
+			fixture:  "endpoint_minimal",
+			resource: resourceEndpoint(),
+			id:       "ep-young-frog-654321",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Endpoint
+				readGoldenFixture(t, fixture, &v)
+				return updateStateEndpoint(d, v, "")
+			},
+		},
+		{
+			fixture:  "role_with_password",
+			resource: resourceRole(),
+			id:       "shiny-wind-028834/br-wispy-dew-591433/alex",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Role
+				readGoldenFixture(t, fixture, &v)
+				return updateStateRole(d, v)
+			},
+		},
+		{
+			fixture:  "role_protected",
+			resource: resourceRole(),
+			id:       "shiny-wind-028834/br-wispy-dew-591433/neon_superuser",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Role
+				readGoldenFixture(t, fixture, &v)
+				return updateStateRole(d, v)
+			},
+		},
+		{
+			fixture:  "database",
+			resource: resourceDatabase(),
+			id:       "shiny-wind-028834/br-wispy-dew-591433/neondb",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Database
+				readGoldenFixture(t, fixture, &v)
+				return updateStateDatabase(d, v)
+			},
+		},
+		{
+			fixture:  "project_full",
+			resource: resourceProject(),
+			id:       "shiny-wind-028834",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Project
+				readGoldenFixture(t, fixture, &v)
+				return updateStateProject(d, v, "br-aged-salad-637688", "main", dbConnectionInfo{
+					userName:   "alex",
+					pass:       "Onf1AjayKwe0",
+					dbName:     "neondb",
+					host:       "ep-cool-darkness-123456.us-east-2.aws.neon.tech",
+					endpointID: "ep-cool-darkness-123456",
+				}, "")
+			},
+		},
+		{
+			fixture:  "project_partial_settings",
+			resource: resourceProject(),
+			id:       "shiny-wind-028834",
+			update: func(t *testing.T, d *schema.ResourceData, fixture string) error {
+				var v neon.Project
+				readGoldenFixture(t, fixture, &v)
+				return updateStateProject(d, v, "br-aged-salad-637688", "main", dbConnectionInfo{}, "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			d := tt.resource.TestResourceData()
+			d.SetId(tt.id)
+
+			if err := tt.update(t, d, tt.fixture); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertGoldenState(t, tt.fixture, d)
+		})
+	}
+}
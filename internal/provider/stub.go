@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,6 +28,11 @@ func (s *sdkClientStub) ListProjectBranches(_ string, _ *string) (neon.ListProje
 	return neon.ListProjectBranchesRespObj{}, nil
 }
 
+func (s *sdkClientStub) UpdateProjectBranch(_ string, _ string, cfg neon.BranchUpdateRequest) (neon.BranchOperations, error) {
+	s.req = cfg
+	return neon.BranchOperations{}, s.err
+}
+
 func (s *sdkClientStub) ListProjectBranchEndpoints(_ string, _ string) (neon.EndpointsResponse, error) {
 	panic("implement me")
 }
@@ -96,3 +102,90 @@ func (s *stubProjectPermission) ListProjectPermissions(_ string) (neon.ProjectPe
 	}
 	return s.ProjectPermissions, nil
 }
+
+// stubDatabase in-memory stub of the databases API.
+type stubDatabase struct {
+	Branches  []neon.Branch
+	Databases map[string]neon.Database
+	err       error
+}
+
+func (s *stubDatabase) ListProjectBranches(_ string, _ *string) (neon.ListProjectBranchesRespObj, error) {
+	var o neon.ListProjectBranchesRespObj
+	o.Branches = s.Branches
+	return o, s.err
+}
+
+func (s *stubDatabase) CreateProjectBranchDatabase(_ string, branchID string, cfg neon.DatabaseCreateRequest) (
+	neon.DatabaseOperations, error,
+) {
+	if s.err != nil {
+		return neon.DatabaseOperations{}, s.err
+	}
+	if s.Databases == nil {
+		s.Databases = map[string]neon.Database{}
+	}
+	v := neon.Database{BranchID: branchID, Name: cfg.Database.Name, OwnerName: cfg.Database.OwnerName}
+	s.Databases[cfg.Database.Name] = v
+	return neon.DatabaseOperations{DatabaseResponse: neon.DatabaseResponse{Database: v}}, nil
+}
+
+func (s *stubDatabase) GetProjectBranchDatabase(_ string, _ string, name string) (neon.DatabaseResponse, error) {
+	if s.err != nil {
+		return neon.DatabaseResponse{}, s.err
+	}
+	v, ok := s.Databases[name]
+	if !ok {
+		return neon.DatabaseResponse{}, neon.Error{HTTPCode: http.StatusNotFound}
+	}
+	return neon.DatabaseResponse{Database: v}, nil
+}
+
+func (s *stubDatabase) UpdateProjectBranchDatabase(_ string, _ string, name string, cfg neon.DatabaseUpdateRequest) (
+	neon.DatabaseOperations, error,
+) {
+	if s.err != nil {
+		return neon.DatabaseOperations{}, s.err
+	}
+	v, ok := s.Databases[name]
+	if !ok {
+		return neon.DatabaseOperations{}, neon.Error{HTTPCode: http.StatusNotFound}
+	}
+	delete(s.Databases, name)
+	if cfg.Database.Name != nil {
+		v.Name = *cfg.Database.Name
+	}
+	if cfg.Database.OwnerName != nil {
+		v.OwnerName = *cfg.Database.OwnerName
+	}
+	s.Databases[v.Name] = v
+	return neon.DatabaseOperations{DatabaseResponse: neon.DatabaseResponse{Database: v}}, nil
+}
+
+func (s *stubDatabase) DeleteProjectBranchDatabase(_ string, _ string, name string) (neon.DatabaseOperations, error) {
+	if s.err != nil {
+		return neon.DatabaseOperations{}, s.err
+	}
+	delete(s.Databases, name)
+	return neon.DatabaseOperations{}, nil
+}
+
+type stubProjectTransfer struct {
+	project neon.Project
+	req     neon.TransferProjectsToOrganizationRequest
+	err     error
+}
+
+func (s *stubProjectTransfer) GetProject(_ string) (neon.ProjectResponse, error) {
+	return neon.ProjectResponse{Project: s.project}, s.err
+}
+
+func (s *stubProjectTransfer) TransferProjectsFromUserToOrg(cfg neon.TransferProjectsToOrganizationRequest) (
+	neon.EmptyResponse, error,
+) {
+	s.req = cfg
+	if s.err == nil {
+		s.project.OrgID = &cfg.OrgID
+	}
+	return neon.EmptyResponse{}, s.err
+}
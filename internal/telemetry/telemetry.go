@@ -2,11 +2,21 @@
 package telemetry
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
+// DefaultBaseURL defines the base URL of the Neon API used by the SDK.
+const DefaultBaseURL = "https://console.neon.tech/api/v2"
+
+// ErrReadOnly is returned when the request is rejected because the client is read-only.
+var ErrReadOnly = errors.New("the provider is configured with read_only = true")
+
 // NewHTTPClient init the HTTP client to send HTTP request with required telemetry headers.
 func NewHTTPClient(providerName, providerVersion, tfVersion string) *HTTPClient {
 	return &HTTPClient{
@@ -23,11 +33,44 @@ type HTTPClient struct {
 	ProviderVersion string
 	TfVersion       string
 
+	// CorrelationHeader and CorrelationID define the header attached to every request
+	// to trace the API calls, e.g. to relate them to the Terraform run.
+	CorrelationHeader string
+	CorrelationID     string
+
+	// ReadOnly defines if only the requests which do not mutate the resources are permitted.
+	ReadOnly bool
+
+	// BaseURL defines the base URL to send the API calls to instead of DefaultBaseURL,
+	// e.g. to run against the fake API.
+	BaseURL string
+
 	c *http.Client
 }
 
+// SetTLSConfig sets the TLS configuration to establish connections with.
+func (c *HTTPClient) SetTLSConfig(cfg *tls.Config) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	c.c.Transport = t
+}
+
+// SetTransport sets the transport to send the requests with, e.g. the in-process fake API.
+func (c *HTTPClient) SetTransport(t http.RoundTripper) {
+	c.c.Transport = t
+}
+
 func (c HTTPClient) Do(r *http.Request) (*http.Response, error) {
+	if c.ReadOnly && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, fmt.Errorf("%w: %s %s is rejected", ErrReadOnly, r.Method, r.URL.Path)
+	}
+
+	if err := c.rewriteBaseURL(r); err != nil {
+		return nil, err
+	}
+
 	c.setUAHeader(r)
+	c.setCorrelationHeader(r)
 
 	return c.c.Do(r)
 }
@@ -44,3 +87,32 @@ func (c HTTPClient) setUAHeader(r *http.Request) {
 		r.Header.Set("User-Agent", telemetryHeader)
 	}
 }
+
+func (c HTTPClient) setCorrelationHeader(r *http.Request) {
+	if c.CorrelationHeader != "" && c.CorrelationID != "" {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+
+		r.Header.Set(c.CorrelationHeader, c.CorrelationID)
+	}
+}
+
+func (c HTTPClient) rewriteBaseURL(r *http.Request) error {
+	if c.BaseURL == "" || c.BaseURL == DefaultBaseURL {
+		return nil
+	}
+
+	v := r.URL.String()
+	if !strings.HasPrefix(v, DefaultBaseURL) {
+		return nil
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(c.BaseURL, "/") + strings.TrimPrefix(v, DefaultBaseURL))
+	if err != nil {
+		return fmt.Errorf("invalid base URL %s: %w", c.BaseURL, err)
+	}
+	r.URL = u
+	r.Host = u.Host
+	return nil
+}
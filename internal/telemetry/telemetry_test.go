@@ -1,7 +1,9 @@
 package telemetry
 
 import (
+	"crypto/tls"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -57,3 +59,119 @@ func TestHTTPClient_setUAHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPClient_setCorrelationHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		id     string
+		want   http.Header
+	}{
+		{
+			name:   "header is set",
+			header: "X-Correlation-ID",
+			id:     "run-foo",
+			want:   http.Header{"X-Correlation-Id": []string{"run-foo"}},
+		},
+		{
+			name:   "ID is not set",
+			header: "X-Correlation-ID",
+			want:   nil,
+		},
+		{
+			name: "header name is not set",
+			id:   "run-foo",
+			want: nil,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHTTPClient("Foo", "1.0.0", "1.5.7")
+			c.CorrelationHeader = tt.header
+			c.CorrelationID = tt.id
+
+			var r = &http.Request{}
+			c.setCorrelationHeader(r)
+			assert.Equal(t, tt.want, r.Header)
+		})
+	}
+}
+
+func TestHTTPClient_SetTLSConfig(t *testing.T) {
+	c := NewHTTPClient("Foo", "1.0.0", "1.5.7")
+	c.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS13})
+
+	tr, ok := c.c.Transport.(*http.Transport)
+	assert.True(t, ok)
+	assert.Equal(t, uint16(tls.VersionTLS13), tr.TLSClientConfig.MinVersion)
+	assert.NotSame(t, http.DefaultTransport, tr)
+}
+
+func TestHTTPClient_Do_readOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewHTTPClient("Foo", "1.0.0", "1.5.7")
+	c.ReadOnly = true
+
+	t.Run("shall permit GET request", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, srv.URL+"/projects", nil)
+		resp, err := c.Do(r)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		t.Run("shall reject "+method+" request", func(t *testing.T) {
+			r, _ := http.NewRequest(method, srv.URL+"/projects", nil)
+			_, err := c.Do(r)
+			assert.ErrorIs(t, err, ErrReadOnly)
+		})
+	}
+}
+
+func TestHTTPClient_rewriteBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		url     string
+		want    string
+	}{
+		{
+			name:    "base URL is not set",
+			baseURL: "",
+			url:     DefaultBaseURL + "/projects?limit=1",
+			want:    DefaultBaseURL + "/projects?limit=1",
+		},
+		{
+			name:    "base URL is set",
+			baseURL: "http://localhost:8080/api/v2/",
+			url:     DefaultBaseURL + "/projects?limit=1",
+			want:    "http://localhost:8080/api/v2/projects?limit=1",
+		},
+		{
+			name:    "request to another host",
+			baseURL: "http://localhost:8080/api/v2",
+			url:     "https://foo.com/projects",
+			want:    "https://foo.com/projects",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHTTPClient("Foo", "1.0.0", "1.5.7")
+			c.BaseURL = tt.baseURL
+
+			r, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			assert.NoError(t, c.rewriteBaseURL(r))
+			assert.Equal(t, tt.want, r.URL.String())
+		})
+	}
+}
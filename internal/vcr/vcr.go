@@ -0,0 +1,210 @@
+// Package vcr defines the HTTP client to record the API calls to the cassette file, and to replay them.
+// It allows to run the acceptance tests deterministically without access to the Neon API.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode defines the recorder's mode.
+type Mode string
+
+const (
+	// ModeDisabled the requests are sent to the API, no interactions are recorded.
+	ModeDisabled Mode = ""
+	// ModeRecord the requests are sent to the API, the interactions are recorded to the cassette.
+	ModeRecord Mode = "record"
+	// ModeReplay the requests are served from the cassette, no requests are sent to the API.
+	ModeReplay Mode = "replay"
+)
+
+// HTTPClient defines the client to communicate with the API.
+type HTTPClient interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// Request recorded request.
+// Note that the headers are not recorded to avoid leaking the API key.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response recorded response.
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Interaction recorded pair of the request and response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Recorder the HTTP client which records, or replays the interactions.
+type Recorder struct {
+	mode     Mode
+	cassette string
+	client   HTTPClient
+
+	// Sanitize modifies the recorded body, e.g. to redact the secrets.
+	Sanitize func(body string) string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New initializes the recorder.
+// The cassette is read from the file in the replay mode. The client is used in the record and disabled modes.
+func New(mode Mode, cassette string, client HTTPClient) (*Recorder, error) {
+	o := &Recorder{
+		mode:     mode,
+		cassette: cassette,
+		client:   client,
+	}
+
+	switch mode {
+	case ModeDisabled, ModeRecord:
+		if client == nil {
+			return nil, errors.New("HTTP client must be set to send requests in the mode " + string(mode))
+		}
+	case ModeReplay:
+		b, err := os.ReadFile(cassette)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the cassette: %w", err)
+		}
+		if err := json.Unmarshal(b, &o.interactions); err != nil {
+			return nil, fmt.Errorf("cannot decode the cassette %s: %w", cassette, err)
+		}
+		o.used = make([]bool, len(o.interactions))
+	default:
+		return nil, errors.New("unknown mode " + string(mode))
+	}
+
+	return o, nil
+}
+
+// Do sends the request, or serves it from the cassette depending on the mode.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Body:   r.sanitize(reqBody),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(rec)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil || r.mode != ModeRecord {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: rec,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    map[string]string{"Content-Type": resp.Header.Get("Content-Type")},
+			Body:       r.sanitize(string(respBody)),
+		},
+	})
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request's method, path, query and body.
+// Note that the tests shall generate the deterministic values, e.g. the resources' names, to be replayed.
+func (r *Recorder) replay(req Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, v := range r.interactions {
+		if r.used[i] || v.Request != req {
+			continue
+		}
+		r.used[i] = true
+
+		o := &http.Response{
+			StatusCode: v.Response.StatusCode,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader([]byte(v.Response.Body))),
+		}
+		for k, h := range v.Response.Headers {
+			o.Header.Set(k, h)
+		}
+		return o, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction found for %s %s?%s with the body %q in the cassette %s",
+		req.Method, req.Path, req.Query, req.Body, r.cassette)
+}
+
+// Save writes the recorded interactions to the cassette file. It's noop unless the recorder is in the record mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.cassette), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.cassette, b, 0o600)
+}
+
+func (r *Recorder) sanitize(body string) string {
+	if r.Sanitize == nil || body == "" {
+		return body
+	}
+	return r.Sanitize(body)
+}
+
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return string(b), nil
+}
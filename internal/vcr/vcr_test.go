@@ -0,0 +1,140 @@
+package vcr
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type httpClientFn func(r *http.Request) (*http.Response, error)
+
+func (f httpClientFn) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestRecorder(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "fixtures", "foo.json")
+
+	// GIVEN the interactions recorded with the live API
+	var cnt int
+	live := httpClientFn(func(r *http.Request) (*http.Response, error) {
+		cnt++
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		b, _ := io.ReadAll(r.Body)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(
+				`{"id":"bar","request":` + string(b) + `,"password":"qux"}`,
+			)),
+		}, nil
+	})
+
+	rec, err := New(ModeRecord, cassette, live)
+	assert.NoError(t, err)
+	rec.Sanitize = func(body string) string { return strings.ReplaceAll(body, "qux", "REDACTED") }
+
+	req, _ := http.NewRequest(http.MethodPost, "https://console.neon.tech/api/v2/projects?foo=bar",
+		bytes.NewReader([]byte(`{"name":"baz"}`)))
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := rec.Do(req)
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":"bar","request":{"name":"baz"},"password":"qux"}`, string(b),
+		"the caller shall receive the original response")
+	assert.NoError(t, rec.Save())
+
+	// WHEN the interactions are replayed
+	replay, err := New(ModeReplay, cassette, nil)
+	assert.NoError(t, err)
+
+	req, _ = http.NewRequest(http.MethodPost, "https://console.neon.tech/api/v2/projects?foo=bar",
+		bytes.NewReader([]byte(`{"name":"baz"}`)))
+	resp, err = replay.Do(req)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cnt, "no requests shall be sent to the API upon replay")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	b, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, `{"id":"bar","request":{"name":"baz"},"password":"REDACTED"}`, string(b))
+
+	// the interaction is served once
+	req, _ = http.NewRequest(http.MethodPost, "https://console.neon.tech/api/v2/projects?foo=bar",
+		bytes.NewReader([]byte(`{"name":"baz"}`)))
+	_, err = replay.Do(req)
+	assert.Error(t, err)
+}
+
+func TestRecorder_replayMatchesRequest(t *testing.T) {
+	cassette := filepath.Join(t.TempDir(), "foo.json")
+
+	// GIVEN the interactions which differ by the request's body
+	live := httpClientFn(func(r *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(r.Body)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(b))}, nil
+	})
+	rec, err := New(ModeRecord, cassette, live)
+	assert.NoError(t, err)
+	for _, body := range []string{`{"name":"foo"}`, `{"name":"bar"}`} {
+		req, _ := http.NewRequest(http.MethodPost, "https://console.neon.tech/api/v2/projects",
+			strings.NewReader(body))
+		_, err := rec.Do(req)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, rec.Save())
+
+	replay, err := New(ModeReplay, cassette, nil)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		url     string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "replays the interaction out of order",
+			url:  "/api/v2/projects", body: `{"name":"bar"}`, want: `{"name":"bar"}`,
+		},
+		{name: "fails given the different body", url: "/api/v2/projects", body: `{"name":"qux"}`, wantErr: true},
+		{name: "fails given the different query", url: "/api/v2/projects?foo=bar", body: `{"name":"foo"}`, wantErr: true},
+		{name: "replays the remaining interaction", url: "/api/v2/projects", body: `{"name":"foo"}`, want: `{"name":"foo"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// WHEN
+			req, _ := http.NewRequest(http.MethodPost, "https://console.neon.tech"+tt.url, strings.NewReader(tt.body))
+			resp, err := replay.Do(req)
+
+			// THEN
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				b, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.want, string(b))
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(ModeRecord, "foo.json", nil)
+	assert.Error(t, err, "the client is required to record")
+
+	_, err = New(ModeReplay, filepath.Join(t.TempDir(), "missing.json"), nil)
+	assert.Error(t, err, "the cassette is required to replay")
+
+	_, err = New("foo", "foo.json", nil)
+	assert.Error(t, err, "unknown mode")
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"flag"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/kislerdm/terraform-provider-neon/internal/provider"
 )
@@ -35,8 +35,8 @@ func main() {
 
 		ProviderAddr: "registry.terraform.io/" + provider.Name,
 
-		ProviderFunc: func() *schema.Provider {
-			return provider.New(version)
+		GRPCProviderFunc: func() tfprotov5.ProviderServer {
+			return provider.NewProviderServer(version)
 		},
 	}
 